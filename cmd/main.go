@@ -3,70 +3,127 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"log"
-	"os"
+	"strings"
+	"trip-plan-service/internal/backfill"
+	"trip-plan-service/internal/cleanup"
 	"trip-plan-service/internal/client"
+	"trip-plan-service/internal/config"
+	"trip-plan-service/internal/distance"
+	"trip-plan-service/internal/fallback"
+	"trip-plan-service/internal/geocode"
 	"trip-plan-service/internal/handler"
+	"trip-plan-service/internal/openapi"
 	"trip-plan-service/internal/routes"
 
 	_ "github.com/lib/pq"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 func main() {
-	// Değişkenler artık .env dosyasından env_file ile container'a doğru şekilde aktarılacak
-	user := os.Getenv("DB_USERNAME")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_DATABASE")
-	host := os.Getenv("DB_HOST") // Değeri: "trip-location-service-psql"
-	port := os.Getenv("DB_PORT") // Değeri: "5432"
-	appPort := os.Getenv("PORT") // Değeri: "8085"
-	aiServiceAddr := os.Getenv("AI_SERVICE_ADDR")
+	// Değişkenler .env dosyasından env_file ile container'a aktarılır,
+	// isteğe bağlı olarak CONFIG_FILE ile bir dosyadan da okunabilir.
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Konfigürasyon hatası: %v", err)
+	}
+	log.Printf("Etkin konfigürasyon:\n%s", cfg)
 
 	app := fiber.New()
 
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:3000",
+		AllowOrigins:     strings.Join(cfg.CORS.AllowOrigins, ","),
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
 		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
 		AllowCredentials: true,
 	}))
 
-	// Bağlantı dizesi artık doğru değerlerle oluşturulacak
-	dburl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		user, password, host, port, dbname)
+	if cfg.Features.Enabled("request_logging") {
+		app.Use(logger.New())
+	}
 
-	db, err := sql.Open("postgres", dburl)
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("OpenAPI dokümanı yüklenemedi: %v", err)
+	}
+	validateResponses := cfg.AppEnv == "test" || cfg.Features.Enabled("openapi_response_validation")
+	if cfg.Features.Enabled("openapi_validation") || validateResponses {
+		app.Use(spec.Middleware(openapi.Options{
+			ValidateRequests:  cfg.Features.Enabled("openapi_validation"),
+			ValidateResponses: validateResponses,
+		}))
+	}
+
+	db, err := sql.Open("postgres", cfg.DB.DSN())
 	if err != nil {
 		log.Fatalf("Veritabanı bağlantı hatası: %v", err)
 	}
 	defer db.Close()
 
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
+
 	// Bağlantıyı doğrulamak için ping atın
 	if err = db.Ping(); err != nil {
 		log.Fatalf("Veritabanına ping atılamadı: %v", err)
 	}
 	log.Println("Veritabanı bağlantısı başarıyla sağlandı!")
 
-	aiClient, err := client.NewAIClient(aiServiceAddr)
+	if cfg.Features.Enabled("location_gc") {
+		cleanup.Start(context.Background(), db, cfg.LocationGC.Interval, cleanup.Options{
+			GracePeriod: cfg.LocationGC.GracePeriod,
+			BatchSize:   cfg.LocationGC.BatchSize,
+		})
+		log.Printf("🧹 Sahipsiz lokasyon temizliği her %s çalışacak", cfg.LocationGC.Interval)
+	}
+
+	// Sınır veri seti ilk kayıtta değil, burada yüklenir; bozuksa sunucu açılmaz.
+	boundaries, err := geocode.Default()
+	if err != nil {
+		log.Fatalf("Sınır veri seti yüklenemedi: %v", err)
+	}
+	if cfg.Features.Enabled("location_geocode_backfill") {
+		backfill.Start(context.Background(), db, backfill.Options{BatchSize: 500})
+	}
+
+	aiClient, err := client.NewAIClient(cfg.AI)
 	if err != nil {
 		log.Fatalf("AI istemcisi oluşturulamadı: %v", err)
 	}
 	defer aiClient.Close()
 
-	tripHandler := handler.NewTripHandler(db, aiClient)
-	routes.TripRoutes(app, tripHandler)
+	var fallbackPlanner client.Planner
+	if cfg.Features.Enabled("fallback_planner") {
+		planner, err := fallback.NewPlanner()
+		if err != nil {
+			log.Fatalf("Yedek planlayıcı oluşturulamadı: %v", err)
+		}
+		fallbackPlanner = planner
+	}
 
-	if appPort == "" {
-		appPort = "8085" // Ortam değişkeni yoksa varsayılan port
+	tripHandler := handler.NewTripHandler(db, aiClient, fallbackPlanner)
+	tripHandler.PublicBaseURL = cfg.PublicBaseURL
+	tripHandler.Travel = distance.Profiles{DefaultMode: cfg.Travel.DefaultMode, Speeds: cfg.Travel.Speeds}
+
+	var geocoder geocode.Geocoder
+	if cfg.Geocoder.URL != "" {
+		geocoder = geocode.NewHTTPGeocoder(cfg.Geocoder.URL, cfg.Geocoder.UserAgent, cfg.Geocoder.Timeout)
+		log.Printf("🗺️ Geocoder: %s (yedek: gömülü yer listesi)", cfg.Geocoder.URL)
 	}
+	tripHandler.Geocoder = geocode.WithFallback(geocoder, geocode.NewGazetteer(boundaries))
+	routes.TripRoutes(app, tripHandler)
+	routes.LocationRoutes(app, tripHandler)
+	routes.AdminRoutes(app, tripHandler, cfg.AdminToken)
+	routes.DocsRoutes(app, spec)
 
-	log.Printf("Sunucu :%s portunda dinleniyor...", appPort)
-	if err := app.Listen(":" + appPort); err != nil {
+	log.Printf("Sunucu :%s portunda dinleniyor...", cfg.Port)
+	if err := app.Listen(":" + cfg.Port); err != nil {
 		log.Fatalf("Sunucu başlatılamadı: %v", err)
 	}
 }
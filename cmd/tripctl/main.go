@@ -0,0 +1,187 @@
+// tripctl - veritabanı üzerinde yönetim işlemleri için komut satırı aracı
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"trip-plan-service/internal/backfill"
+	"trip-plan-service/internal/cleanup"
+	"trip-plan-service/internal/config"
+	"trip-plan-service/internal/service"
+
+	_ "github.com/lib/pq"
+)
+
+const usage = `Kullanım:
+  tripctl account export [-o dosya] <user_id>   Kullanıcının tüm verisini JSON olarak yazar
+  tripctl account delete [-yes] <user_id>       Kullanıcının tüm verisini siler
+  tripctl locations gc [-grace 24h] [-batch 500] [-dry-run]
+                                                Hiçbir trip'te kullanılmayan lokasyonları siler
+  tripctl locations geocode [-all] [-batch 500]  Lokasyonlara ülke/bölge etiketi yazar
+
+Veritabanı ayarları sunucuyla aynı ortam değişkenlerinden (DB_*, CONFIG_FILE) okunur.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] + " " + os.Args[2] {
+	case "account export":
+		accountExport(os.Args[3:])
+	case "account delete":
+		accountDelete(os.Args[3:])
+	case "locations gc":
+		locationsGC(os.Args[3:])
+	case "locations geocode":
+		locationsGeocode(os.Args[3:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func accountExport(args []string) {
+	flags := flag.NewFlagSet("account export", flag.ExitOnError)
+	output := flags.String("o", "", "çıktı dosyası (varsayılan stdout)")
+	flags.Parse(args)
+	userID := requireUserID(flags)
+
+	tripService := service.NewTripService(nil, openDB(), nil)
+	archive, err := tripService.ExportAccount(context.Background(), userID)
+	if err != nil {
+		log.Fatalf("Export başarısız: %v", err)
+	}
+
+	body, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		log.Fatalf("Export başarısız: %v", err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(append(body, '\n'))
+		return
+	}
+	if err := os.WriteFile(*output, body, 0o600); err != nil {
+		log.Fatalf("Dosya yazılamadı: %v", err)
+	}
+	log.Printf("%s kullanıcısının %d trip'i %s dosyasına yazıldı", userID, len(archive.Trips), *output)
+}
+
+func accountDelete(args []string) {
+	flags := flag.NewFlagSet("account delete", flag.ExitOnError)
+	yes := flags.Bool("yes", false, "onay sormadan sil")
+	flags.Parse(args)
+	userID := requireUserID(flags)
+
+	if !*yes && !confirm(fmt.Sprintf("%s kullanıcısının tüm verisi kalıcı olarak silinecek. Devam edilsin mi? [e/H] ", userID)) {
+		log.Fatal("İptal edildi")
+	}
+
+	tripService := service.NewTripService(nil, openDB(), nil)
+	report, err := tripService.DeleteAccount(context.Background(), userID)
+	if report != nil {
+		body, _ := json.MarshalIndent(report, "", "  ")
+		os.Stdout.Write(append(body, '\n'))
+	}
+	if err != nil {
+		log.Fatalf("Silme başarısız: %v", err)
+	}
+}
+
+func locationsGC(args []string) {
+	flags := flag.NewFlagSet("locations gc", flag.ExitOnError)
+	grace := flags.Duration("grace", 24*time.Hour, "bu süreden yeni lokasyonlara dokunma")
+	batch := flags.Int("batch", 500, "tek seferde silinecek en fazla lokasyon")
+	dryRun := flags.Bool("dry-run", false, "silmeden sadece say")
+	flags.Parse(args)
+
+	if *grace < 0 || *batch < 1 || flags.NArg() != 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	report, err := cleanup.CollectOrphanedLocations(context.Background(), openDB(), cleanup.Options{
+		GracePeriod: *grace,
+		BatchSize:   *batch,
+		DryRun:      *dryRun,
+	})
+	if report != nil {
+		body, _ := json.MarshalIndent(report, "", "  ")
+		os.Stdout.Write(append(body, '\n'))
+	}
+	if err != nil {
+		log.Fatalf("Temizlik başarısız: %v", err)
+	}
+}
+
+func locationsGeocode(args []string) {
+	flags := flag.NewFlagSet("locations geocode", flag.ExitOnError)
+	all := flags.Bool("all", false, "daha önce etiketlenmiş lokasyonları da yeniden etiketle")
+	batch := flags.Int("batch", 500, "tek seferde okunacak en fazla lokasyon")
+	flags.Parse(args)
+
+	if *batch < 1 || flags.NArg() != 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	report, err := backfill.GeocodeLocations(context.Background(), openDB(), backfill.Options{
+		BatchSize: *batch,
+		All:       *all,
+	})
+	if report != nil {
+		body, _ := json.MarshalIndent(report, "", "  ")
+		os.Stdout.Write(append(body, '\n'))
+	}
+	if err != nil {
+		log.Fatalf("Etiketleme başarısız: %v", err)
+	}
+}
+
+func requireUserID(flags *flag.FlagSet) string {
+	if flags.NArg() != 1 || strings.TrimSpace(flags.Arg(0)) == "" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	return flags.Arg(0)
+}
+
+func confirm(prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "e", "evet", "y", "yes":
+		return true
+	}
+	return false
+}
+
+func openDB() *sql.DB {
+	cfg, err := config.LoadDB()
+	if err != nil {
+		log.Fatalf("Konfigürasyon hatası: %v", err)
+	}
+
+	db, err := sql.Open("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Veritabanı bağlantı hatası: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Veritabanına ping atılamadı: %v", err)
+	}
+	return db
+}
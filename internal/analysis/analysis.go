@@ -0,0 +1,266 @@
+// internal/analysis/analysis.go for trip-plan-service
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"trip-plan-service/internal/distance"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+	"trip-plan-service/internal/optimizer"
+)
+
+// Uyarı kodları
+const (
+	CodeLongDrive         = "long_drive"
+	CodeOverloadedDay     = "overloaded_day"
+	CodeBacktracking      = "backtracking"
+	CodeDuplicateLocation = "duplicate_location"
+	CodeEmptyDay          = "empty_day"
+)
+
+// MaxDays analiz edilebilecek en uzun trip'tir; gün listesi tarihlerden
+// üretildiği için /save'in sınırlamadığı uzun aralıklar burada durdurulur.
+const MaxDays = 366
+
+// ErrTooManyDays trip MaxDays'ten uzunsa döner.
+var ErrTooManyDays = errors.New("analysis: trip has too many days")
+
+// Genel puandaki ağırlıklar (toplam 100).
+const (
+	weightDailyDistance = 30
+	weightBalance       = 20
+	weightBacktracking  = 25
+	weightDuplicates    = 10
+	weightEmptyDays     = 15
+)
+
+// Limits bir planın hangi noktadan sonra sorunlu sayılacağını belirler.
+type Limits struct {
+	// MaxDailyMinutes bir günde yolda geçirilebilecek en uzun süredir.
+	MaxDailyMinutes int
+	// MaxStopsPerDay bir güne sığabilecek en fazla durak sayısıdır.
+	MaxStopsPerDay int
+	// BacktrackRatio günün rotası en kısa sıralamadan bu oranda uzunsa
+	// kendi üzerine dönüyor sayılır.
+	BacktrackRatio float64
+	// DuplicateKm bu mesafeden yakın iki durak aynı yer sayılır.
+	DuplicateKm float64
+}
+
+// DefaultLimits araba ile yapılan, günde birkaç duraklı bir gezi içindir.
+var DefaultLimits = Limits{
+	MaxDailyMinutes: 6 * 60,
+	MaxStopsPerDay:  8,
+	BacktrackRatio:  1.25,
+	DuplicateKm:     0.1,
+}
+
+// Analyze planı günlük yol süresi, günlere düşen durak dengesi, geri dönüşler,
+// tekrarlanan lokasyonlar ve boş günler açısından puanlar. Yol süreleri
+// trip.Distances'tan okunur; boşsa varsayılan ulaşım türüyle hesaplanır.
+// Trip MaxDays'ten uzunsa ErrTooManyDays döner.
+func (l Limits) Analyze(trip *models.TripWithLocations) (*models.PlanAnalysis, error) {
+	days := trip.Trip.TotalDays
+	for _, loc := range trip.Locations {
+		if loc.Day > days {
+			days = loc.Day
+		}
+	}
+	if days > MaxDays {
+		return nil, fmt.Errorf("%w: %d, at most %d can be analysed", ErrTooManyDays, days, MaxDays)
+	}
+
+	distances := trip.Distances
+	if distances == nil {
+		var err error
+		distances, err = distance.DefaultProfiles.Compute(trip.Locations, "")
+		if err != nil {
+			return nil, fmt.Errorf("analysis: failed to compute distances: %w", err)
+		}
+	}
+
+	result := &models.PlanAnalysis{
+		Days:     []models.DayAnalysis{},
+		Warnings: []models.PlanWarning{},
+	}
+
+	start, startErr := time.Parse("2006-01-02", trip.Trip.StartDate)
+	byDay := make([][]models.Location, days+1)
+	for _, loc := range trip.Locations {
+		if loc.Day > 0 {
+			byDay[loc.Day] = append(byDay[loc.Day], loc)
+		}
+	}
+
+	for day := 1; day <= days; day++ {
+		entry := models.DayAnalysis{Day: day, Stops: len(byDay[day]), Warnings: []string{}}
+		if startErr == nil {
+			entry.Date = start.AddDate(0, 0, day-1).Format("2006-01-02")
+		}
+		for _, d := range distances.Days {
+			if d.Day == day {
+				entry.DistanceKm, entry.DurationMinutes = d.DistanceKm, d.DurationMinutes
+			}
+		}
+		result.Days = append(result.Days, entry)
+	}
+
+	warn := func(code string, day int, format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		result.Warnings = append(result.Warnings, models.PlanWarning{Code: code, Day: day, Message: message})
+		if day > 0 {
+			result.Days[day-1].Warnings = append(result.Days[day-1].Warnings, message)
+		}
+	}
+
+	result.Scores = models.PlanScores{
+		DailyDistance: l.scoreDailyDistance(result.Days, warn),
+		Balance:       l.scoreBalance(result.Days, warn),
+		Backtracking:  l.scoreBacktracking(byDay, warn),
+		Duplicates:    l.scoreDuplicates(trip.Locations, warn),
+		EmptyDays:     scoreEmptyDays(result.Days, warn),
+	}
+
+	result.Score = int(math.Round(float64(
+		result.Scores.DailyDistance*weightDailyDistance+
+			result.Scores.Balance*weightBalance+
+			result.Scores.Backtracking*weightBacktracking+
+			result.Scores.Duplicates*weightDuplicates+
+			result.Scores.EmptyDays*weightEmptyDays) / 100))
+	return result, nil
+}
+
+type warnFunc func(code string, day int, format string, args ...interface{})
+
+// scoreDailyDistance limiti aşan her günü limit/süre oranında cezalandırır.
+func (l Limits) scoreDailyDistance(days []models.DayAnalysis, warn warnFunc) int {
+	if len(days) == 0 {
+		return 100
+	}
+
+	total := 0.0
+	for _, day := range days {
+		if day.DurationMinutes <= l.MaxDailyMinutes {
+			total++
+			continue
+		}
+		total += float64(l.MaxDailyMinutes) / float64(day.DurationMinutes)
+		warn(CodeLongDrive, day.Day, "day %d has about %s of travel (%.0f km), more than the recommended %s",
+			day.Day, formatMinutes(day.DurationMinutes), day.DistanceKm, formatMinutes(l.MaxDailyMinutes))
+	}
+	return percent(total / float64(len(days)))
+}
+
+// scoreBalance günlük durak sayılarının değişim katsayısına göre puan verir.
+func (l Limits) scoreBalance(days []models.DayAnalysis, warn warnFunc) int {
+	stops := 0
+	for _, day := range days {
+		stops += day.Stops
+	}
+	if stops == 0 {
+		return 100
+	}
+
+	mean := float64(stops) / float64(len(days))
+	variance := 0.0
+	for _, day := range days {
+		variance += math.Pow(float64(day.Stops)-mean, 2)
+	}
+	cv := math.Sqrt(variance/float64(len(days))) / mean
+
+	for _, day := range days {
+		switch {
+		case day.Stops > l.MaxStopsPerDay:
+			warn(CodeOverloadedDay, day.Day, "day %d has %d stops, more than the recommended %d", day.Day, day.Stops, l.MaxStopsPerDay)
+		case mean >= 1 && float64(day.Stops) >= 2*mean+1:
+			warn(CodeOverloadedDay, day.Day, "day %d has %d stops while the average is %.1f", day.Day, day.Stops, mean)
+		}
+	}
+	return percent(1 - cv)
+}
+
+// scoreBacktracking her günün rotasını, ilk ve son durağı aynı kalacak
+// şekilde bulunabilen en kısa sıralamayla karşılaştırır.
+func (l Limits) scoreBacktracking(byDay [][]models.Location, warn warnFunc) int {
+	before, after := 0.0, 0.0
+	for day, locations := range byDay {
+		if len(locations) < 4 {
+			continue
+		}
+		result, err := optimizer.Optimize(locations, optimizer.Options{})
+		if err != nil {
+			continue
+		}
+		before += result.BeforeKm
+		after += result.AfterKm
+
+		if result.BeforeKm > result.AfterKm*l.BacktrackRatio && result.BeforeKm-result.AfterKm >= 1 {
+			warn(CodeBacktracking, day, "day %d doubles back on itself, reordering its stops would save about %.0f km",
+				day, result.BeforeKm-result.AfterKm)
+		}
+	}
+	if before == 0 {
+		return 100
+	}
+	return percent(after / before)
+}
+
+// scoreDuplicates aynı adlı ya da neredeyse aynı konumdaki durakları bulur.
+func (l Limits) scoreDuplicates(locations []models.Location, warn warnFunc) int {
+	if len(locations) == 0 {
+		return 100
+	}
+
+	duplicates := 0
+	for i := range locations {
+		for j := 0; j < i; j++ {
+			if !l.sameLocation(locations[i], locations[j]) {
+				continue
+			}
+			duplicates++
+			warn(CodeDuplicateLocation, locations[i].Day, "%q appears more than once (days %d and %d)",
+				locations[i].Name, locations[j].Day, locations[i].Day)
+			break
+		}
+	}
+	return percent(1 - float64(duplicates)/float64(len(locations)))
+}
+
+func (l Limits) sameLocation(a, b models.Location) bool {
+	if name := geo.NormalizeName(a.Name); name != "" && name == geo.NormalizeName(b.Name) {
+		return true
+	}
+	pa := geo.Point{Latitude: a.Latitude, Longitude: a.Longitude}
+	pb := geo.Point{Latitude: b.Latitude, Longitude: b.Longitude}
+	if pa.IsZero() || pb.IsZero() {
+		return false
+	}
+	return geo.HaversineKm(pa, pb) <= l.DuplicateKm
+}
+
+func scoreEmptyDays(days []models.DayAnalysis, warn warnFunc) int {
+	if len(days) == 0 {
+		return 100
+	}
+
+	empty := 0
+	for _, day := range days {
+		if day.Stops == 0 {
+			empty++
+			warn(CodeEmptyDay, day.Day, "day %d has no stops", day.Day)
+		}
+	}
+	return percent(1 - float64(empty)/float64(len(days)))
+}
+
+func percent(ratio float64) int {
+	return int(math.Round(100 * math.Max(0, math.Min(1, ratio))))
+}
+
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%dh%02d", minutes/60, minutes%60)
+}
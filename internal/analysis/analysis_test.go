@@ -0,0 +1,196 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/distance"
+	"trip-plan-service/internal/models"
+)
+
+func stop(name string, day int, lat, lon float64) models.Location {
+	return models.Location{Name: name, Day: day, Latitude: lat, Longitude: lon}
+}
+
+func testTrip(totalDays int, locations ...models.Location) *models.TripWithLocations {
+	return &models.TripWithLocations{
+		Trip:      models.Trip{StartDate: "2026-06-01", TotalDays: totalDays},
+		Locations: locations,
+	}
+}
+
+// warningCodes uyarıları "kod@gün" olarak sırasıyla döner.
+func warningCodes(result *models.PlanAnalysis) []string {
+	codes := []string{}
+	for _, warning := range result.Warnings {
+		codes = append(codes, fmt.Sprintf("%s@%d", warning.Code, warning.Day))
+	}
+	return codes
+}
+
+// Aynı enlemde boylamda bir derece ~88 km'dir.
+func TestAnalyzeWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		trip   *models.TripWithLocations
+		codes  []string
+		scores models.PlanScores
+	}{
+		{
+			name: "sorunsuz plan",
+			trip: testTrip(2,
+				stop("A", 1, 38, 27), stop("B", 1, 38, 27.5),
+				stop("C", 2, 38, 28), stop("D", 2, 38, 28.5)),
+			codes:  []string{},
+			scores: models.PlanScores{DailyDistance: 100, Balance: 100, Backtracking: 100, Duplicates: 100, EmptyDays: 100},
+		},
+		{
+			name: "uzun yol günü",
+			trip: func() *models.TripWithLocations {
+				trip := testTrip(2, stop("A", 1, 38, 27), stop("B", 2, 38, 28))
+				trip.Distances = &models.TripDistances{Days: []models.DayDistance{
+					{Day: 1, DistanceKm: 640, DurationMinutes: 480},
+				}}
+				return trip
+			}(),
+			codes: []string{CodeLongDrive + "@1"},
+			// (360/480 + 1) / 2
+			scores: models.PlanScores{DailyDistance: 88, Balance: 100, Backtracking: 100, Duplicates: 100, EmptyDays: 100},
+		},
+		{
+			name: "ortalamanın çok üstünde duraklı gün",
+			trip: testTrip(4,
+				stop("A", 1, 38, 27), stop("B", 1, 38, 27.1), stop("C", 1, 38, 27.2), stop("D", 1, 38, 27.3), stop("E", 1, 38, 27.4),
+				stop("F", 2, 38, 27.5), stop("G", 3, 38, 27.6), stop("H", 4, 38, 27.7)),
+			codes:  []string{CodeOverloadedDay + "@1"},
+			scores: models.PlanScores{DailyDistance: 100, Balance: 13, Backtracking: 100, Duplicates: 100, EmptyDays: 100},
+		},
+		{
+			name: "kendi üzerine dönen gün",
+			trip: testTrip(1,
+				stop("A", 1, 38, 27), stop("C", 1, 38, 29), stop("B", 1, 38, 28), stop("D", 1, 38, 30)),
+			codes: []string{CodeBacktracking + "@1"},
+			// A-B-C-D / A-C-B-D = 3/5
+			scores: models.PlanScores{DailyDistance: 100, Balance: 100, Backtracking: 60, Duplicates: 100, EmptyDays: 100},
+		},
+		{
+			name: "aynı adlı ve aynı konumdaki duraklar",
+			trip: testTrip(2,
+				stop("Efes", 1, 37.9395, 27.3417), stop("Meryem Ana Evi", 1, 37.9116, 27.3340),
+				stop("EFES", 2, 0, 0), stop("Efes Antik Kenti", 2, 37.93951, 27.34171)),
+			codes:  []string{CodeDuplicateLocation + "@2", CodeDuplicateLocation + "@2"},
+			scores: models.PlanScores{DailyDistance: 100, Balance: 100, Backtracking: 100, Duplicates: 50, EmptyDays: 100},
+		},
+		{
+			name:   "boş gün",
+			trip:   testTrip(3, stop("A", 1, 38, 27), stop("B", 3, 38, 27.5)),
+			codes:  []string{CodeEmptyDay + "@2"},
+			scores: models.PlanScores{DailyDistance: 100, Balance: 29, Backtracking: 100, Duplicates: 100, EmptyDays: 67},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DefaultLimits.Analyze(tt.trip)
+			if err != nil {
+				t.Fatalf("Analyze: %v", err)
+			}
+			if got := warningCodes(result); !reflect.DeepEqual(got, tt.codes) {
+				t.Errorf("warnings = %v, want %v (%+v)", got, tt.codes, result.Warnings)
+			}
+			if result.Scores != tt.scores {
+				t.Errorf("scores = %+v, want %+v", result.Scores, tt.scores)
+			}
+		})
+	}
+}
+
+// Limit aşan durak sayısı ortalamadan bağımsız olarak uyarılır.
+func TestAnalyzeMaxStopsPerDay(t *testing.T) {
+	limits := DefaultLimits
+	limits.MaxStopsPerDay = 2
+
+	result, err := limits.Analyze(testTrip(2,
+		stop("A", 1, 38, 27), stop("B", 1, 38, 27.1), stop("C", 1, 38, 27.2),
+		stop("D", 2, 38, 27.3), stop("E", 2, 38, 27.4)))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if got, want := warningCodes(result), []string{CodeOverloadedDay + "@1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("warnings = %v, want %v", got, want)
+	}
+	if msg := result.Warnings[0].Message; !strings.Contains(msg, "more than the recommended 2") {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+// Uyarılar gün kaydına da eklenir; günlere tarih ve mesafe yazılır.
+func TestAnalyzeDays(t *testing.T) {
+	result, err := DefaultLimits.Analyze(testTrip(3, stop("A", 1, 38, 27), stop("B", 3, 38, 28)))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	if len(result.Days) != 3 {
+		t.Fatalf("days = %+v, want 3", result.Days)
+	}
+	if day := result.Days[1]; day.Date != "2026-06-02" || day.Stops != 0 || len(day.Warnings) != 1 {
+		t.Errorf("day 2 = %+v, want an empty day with one warning", day)
+	}
+	if day := result.Days[2]; day.DistanceKm != 87.6 || day.DurationMinutes != 66 {
+		t.Errorf("day 3 = %+v, want 87.6 km and 66 minutes by car", day)
+	}
+}
+
+// Genel puan kriter puanlarının ağırlıklı ortalamasıdır:
+// (100*30 + 29*20 + 100*25 + 100*10 + 67*15) / 100 = 80.85.
+func TestAnalyzeWeightedScore(t *testing.T) {
+	result, err := DefaultLimits.Analyze(testTrip(3, stop("A", 1, 38, 27), stop("B", 3, 38, 27.5)))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if result.Score != 81 {
+		t.Errorf("score = %d, want 81 (scores %+v)", result.Score, result.Scores)
+	}
+
+	clean, err := DefaultLimits.Analyze(testTrip(1, stop("A", 1, 38, 27)))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if clean.Score != 100 {
+		t.Errorf("score = %d, want 100 for a single stop", clean.Score)
+	}
+}
+
+func TestAnalyzeTooManyDays(t *testing.T) {
+	tests := map[string]*models.TripWithLocations{
+		"uzun tarih aralığı": testTrip(MaxDays+1, stop("A", 1, 38, 27)),
+		"uzak gün numarası":  testTrip(3, stop("A", 1, 38, 27), stop("B", 100000, 38, 28)),
+	}
+	for name, trip := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DefaultLimits.Analyze(trip); !errors.Is(err, ErrTooManyDays) {
+				t.Errorf("err = %v, want ErrTooManyDays", err)
+			}
+		})
+	}
+
+	if _, err := DefaultLimits.Analyze(testTrip(MaxDays, stop("A", 1, 38, 27))); err != nil {
+		t.Errorf("Analyze with %d days: %v", MaxDays, err)
+	}
+}
+
+// Mesafeler hesaplanamazsa hata yutulmadan dönmeli.
+func TestAnalyzeReportsDistanceError(t *testing.T) {
+	defaults := distance.DefaultProfiles
+	t.Cleanup(func() { distance.DefaultProfiles = defaults })
+	distance.DefaultProfiles = distance.Profiles{DefaultMode: "car", Speeds: map[string]float64{}}
+
+	var unknown *distance.UnknownModeError
+	if _, err := DefaultLimits.Analyze(testTrip(1, stop("A", 1, 38, 27))); !errors.As(err, &unknown) {
+		t.Errorf("err = %v, want *distance.UnknownModeError", err)
+	}
+}
@@ -0,0 +1,143 @@
+// internal/backfill/regions.go for trip-plan-service
+package backfill
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"log"
+	"strconv"
+	"time"
+
+	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/geocode"
+)
+
+// metrics /api/v1/admin/debug/vars altında "location_geocode" olarak yayınlanır.
+var metrics = expvar.NewMap("location_geocode")
+
+// Options lokasyonların ülke/bölge etiketlemesini yönlendirir.
+type Options struct {
+	// BatchSize tek sorguyla okunacak en fazla lokasyon sayısıdır.
+	BatchSize int
+	// All true ise daha önce etiketlenmiş lokasyonlar da yeniden etiketlenir
+	// (ör. sınır veri seti güncellendiğinde).
+	All bool
+}
+
+// Report bir etiketleme çalışmasının sonucudur.
+type Report struct {
+	All     bool  `json:"all"`
+	Scanned int64 `json:"scanned"`
+	// Tagged bir ülkeye atanan lokasyon sayısıdır.
+	Tagged int64 `json:"tagged"`
+	// Unmatched veri setinin kapsamadığı ya da koordinatı okunamayan
+	// lokasyonlardır; etiketsiz kalırlar ve sonraki çalışmalarda yeniden
+	// denenirler.
+	Unmatched  int64 `json:"unmatched"`
+	Batches    int   `json:"batches"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// GeocodeLocations koordinatı olan lokasyonları ID sırasıyla BatchSize'lık
+// gruplar halinde okur ve her birine gömülü veri setinden bulunan ülke ve
+// bölgeyi yazar. Ülkesi bulunamayan lokasyonlar her çalışmada yeniden
+// denenir; geocoded_at yalnızca son denemenin zamanıdır. Her satır ayrı güncellenir; iş yarıda kesilirse bir sonraki
+// çalışma kalan satırlardan devam eder.
+func GeocodeLocations(ctx context.Context, conn *sql.DB, opts Options) (*Report, error) {
+	q := db.New(conn)
+	started := time.Now()
+	report := &Report{All: opts.All}
+
+	err := func() error {
+		var afterID int32
+		for {
+			rows, err := q.ListLocationsToGeocode(ctx, db.ListLocationsToGeocodeParams{
+				AfterID:   afterID,
+				AllRows:   opts.All,
+				BatchSize: int32(opts.BatchSize),
+			})
+			if err != nil {
+				return err
+			}
+			report.Batches++
+
+			for _, row := range rows {
+				afterID = row.ID
+				report.Scanned++
+
+				region, ok := lookup(row)
+				_, err := q.SetLocationRegion(ctx, db.SetLocationRegionParams{
+					CountryCode: sql.NullString{String: region.CountryCode, Valid: ok},
+					AdminRegion: sql.NullString{String: region.AdminRegion, Valid: region.AdminRegion != ""},
+					ID:          row.ID,
+				})
+				if err != nil {
+					return err
+				}
+				if ok {
+					report.Tagged++
+					metrics.Add("tagged_total", 1)
+				} else {
+					report.Unmatched++
+				}
+			}
+
+			if len(rows) < opts.BatchSize {
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}()
+
+	report.DurationMs = time.Since(started).Milliseconds()
+	record(report, err)
+	return report, err
+}
+
+// Start etiketlemeyi arka planda bir kez çalıştırır. Yeni lokasyonlar
+// kayıt sırasında etiketlendiği için periyodik çalışmaya gerek yoktur;
+// eşleşmeyenler bir sonraki açılışta yeniden denenir.
+func Start(ctx context.Context, conn *sql.DB, opts Options) {
+	go func() {
+		report, err := GeocodeLocations(ctx, conn, opts)
+		if err != nil {
+			log.Printf("❌ Lokasyon etiketleme hatası: %v (etiketlenen: %d)", err, report.Tagged)
+			return
+		}
+		if report.Scanned > 0 {
+			log.Printf("🌍 Lokasyon etiketleme: %d lokasyon tarandı, %d etiketlendi, %d eşleşmedi (%d ms)",
+				report.Scanned, report.Tagged, report.Unmatched, report.DurationMs)
+		}
+	}()
+}
+
+func lookup(row db.ListLocationsToGeocodeRow) (geocode.Region, bool) {
+	lat, errLat := strconv.ParseFloat(row.Latitude.String, 64)
+	lon, errLon := strconv.ParseFloat(row.Longitude.String, 64)
+	if errLat != nil || errLon != nil {
+		return geocode.Region{}, false
+	}
+	return geocode.Lookup(geo.Point{Latitude: lat, Longitude: lon})
+}
+
+func record(report *Report, err error) {
+	metrics.Add("runs_total", 1)
+	if err != nil {
+		metrics.Add("failures_total", 1)
+	}
+
+	last := func(key string, value int64) {
+		v := new(expvar.Int)
+		v.Set(value)
+		metrics.Set(key, v)
+	}
+	last("last_run_unix", time.Now().Unix())
+	last("last_scanned", report.Scanned)
+	last("last_tagged", report.Tagged)
+	last("last_unmatched", report.Unmatched)
+	last("last_duration_ms", report.DurationMs)
+}
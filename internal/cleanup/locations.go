@@ -0,0 +1,135 @@
+// internal/cleanup/locations.go for trip-plan-service
+package cleanup
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"log"
+	"time"
+
+	db "trip-plan-service/internal/db/postgresql"
+)
+
+// metrics /api/v1/admin/debug/vars altında "location_gc" olarak yayınlanır.
+var metrics = expvar.NewMap("location_gc")
+
+// Options sahipsiz lokasyon temizliğini yönlendirir.
+type Options struct {
+	// GracePeriod bu süreden yeni lokasyonlar sahipsiz olsa da silinmez.
+	GracePeriod time.Duration
+	// BatchSize tek DELETE ile silinecek en fazla satır sayısıdır.
+	BatchSize int
+	// DryRun true ise sadece sayılır, hiçbir şey silinmez.
+	DryRun bool
+}
+
+// Report bir temizlik çalışmasının sonucudur. Sayılar silmeden önceki
+// durumu gösterir.
+type Report struct {
+	DryRun      bool   `json:"dry_run"`
+	GracePeriod string `json:"grace_period"`
+	// Eligible silinebilecek sahipsiz lokasyon sayısıdır.
+	Eligible int64 `json:"eligible"`
+	// InGracePeriod sahipsiz ama henüz bekleme süresinde olanlardır.
+	InGracePeriod int64 `json:"in_grace_period"`
+	// InCatalogue sahipsiz ama katalog kaydı olduğu için korunanlardır.
+	InCatalogue int64 `json:"in_catalogue"`
+	Deleted     int64 `json:"deleted"`
+	Batches     int   `json:"batches"`
+	DurationMs  int64 `json:"duration_ms"`
+}
+
+// CollectOrphanedLocations hiçbir trip'e bağlı olmayan, bekleme süresini
+// doldurmuş ve katalogda olmayan lokasyonları BatchSize'lık gruplar halinde
+// siler. Her grup ayrı bir ifadedir; iş yarıda kesilirse silinenler silinmiş
+// kalır ve bir sonraki çalışma kaldığı yerden devam eder.
+func CollectOrphanedLocations(ctx context.Context, conn *sql.DB, opts Options) (*Report, error) {
+	q := db.New(conn)
+	started := time.Now()
+	report := &Report{DryRun: opts.DryRun, GracePeriod: opts.GracePeriod.String()}
+	graceSeconds := opts.GracePeriod.Seconds()
+
+	err := func() error {
+		stats, err := q.GetOrphanedLocationStats(ctx, graceSeconds)
+		if err != nil {
+			return err
+		}
+		report.Eligible = stats.Eligible
+		report.InGracePeriod = stats.InGracePeriod
+		report.InCatalogue = stats.InCatalogue
+
+		if opts.DryRun || report.Eligible == 0 {
+			return nil
+		}
+
+		for {
+			deleted, err := q.DeleteOrphanedLocationsBatch(ctx, db.DeleteOrphanedLocationsBatchParams{
+				GraceSeconds: graceSeconds,
+				BatchSize:    int32(opts.BatchSize),
+			})
+			if err != nil {
+				return err
+			}
+			report.Batches++
+			report.Deleted += deleted
+			metrics.Add("deleted_total", deleted)
+
+			if deleted < int64(opts.BatchSize) {
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}()
+
+	report.DurationMs = time.Since(started).Milliseconds()
+	record(report, err)
+	return report, err
+}
+
+// Start temizliği interval aralıklarla arka planda çalıştırır; ctx iptal
+// edilince durur. İlk çalışma bir interval sonra yapılır.
+func Start(ctx context.Context, conn *sql.DB, interval time.Duration, opts Options) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			report, err := CollectOrphanedLocations(ctx, conn, opts)
+			if err != nil {
+				log.Printf("❌ Lokasyon temizliği hatası: %v (silinen: %d)", err, report.Deleted)
+				continue
+			}
+			if report.Deleted > 0 {
+				log.Printf("🧹 Lokasyon temizliği: %d sahipsiz lokasyon silindi (%d grup, %d ms)", report.Deleted, report.Batches, report.DurationMs)
+			}
+		}
+	}()
+}
+
+func record(report *Report, err error) {
+	metrics.Add("runs_total", 1)
+	if err != nil {
+		metrics.Add("failures_total", 1)
+	}
+
+	last := func(key string, value int64) {
+		v := new(expvar.Int)
+		v.Set(value)
+		metrics.Set(key, v)
+	}
+	last("last_run_unix", time.Now().Unix())
+	last("last_eligible", report.Eligible)
+	last("last_in_grace_period", report.InGracePeriod)
+	last("last_in_catalogue", report.InCatalogue)
+	last("last_deleted", report.Deleted)
+	last("last_duration_ms", report.DurationMs)
+}
@@ -4,29 +4,50 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"trip-plan-service/internal/config"
+
 	"github.com/Semhumc/grpc-proto/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Planner trip planı üreten kaynakların ortak sözleşmesidir. AIClient ve
+// AI servisi yanıt vermediğinde kullanılan yedek planlayıcı bunu uygular.
+type Planner interface {
+	GenerateTripPlan(ctx context.Context, req *proto.PromptRequest) (*proto.TripOptionsResponse, error)
+}
+
 type AIClient struct {
-	client proto.AIServiceClient
-	conn   *grpc.ClientConn
+	client  proto.AIServiceClient
+	conn    *grpc.ClientConn
+	timeout time.Duration
 }
 
-func NewAIClient(serverAddress string) (*AIClient, error) {
-	conn, err := grpc.NewClient(serverAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewAIClient(cfg config.AIConfig) (*AIClient, error) {
+	creds, err := transportCredentials(cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure AI service TLS: %v", err)
+	}
+
+	target, opts := balancingOptions(cfg)
+	opts = append(opts, grpc.WithTransportCredentials(creds))
+	if cfg.AuthToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: cfg.AuthToken}))
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to AI service: %v", err)
 	}
 
 	client := proto.NewAIServiceClient(conn)
-	
+
 	return &AIClient{
-		client: client,
-		conn:   conn,
+		client:  client,
+		conn:    conn,
+		timeout: cfg.Timeout,
 	}, nil
 }
 
@@ -36,7 +57,7 @@ func (c *AIClient) Close() error {
 
 func (c *AIClient) GenerateTripPlan(ctx context.Context, req *proto.PromptRequest) (*proto.TripOptionsResponse, error) {
 	// Set timeout for the request
-	ctx, cancel := context.WithTimeout(ctx, 250*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	response, err := c.client.GeneratePlan(ctx, req)
@@ -47,15 +68,35 @@ func (c *AIClient) GenerateTripPlan(ctx context.Context, req *proto.PromptReques
 	return response, nil
 }
 
-// Helper function to convert internal models to proto
-func CreatePromptRequest(userID, name, description, startPos, endPos, startDate, endDate string) *proto.PromptRequest {
+// Helper function to convert internal models to proto. PromptRequest'te ara
+// nokta alanı olmadığı için waypoints açıklamanın sonuna talimat olarak eklenir.
+func CreatePromptRequest(userID, name, description, startPos, endPos, startDate, endDate string, waypoints ...string) *proto.PromptRequest {
 	return &proto.PromptRequest{
 		UserId:        userID,
 		Name:          name,
-		Description:   description,
+		Description:   withWaypoints(description, waypoints),
 		StartPosition: startPos,
 		EndPosition:   endPos,
 		StartDate:     startDate,
 		EndDate:       endDate,
 	}
-}
\ No newline at end of file
+}
+
+// withWaypoints ara noktaları AI'ın uyması gereken sıralı bir liste olarak
+// açıklamanın sonuna ekler.
+func withWaypoints(description string, waypoints []string) string {
+	if len(waypoints) == 0 {
+		return description
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(description))
+	if b.Len() > 0 {
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Zorunlu ara noktalar: rota başlangıç ile bitiş arasında aşağıdaki yerlere bu sırayla uğramalı ve her biri planda ayrı bir durak olarak yer almalıdır:")
+	for i, name := range waypoints {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, name)
+	}
+	return b.String()
+}
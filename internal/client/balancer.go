@@ -0,0 +1,284 @@
+// internal/client/balancer.go for trip-plan-service
+package client
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"trip-plan-service/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/health" // istemci tarafı health check'i kaydeder
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	roundRobinBalancer   = "trip_ai_round_robin"
+	leastRequestBalancer = "trip_ai_least_request"
+	staticScheme         = "trip-ai"
+)
+
+// backends tüm AI örneklerinin istek/hata sayılarını ve ejection durumunu
+// tutar. Serviste tek bir AIClient olduğu için paket seviyesinde yaşar.
+var backends = &backendTracker{states: map[string]*backendState{}}
+
+func init() {
+	// Örneklerin sağlık ve yük durumu /api/v1/admin/debug/vars altında
+	// "ai_backends" olarak görünür.
+	expvar.Publish("ai_backends", expvar.Func(func() interface{} { return backends.stats() }))
+
+	balancer.Register(base.NewBalancerBuilder(roundRobinBalancer, &pickerBuilder{}, base.Config{HealthCheck: true}))
+	balancer.Register(base.NewBalancerBuilder(leastRequestBalancer, &pickerBuilder{leastRequest: true}, base.Config{HealthCheck: true}))
+}
+
+// BackendStat bir AI örneğinin expvar'da yayınlanan anlık durumudur.
+type BackendStat struct {
+	Addr         string    `json:"addr"`
+	Ready        bool      `json:"ready"`
+	Outstanding  int64     `json:"outstanding"`
+	Requests     uint64    `json:"requests"`
+	Failures     uint64    `json:"failures"`
+	EjectedUntil time.Time `json:"ejected_until,omitempty"`
+}
+
+// balancingOptions AI_SERVICE_ADDR ve LB ayarlarına göre hedef adresi
+// ve gRPC seçeneklerini üretir.
+func balancingOptions(cfg config.AIConfig) (string, []grpc.DialOption) {
+	backends.configure(cfg.LB)
+
+	policy := roundRobinBalancer
+	if cfg.LB.Policy == config.LBLeastRequest {
+		policy = leastRequestBalancer
+	}
+
+	serviceConfig := fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]`, policy)
+	if cfg.LB.HealthCheck {
+		serviceConfig += `,"healthCheckConfig":{"serviceName":""}`
+	}
+	serviceConfig += "}"
+
+	opts := []grpc.DialOption{grpc.WithDefaultServiceConfig(serviceConfig)}
+
+	// Tek adres DNS (veya verilen resolver) ile çözülür; DNS birden fazla
+	// kayıt dönerse hepsine dağıtılır.
+	if len(cfg.Addrs) == 1 {
+		return cfg.Addrs[0], opts
+	}
+
+	var addrs []resolver.Address
+	for _, addr := range cfg.Addrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		addrs = append(addrs, resolver.Address{Addr: addr, ServerName: host})
+	}
+
+	r := manual.NewBuilderWithScheme(staticScheme)
+	r.InitialState(resolver.State{Addresses: addrs})
+
+	return staticScheme + ":///ai-service", append(opts, grpc.WithResolvers(r))
+}
+
+type pickerBuilder struct {
+	leastRequest bool
+}
+
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		backends.markReady(nil)
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	p := &picker{leastRequest: b.leastRequest}
+	var ready []string
+	for sc, scInfo := range info.ReadySCs {
+		p.subConns = append(p.subConns, sc)
+		p.states = append(p.states, backends.get(scInfo.Address.Addr))
+		ready = append(ready, scInfo.Address.Addr)
+	}
+	backends.markReady(ready)
+
+	return p
+}
+
+type picker struct {
+	leastRequest bool
+	subConns     []balancer.SubConn
+	states       []*backendState
+	next         uint32
+}
+
+func (p *picker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	now := time.Now()
+
+	var candidates []int
+	for i, state := range p.states {
+		if !state.ejected(now) {
+			candidates = append(candidates, i)
+		}
+	}
+	// Hepsi dışlandıysa istekleri düşürmek yerine yine de dağıtıyoruz.
+	if len(candidates) == 0 {
+		for i := range p.states {
+			candidates = append(candidates, i)
+		}
+	}
+
+	start := int(atomic.AddUint32(&p.next, 1)) % len(candidates)
+	chosen := candidates[start]
+
+	if p.leastRequest {
+		for k := 1; k < len(candidates); k++ {
+			i := candidates[(start+k)%len(candidates)]
+			if atomic.LoadInt64(&p.states[i].outstanding) < atomic.LoadInt64(&p.states[chosen].outstanding) {
+				chosen = i
+			}
+		}
+	}
+
+	state := p.states[chosen]
+	atomic.AddInt64(&state.outstanding, 1)
+
+	return balancer.PickResult{
+		SubConn: p.subConns[chosen],
+		Done: func(info balancer.DoneInfo) {
+			atomic.AddInt64(&state.outstanding, -1)
+			backends.record(state, info.Err)
+		},
+	}, nil
+}
+
+type backendState struct {
+	addr        string
+	outstanding int64
+
+	// Aşağıdakiler backendTracker.mu ile korunur.
+	ready               bool
+	requests            uint64
+	failures            uint64
+	consecutiveFailures int
+	ejectedUntil        time.Time
+}
+
+func (s *backendState) ejected(now time.Time) bool {
+	backends.mu.Lock()
+	defer backends.mu.Unlock()
+	return now.Before(s.ejectedUntil)
+}
+
+type backendTracker struct {
+	mu     sync.Mutex
+	cfg    config.LBConfig
+	states map[string]*backendState
+}
+
+func (t *backendTracker) configure(cfg config.LBConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cfg = cfg
+}
+
+func (t *backendTracker) get(addr string) *backendState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[addr]
+	if !ok {
+		state = &backendState{addr: addr}
+		t.states[addr] = state
+	}
+	return state
+}
+
+func (t *backendTracker) markReady(ready []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, state := range t.states {
+		state.ready = false
+	}
+	for _, addr := range ready {
+		if state, ok := t.states[addr]; ok {
+			state.ready = true
+		}
+	}
+}
+
+// record bir çağrının sonucunu işler; art arda OutlierFailures kadar hata
+// veren örnek, MaxEjectionPercent sınırı aşılmıyorsa bir süre dışlanır.
+func (t *backendTracker) record(state *backendState, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state.requests++
+	if !isBackendFailure(err) {
+		state.consecutiveFailures = 0
+		return
+	}
+
+	state.failures++
+	state.consecutiveFailures++
+
+	if t.cfg.OutlierFailures == 0 || state.consecutiveFailures < t.cfg.OutlierFailures {
+		return
+	}
+
+	now := time.Now()
+	ejected := 0
+	for _, s := range t.states {
+		if now.Before(s.ejectedUntil) {
+			ejected++
+		}
+	}
+	if (ejected+1)*100 > len(t.states)*t.cfg.MaxEjectionPercent {
+		return
+	}
+
+	state.ejectedUntil = now.Add(t.cfg.OutlierEjectionTime)
+	state.consecutiveFailures = 0
+	log.Printf("⚠️ AI örneği %s art arda hatalar nedeniyle %s süreyle devre dışı bırakıldı", state.addr, t.cfg.OutlierEjectionTime)
+}
+
+func (t *backendTracker) stats() []BackendStat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var stats []BackendStat
+	for _, state := range t.states {
+		stats = append(stats, BackendStat{
+			Addr:         state.addr,
+			Ready:        state.ready,
+			Outstanding:  atomic.LoadInt64(&state.outstanding),
+			Requests:     state.requests,
+			Failures:     state.failures,
+			EjectedUntil: state.ejectedUntil,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Addr < stats[j].Addr })
+	return stats
+}
+
+// isBackendFailure sadece örneğin kendisinden kaynaklanabilecek hataları sayar;
+// geçersiz istek gibi hatalar örneği dışlatmaz.
+func isBackendFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"trip-plan-service/internal/config"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSubConn picker'ın yalnızca kimlik olarak kullandığı bağlantıdır.
+type fakeSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+// withBackends paket seviyesindeki backends'i test süresince verilen
+// ayarlarla yenisiyle değiştirir.
+func withBackends(t *testing.T, cfg config.LBConfig) {
+	t.Helper()
+	previous := backends
+	backends = &backendTracker{cfg: cfg, states: map[string]*backendState{}}
+	t.Cleanup(func() { backends = previous })
+}
+
+func buildPicker(t *testing.T, leastRequest bool, addrs ...string) balancer.Picker {
+	t.Helper()
+	info := base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{}}
+	for _, addr := range addrs {
+		info.ReadySCs[&fakeSubConn{addr: addr}] = base.SubConnInfo{Address: resolver.Address{Addr: addr}}
+	}
+	return (&pickerBuilder{leastRequest: leastRequest}).Build(info)
+}
+
+func pick(t *testing.T, p balancer.Picker) (string, func(balancer.DoneInfo)) {
+	t.Helper()
+	result, err := p.Pick(balancer.PickInfo{})
+	if err != nil {
+		t.Fatalf("Pick: %v", err)
+	}
+	return result.SubConn.(*fakeSubConn).addr, result.Done
+}
+
+var unavailable = status.Error(codes.Unavailable, "connection refused")
+
+func TestPickerRoundRobin(t *testing.T) {
+	withBackends(t, config.LBConfig{})
+	p := buildPicker(t, false, "a:1", "b:1", "c:1")
+
+	counts := map[string]int{}
+	for i := 0; i < 9; i++ {
+		addr, done := pick(t, p)
+		counts[addr]++
+		done(balancer.DoneInfo{})
+	}
+	for _, addr := range []string{"a:1", "b:1", "c:1"} {
+		if counts[addr] != 3 {
+			t.Errorf("picks = %v, want 3 for every backend", counts)
+			break
+		}
+	}
+}
+
+// Least request en az bekleyen isteği olan örneği seçmeli; Done çağrılınca
+// bekleyen sayısı düşmeli.
+func TestPickerLeastRequest(t *testing.T) {
+	withBackends(t, config.LBConfig{})
+	p := buildPicker(t, true, "a:1", "b:1")
+
+	first, done := pick(t, p)
+	second, _ := pick(t, p)
+	if second == first {
+		t.Fatalf("second pick chose %s which already has an outstanding request", first)
+	}
+	done(balancer.DoneInfo{})
+	if third, _ := pick(t, p); third != first {
+		t.Errorf("third pick = %s, want %s after its request finished", third, first)
+	}
+
+	for _, stat := range backends.stats() {
+		if stat.Outstanding != 1 {
+			t.Errorf("%s outstanding = %d, want 1", stat.Addr, stat.Outstanding)
+		}
+	}
+}
+
+func TestPickerSkipsEjectedBackends(t *testing.T) {
+	withBackends(t, config.LBConfig{})
+	p := buildPicker(t, false, "a:1", "b:1")
+	backends.get("a:1").ejectedUntil = time.Now().Add(time.Minute)
+
+	for i := 0; i < 4; i++ {
+		if addr, _ := pick(t, p); addr != "b:1" {
+			t.Fatalf("pick %d = %s, want b:1 while a:1 is ejected", i, addr)
+		}
+	}
+
+	// Hepsi dışlanmışsa istekler yine de dağıtılır
+	backends.get("b:1").ejectedUntil = time.Now().Add(time.Minute)
+	seen := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		addr, _ := pick(t, p)
+		seen[addr] = true
+	}
+	if !seen["a:1"] || !seen["b:1"] {
+		t.Errorf("picked %v with every backend ejected, want both", seen)
+	}
+}
+
+func TestPickerWithoutReadyBackends(t *testing.T) {
+	withBackends(t, config.LBConfig{})
+	if _, err := buildPicker(t, false).Pick(balancer.PickInfo{}); err != balancer.ErrNoSubConnAvailable {
+		t.Errorf("Pick err = %v, want ErrNoSubConnAvailable", err)
+	}
+}
+
+func TestRecordEjectsAfterConsecutiveFailures(t *testing.T) {
+	withBackends(t, config.LBConfig{OutlierFailures: 3, OutlierEjectionTime: time.Minute, MaxEjectionPercent: 50})
+	a := backends.get("a:1")
+	backends.get("b:1")
+	now := time.Now()
+
+	backends.record(a, unavailable)
+	backends.record(a, unavailable)
+	// Başarılı çağrı ya da örnekten kaynaklanmayan hata seriyi sıfırlar
+	backends.record(a, nil)
+	backends.record(a, unavailable)
+	backends.record(a, status.Error(codes.InvalidArgument, "bad request"))
+	backends.record(a, unavailable)
+	backends.record(a, unavailable)
+	if a.ejected(now) {
+		t.Fatal("a:1 ejected without three consecutive failures")
+	}
+
+	backends.record(a, unavailable)
+	if !a.ejected(now) {
+		t.Fatal("a:1 not ejected after three consecutive failures")
+	}
+	if a.ejected(now.Add(2 * time.Minute)) {
+		t.Error("a:1 still ejected after the ejection time")
+	}
+
+	stats := backends.stats()
+	if stats[0].Addr != "a:1" || stats[0].Requests != 8 || stats[0].Failures != 6 {
+		t.Errorf("stats = %+v, want 8 requests and 6 failures for a:1", stats[0])
+	}
+}
+
+// MaxEjectionPercent aşılacaksa örnek dışlanmaz; iki örnekten biri zaten
+// dışlanmışken %50 sınırı ikincisini korur.
+func TestRecordRespectsMaxEjectionPercent(t *testing.T) {
+	withBackends(t, config.LBConfig{OutlierFailures: 1, OutlierEjectionTime: time.Minute, MaxEjectionPercent: 50})
+	a := backends.get("a:1")
+	b := backends.get("b:1")
+	now := time.Now()
+
+	backends.record(a, unavailable)
+	backends.record(b, unavailable)
+
+	if !a.ejected(now) {
+		t.Error("a:1 not ejected")
+	}
+	if b.ejected(now) {
+		t.Error("b:1 ejected although that would exceed MaxEjectionPercent")
+	}
+}
+
+func TestRecordWithoutOutlierDetection(t *testing.T) {
+	withBackends(t, config.LBConfig{OutlierFailures: 0, MaxEjectionPercent: 100})
+	a := backends.get("a:1")
+	for i := 0; i < 10; i++ {
+		backends.record(a, unavailable)
+	}
+	if a.ejected(time.Now()) {
+		t.Error("a:1 ejected with AI_OUTLIER_FAILURES=0")
+	}
+}
+
+// Done, çağrının sonucunu örneğin sayaçlarına işler.
+func TestPickDoneRecordsResult(t *testing.T) {
+	withBackends(t, config.LBConfig{OutlierFailures: 1, OutlierEjectionTime: time.Minute, MaxEjectionPercent: 100})
+	p := buildPicker(t, false, "a:1")
+
+	_, done := pick(t, p)
+	done(balancer.DoneInfo{Err: unavailable})
+
+	stats := backends.stats()
+	if len(stats) != 1 || !stats[0].Ready || stats[0].Outstanding != 0 || stats[0].Failures != 1 || stats[0].EjectedUntil.IsZero() {
+		t.Errorf("stats = %+v, want a ready, ejected a:1 with one failure and nothing outstanding", stats)
+	}
+}
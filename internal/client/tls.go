@@ -0,0 +1,242 @@
+// internal/client/tls.go for trip-plan-service
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"trip-plan-service/internal/config"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// transportCredentials AI bağlantısı için TLS, mTLS veya (kapalıysa)
+// şifresiz kimlik bilgilerini üretir.
+func transportCredentials(cfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	reloader, err := newCertReloader(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.MutualTLS() {
+		tlsConfig.GetClientCertificate = reloader.clientCertificate
+	}
+
+	// Özel CA verildiyse doğrulamayı kendimiz yapıyoruz ki CA dosyası
+	// değiştiğinde yeni havuz bir sonraki el sıkışmada kullanılsın.
+	// CA verilmezse sistem sertifikaları ile standart doğrulama yapılır.
+	if cfg.CAFile != "" {
+		tlsConfig.InsecureSkipVerify = true
+		return &reloadingCredentials{
+			TransportCredentials: credentials.NewTLS(tlsConfig),
+			config:               tlsConfig,
+			reloader:             reloader,
+		}, nil
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// reloadingCredentials her el sıkışmada sertifikayı reloader'daki güncel CA
+// havuzuyla doğrular. Doğrulanacak ad AI_TLS_SERVER_NAME, verilmemişse
+// bağlanılan host'tur; crypto/tls IP adreslerini ConnectionState.ServerName'e
+// koymadığı için ad bağlantı durumundan alınmaz, IP SAN'ları da böylece
+// kontrol edilir.
+type reloadingCredentials struct {
+	credentials.TransportCredentials
+	config   *tls.Config
+	reloader *certReloader
+}
+
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	serverName := c.config.ServerName
+	if serverName == "" {
+		serverName = authority
+		if host, _, err := net.SplitHostPort(authority); err == nil {
+			serverName = host
+		}
+	}
+
+	cfg := c.config.Clone()
+	cfg.VerifyConnection = func(state tls.ConnectionState) error {
+		return c.reloader.verifyConnection(state, serverName)
+	}
+	return credentials.NewTLS(cfg).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		config:               c.config.Clone(),
+		reloader:             c.reloader,
+	}
+}
+
+// certReloader CA paketi ile istemci sertifikasını diskten okur ve dosyalar
+// değiştiğinde (en fazla ReloadInterval'da bir kontrol ederek) yeniden yükler.
+type certReloader struct {
+	cfg config.TLSConfig
+
+	mu        sync.Mutex
+	roots     *x509.CertPool
+	cert      *tls.Certificate
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+func newCertReloader(cfg config.TLSConfig) (*certReloader, error) {
+	r := &certReloader{cfg: cfg}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = time.Now()
+	return r, nil
+}
+
+func (r *certReloader) files() []string {
+	var files []string
+	for _, path := range []string{r.cfg.CAFile, r.cfg.CertFile, r.cfg.KeyFile} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// load dosyaları okur; hata olursa mevcut sertifikalar değişmez.
+func (r *certReloader) load() error {
+	modTimes := map[string]time.Time{}
+	for _, path := range r.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %v", path, err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	var roots *x509.CertPool
+	if r.cfg.CAFile != "" {
+		pem, err := os.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", r.cfg.CAFile)
+		}
+	}
+
+	var cert *tls.Certificate
+	if r.cfg.MutualTLS() {
+		pair, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		cert = &pair
+	}
+
+	r.roots = roots
+	r.cert = cert
+	r.modTimes = modTimes
+	return nil
+}
+
+// refresh kilit altında çağrılır.
+func (r *certReloader) refresh() {
+	if time.Since(r.lastCheck) < r.cfg.ReloadInterval {
+		return
+	}
+	r.lastCheck = time.Now()
+
+	changed := false
+	for _, path := range r.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Printf("⚠️ AI TLS dosyası okunamadı, eski sertifikalar kullanılıyor: %v", err)
+			return
+		}
+		if !info.ModTime().Equal(r.modTimes[path]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		log.Printf("⚠️ AI TLS sertifikaları yeniden yüklenemedi, eski sertifikalar kullanılıyor: %v", err)
+		return
+	}
+	log.Printf("🔐 AI TLS sertifikaları yeniden yüklendi")
+}
+
+func (r *certReloader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refresh()
+	return r.cert, nil
+}
+
+// verifyConnection sunucu sertifikasını güncel CA havuzuna ve serverName'e
+// (DNS adı ya da IP) göre doğrular.
+func (r *certReloader) verifyConnection(state tls.ConnectionState, serverName string) error {
+	r.mu.Lock()
+	r.refresh()
+	roots := r.roots
+	r.mu.Unlock()
+
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("AI service presented no certificate")
+	}
+	// Boş ad x509'da ad kontrolünü tamamen atlar
+	if serverName == "" {
+		return fmt.Errorf("no server name to verify AI service certificate against")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify AI service certificate: %v", err)
+	}
+	return nil
+}
+
+// tokenCredentials her RPC'ye "authorization: Bearer <token>" metadata'sı ekler.
+type tokenCredentials struct {
+	token string
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,405 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"trip-plan-service/internal/config"
+
+	"github.com/Semhumc/grpc-proto/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testCerts scripts/gen-certs.sh ile aynı yapıdaki sertifikaların yollarıdır;
+// wrongServer CA tarafından imzalanmış ama başka bir adın sertifikasıdır.
+type testCerts struct {
+	ca, server, serverKey, wrongServer, wrongServerKey, client, clientKey string
+}
+
+func generateCerts(t *testing.T) testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey := newKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "trip-plan-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certs := testCerts{ca: filepath.Join(dir, "ca.crt")}
+	writePEM(t, certs.ca, "CERTIFICATE", caDER)
+	certs.server, certs.serverKey = issueCert(t, dir, caCert, caKey, "server", x509.ExtKeyUsageServerAuth,
+		[]string{"localhost", "host.docker.internal"}, []net.IP{net.ParseIP("127.0.0.1")})
+	certs.wrongServer, certs.wrongServerKey = issueWrongServerCert(t, dir, caCert, caKey)
+	certs.client, certs.clientKey = issueCert(t, dir, caCert, caKey, "client", x509.ExtKeyUsageClientAuth, nil, nil)
+	return certs
+}
+
+// issueWrongServerCert CA'nın imzaladığı ama AI servisinin adını ve IP'sini
+// içermeyen bir sunucu sertifikası üretir.
+func issueWrongServerCert(t *testing.T, dir string, caCert *x509.Certificate, caKey crypto.Signer) (string, string) {
+	return issueCert(t, dir, caCert, caKey, "wrong-server", x509.ExtKeyUsageServerAuth,
+		[]string{"other.example"}, []net.IP{net.ParseIP("10.9.8.7")})
+}
+
+func issueCert(t *testing.T, dir string, caCert *x509.Certificate, caKey crypto.Signer, name string, usage x509.ExtKeyUsage, dns []string, ips []net.IP) (string, string) {
+	t.Helper()
+	key := newKey(t)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     dns,
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+	return certPath, keyPath
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serveTLS 127.0.0.1 üzerinde tek bir bağlantı kabul eden bir TLS sunucusu
+// açar ve sunucu tarafı el sıkışmanın sonucunu kanala yazar.
+func serveTLS(t *testing.T, certFile, keyFile, clientCA string) (string, <-chan error) {
+	t.Helper()
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// gRPC istemcisi ALPN ile h2 seçilmesini şart koşar
+	cfg := &tls.Config{Certificates: []tls.Certificate{pair}, NextProtos: []string{"h2"}}
+	if clientCA != "" {
+		pool := x509.NewCertPool()
+		caPEM, err := os.ReadFile(clientCA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.AppendCertsFromPEM(caPEM)
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	result := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			result <- err
+			return
+		}
+		defer conn.Close()
+		result <- tls.Server(conn, cfg).Handshake()
+	}()
+	return listener.Addr().String(), result
+}
+
+func clientHandshake(t *testing.T, cfg config.TLSConfig, authority, addr string) error {
+	t.Helper()
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := creds.ClientHandshake(ctx, authority, raw)
+	if err == nil {
+		conn.Close()
+	}
+	return err
+}
+
+func TestTLSVerifiesServerCertificate(t *testing.T) {
+	certs := generateCerts(t)
+
+	tests := []struct {
+		name       string
+		cert, key  string
+		serverName string
+		authority  func(addr string) string
+		wantErr    bool
+	}{
+		{name: "IP SAN matches dialled IP", cert: certs.server, key: certs.serverKey},
+		{name: "server name overrides dialled host", cert: certs.server, key: certs.serverKey, serverName: "localhost"},
+		{name: "multi-address authority without port", cert: certs.server, key: certs.serverKey,
+			authority: func(addr string) string { host, _, _ := net.SplitHostPort(addr); return host }},
+		{name: "wrong SAN for dialled IP is rejected", cert: certs.wrongServer, key: certs.wrongServerKey, wantErr: true},
+		{name: "wrong SAN for server name is rejected", cert: certs.wrongServer, key: certs.wrongServerKey, serverName: "localhost", wantErr: true},
+		{name: "server name matching the cert is accepted", cert: certs.wrongServer, key: certs.wrongServerKey, serverName: "other.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, _ := serveTLS(t, tt.cert, tt.key, "")
+			authority := addr
+			if tt.authority != nil {
+				authority = tt.authority(addr)
+			}
+			cfg := config.TLSConfig{Enabled: true, CAFile: certs.ca, ServerName: tt.serverName, ReloadInterval: time.Minute}
+
+			err := clientHandshake(t, cfg, authority, addr)
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "failed to verify AI service certificate")) {
+				t.Fatalf("handshake error = %v, want certificate verification failure", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("handshake failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSRejectsCertificateFromUnknownCA(t *testing.T) {
+	certs := generateCerts(t)
+	other := generateCerts(t)
+
+	addr, _ := serveTLS(t, other.server, other.serverKey, "")
+	cfg := config.TLSConfig{Enabled: true, CAFile: certs.ca, ReloadInterval: time.Minute}
+	if err := clientHandshake(t, cfg, addr, addr); err == nil {
+		t.Fatal("certificate signed by another CA was accepted")
+	}
+}
+
+func TestMutualTLSPresentsClientCertificate(t *testing.T) {
+	certs := generateCerts(t)
+
+	t.Run("with client certificate", func(t *testing.T) {
+		addr, server := serveTLS(t, certs.server, certs.serverKey, certs.ca)
+		cfg := config.TLSConfig{Enabled: true, CAFile: certs.ca, CertFile: certs.client, KeyFile: certs.clientKey, ReloadInterval: time.Minute}
+		if err := clientHandshake(t, cfg, addr, addr); err != nil {
+			t.Fatalf("client handshake failed: %v", err)
+		}
+		if err := <-server; err != nil {
+			t.Fatalf("server rejected client certificate: %v", err)
+		}
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		addr, server := serveTLS(t, certs.server, certs.serverKey, certs.ca)
+		cfg := config.TLSConfig{Enabled: true, CAFile: certs.ca, ReloadInterval: time.Minute}
+		clientHandshake(t, cfg, addr, addr)
+		if err := <-server; err == nil {
+			t.Fatal("server accepted a connection without client certificate")
+		}
+	})
+}
+
+func TestTLSReloadsCABundle(t *testing.T) {
+	certs := generateCerts(t)
+	other := generateCerts(t)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	copyFile(t, other.ca, caFile)
+
+	creds, err := transportCredentials(config.TLSConfig{Enabled: true, CAFile: caFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handshake := func() error {
+		addr, _ := serveTLS(t, certs.server, certs.serverKey, "")
+		raw, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer raw.Close()
+		conn, _, err := creds.ClientHandshake(context.Background(), addr, raw)
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+
+	if err := handshake(); err == nil {
+		t.Fatal("certificate was accepted before its CA was installed")
+	}
+
+	copyFile(t, certs.ca, caFile)
+	// mtime çözünürlüğü kaba olan dosya sistemlerinde değişiklik görülsün
+	later := time.Now().Add(time.Second)
+	os.Chtimes(caFile, later, later)
+
+	if err := handshake(); err != nil {
+		t.Fatalf("new CA bundle was not picked up: %v", err)
+	}
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	data, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(to, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestFakeAIServerWithGeneratedCerts scripts/gen-certs.sh'in ürettiği
+// sertifikalarla TLS kullanan sahte bir AI gRPC sunucusuna bağlanır.
+// Sunucuda servis kayıtlı değildir; el sıkışma başarılıysa RPC sertifika
+// hatası dışında bir hatayla döner.
+func TestFakeAIServerWithGeneratedCerts(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is not installed")
+	}
+	dir := t.TempDir()
+	script, err := filepath.Abs("../../scripts/gen-certs.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("sh", script, dir).CombinedOutput(); err != nil {
+		t.Fatalf("gen-certs.sh failed: %v\n%s", err, out)
+	}
+	caCert, caKey := loadCA(t, filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"))
+	wrongCert, wrongKey := issueWrongServerCert(t, dir, caCert, caKey)
+
+	tests := []struct {
+		name      string
+		cert, key string
+		wantErr   bool
+	}{
+		{name: "generated server certificate", cert: filepath.Join(dir, "server.crt"), key: filepath.Join(dir, "server.key")},
+		{name: "same CA, wrong SAN", cert: wrongCert, key: wrongKey, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serveFakeAI(t, tt.cert, tt.key, filepath.Join(dir, "ca.crt"))
+			client, err := NewAIClient(config.AIConfig{
+				Addrs:   []string{addr},
+				Timeout: 5 * time.Second,
+				TLS: config.TLSConfig{
+					Enabled:        true,
+					CAFile:         filepath.Join(dir, "ca.crt"),
+					CertFile:       filepath.Join(dir, "client.crt"),
+					KeyFile:        filepath.Join(dir, "client.key"),
+					ReloadInterval: time.Minute,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			_, err = client.GenerateTripPlan(context.Background(), &proto.PromptRequest{Name: "test"})
+			if err == nil {
+				t.Fatal("fake AI server has no services, expected an error")
+			}
+			rejected := strings.Contains(err.Error(), "certificate")
+			if rejected != tt.wantErr {
+				t.Fatalf("error = %v, want certificate rejection: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func loadCA(t *testing.T, certFile, keyFile string) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, pair.PrivateKey.(crypto.Signer)
+}
+
+func serveFakeAI(t *testing.T, certFile, keyFile, clientCA string) string {
+	t.Helper()
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM, err := os.ReadFile(clientCA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
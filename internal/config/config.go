@@ -0,0 +1,618 @@
+// internal/config/config.go for trip-plan-service
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config servisin tüm ayarlarını tek yerde toplar. Load ile ortam
+// değişkenlerinden (ve isteğe bağlı CONFIG_FILE dosyasından) doldurulur.
+type Config struct {
+	AppEnv string
+	Port   string
+	// PublicBaseURL dışarıya verilen linklerde (takvim feed'i gibi) kullanılır;
+	// boşsa isteğin kendi adresi kullanılır.
+	PublicBaseURL string
+	// AdminToken /api/v1/admin uçlarını korur; boşsa bu uçlar kapalıdır.
+	AdminToken string
+	DB         DBConfig
+	AI         AIConfig
+	CORS       CORSConfig
+	Travel     TravelConfig
+	LocationGC GCConfig
+	Geocoder   GeocoderConfig
+	Features   Features
+}
+
+type DBConfig struct {
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Name            string
+	Schema          string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// AIConfig AI servisine bağlantı ayarlarıdır. Addrs birden fazla
+// host:port ya da tek bir "dns:///host:port" hedefi içerebilir.
+type AIConfig struct {
+	Addrs     []string
+	Timeout   time.Duration
+	AuthToken string
+	TLS       TLSConfig
+	LB        LBConfig
+}
+
+// LBConfig birden fazla AI örneği arasında istemci tarafı yük dağıtımını
+// ve hatalı örneklerin geçici olarak devre dışı bırakılmasını tanımlar.
+type LBConfig struct {
+	Policy              string
+	HealthCheck         bool
+	OutlierFailures     int
+	OutlierEjectionTime time.Duration
+	MaxEjectionPercent  int
+}
+
+// TLSConfig AI servisi ile gRPC bağlantısının şifrelemesini tanımlar.
+// CertFile ve KeyFile verilirse mutual TLS kullanılır.
+type TLSConfig struct {
+	Enabled        bool
+	CAFile         string
+	CertFile       string
+	KeyFile        string
+	ServerName     string
+	ReloadInterval time.Duration
+}
+
+// MutualTLS istemci sertifikası ile kimlik doğrulama yapılıp yapılmadığını döner.
+func (c TLSConfig) MutualTLS() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+type CORSConfig struct {
+	AllowOrigins []string
+}
+
+// TravelConfig mesafe/süre tahmininde kullanılan ulaşım türlerini ve
+// ortalama hızlarını (km/sa) tanımlar.
+type TravelConfig struct {
+	DefaultMode string
+	Speeds      map[string]float64
+}
+
+// GCConfig hiçbir trip'te kullanılmayan lokasyonların arka planda
+// temizlenmesini ayarlar. GracePeriod'dan yeni lokasyonlar silinmez; böylece
+// kaydı süren bir trip'in lokasyonu altından çekilmez.
+type GCConfig struct {
+	Interval    time.Duration
+	GracePeriod time.Duration
+	BatchSize   int
+}
+
+// GeocoderConfig trip başlangıç/bitiş noktalarının koordinata çevrilmesini
+// ayarlar. URL Nominatim uyumlu bir servisin adresidir; boşsa ya da servis
+// yanıt vermezse gömülü yer listesi kullanılır.
+type GeocoderConfig struct {
+	URL       string
+	Timeout   time.Duration
+	UserAgent string
+}
+
+// Features açılıp kapatılabilen özellikleri tutar (FEATURES=a,b,-c).
+type Features map[string]bool
+
+// knownFeatures bilinen özellikleri ve varsayılan değerlerini listeler.
+// Yazım hatalı bir özellik adı başlangıçta hata verir.
+var knownFeatures = map[string]bool{
+	"request_logging":  false,
+	"fallback_planner": true,
+	// İstekleri openapi.json'a göre doğrular
+	"openapi_validation": false,
+	// Yanıtları da doğrular; APP_ENV=test iken her zaman açıktır
+	"openapi_response_validation": false,
+	// Sahipsiz lokasyonları LOCATION_GC_INTERVAL aralıklarla siler
+	"location_gc": true,
+	// Başlangıçta ülke/bölge etiketi olmayan lokasyonları etiketler
+	"location_geocode_backfill": true,
+}
+
+const (
+	LBRoundRobin   = "round_robin"
+	LBLeastRequest = "least_request"
+)
+
+var validSSLModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// Enabled özelliğin açık olup olmadığını döner.
+func (f Features) Enabled(name string) bool {
+	return f[name]
+}
+
+// DSN lib/pq için bağlantı dizesini üretir.
+func (c DBConfig) DSN() string {
+	query := url.Values{}
+	query.Set("sslmode", c.SSLMode)
+	if c.Schema != "" {
+		query.Set("search_path", c.Schema)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     c.Host + ":" + c.Port,
+		Path:     "/" + c.Name,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// Load ayarları okur, doğrular ve ilk hatada değil tüm hataları
+// toplayarak döner.
+func Load() (*Config, error) {
+	src, err := newSource()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		AppEnv:        src.str("APP_ENV", "local"),
+		Port:          src.str("PORT", "8085"),
+		PublicBaseURL: strings.TrimSuffix(src.str("PUBLIC_BASE_URL", ""), "/"),
+		AdminToken:    src.str("ADMIN_TOKEN", ""),
+		DB:            src.db(),
+		AI: AIConfig{
+			Addrs:     src.list("AI_SERVICE_ADDR", nil),
+			Timeout:   src.duration("AI_TIMEOUT", 250*time.Second),
+			AuthToken: src.str("AI_AUTH_TOKEN", ""),
+			TLS: TLSConfig{
+				Enabled:        src.bool("AI_TLS_ENABLED", false),
+				CAFile:         src.str("AI_TLS_CA_FILE", ""),
+				CertFile:       src.str("AI_TLS_CERT_FILE", ""),
+				KeyFile:        src.str("AI_TLS_KEY_FILE", ""),
+				ServerName:     src.str("AI_TLS_SERVER_NAME", ""),
+				ReloadInterval: src.duration("AI_TLS_RELOAD_INTERVAL", time.Minute),
+			},
+			LB: LBConfig{
+				Policy:              src.str("AI_LB_POLICY", LBRoundRobin),
+				HealthCheck:         src.bool("AI_HEALTH_CHECK", true),
+				OutlierFailures:     src.int("AI_OUTLIER_FAILURES", 5),
+				OutlierEjectionTime: src.duration("AI_OUTLIER_EJECTION_TIME", 30*time.Second),
+				MaxEjectionPercent:  src.int("AI_OUTLIER_MAX_EJECTION_PERCENT", 50),
+			},
+		},
+		CORS: CORSConfig{
+			AllowOrigins: src.list("CORS_ALLOW_ORIGINS", []string{"http://localhost:3000"}),
+		},
+		Travel: TravelConfig{
+			DefaultMode: strings.ToLower(src.str("TRAVEL_DEFAULT_MODE", "car")),
+			Speeds:      src.speeds("TRAVEL_SPEEDS", "car=80,caravan=65,bicycle=15,walking=5"),
+		},
+		LocationGC: GCConfig{
+			Interval:    src.duration("LOCATION_GC_INTERVAL", 6*time.Hour),
+			GracePeriod: src.duration("LOCATION_GC_GRACE_PERIOD", 24*time.Hour),
+			BatchSize:   src.int("LOCATION_GC_BATCH_SIZE", 500),
+		},
+		Geocoder: GeocoderConfig{
+			URL:       strings.TrimSuffix(src.str("GEOCODER_URL", ""), "/"),
+			Timeout:   src.duration("GEOCODER_TIMEOUT", 5*time.Second),
+			UserAgent: src.str("GEOCODER_USER_AGENT", "trip-plan-service"),
+		},
+		Features: src.features("FEATURES"),
+	}
+
+	cfg.validate(src)
+
+	if err := src.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDB sadece veritabanı ayarlarını okur; AI servisine ihtiyaç duymayan
+// komut satırı araçları içindir.
+func LoadDB() (*DBConfig, error) {
+	src, err := newSource()
+	if err != nil {
+		return nil, err
+	}
+
+	db := src.db()
+	db.validate(src)
+
+	if err := src.err(); err != nil {
+		return nil, err
+	}
+	return &db, nil
+}
+
+func newSource() (*source, error) {
+	src := &source{file: map[string]string{}}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := readEnvFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: failed to read %s: %v", path, err)
+		}
+		src.file = file
+	}
+	return src, nil
+}
+
+func (s *source) db() DBConfig {
+	return DBConfig{
+		Host:            s.str("DB_HOST", ""),
+		Port:            s.str("DB_PORT", "5432"),
+		User:            s.str("DB_USERNAME", ""),
+		Password:        s.str("DB_PASSWORD", ""),
+		Name:            s.str("DB_DATABASE", ""),
+		Schema:          s.str("DB_SCHEMA", ""),
+		SSLMode:         s.str("DB_SSLMODE", "disable"),
+		MaxOpenConns:    s.int("DB_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    s.int("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: s.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+	}
+}
+
+func (s *source) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("config: invalid configuration:\n  - %s", strings.Join(s.errs, "\n  - "))
+}
+
+func (c *Config) validate(src *source) {
+	c.DB.validate(src)
+
+	if !isPort(c.Port) {
+		src.fail("PORT must be a port number, got %q", c.Port)
+	}
+
+	if c.PublicBaseURL != "" {
+		u, err := url.Parse(c.PublicBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.fail("PUBLIC_BASE_URL must be an absolute http(s) URL, got %q", c.PublicBaseURL)
+		}
+	}
+
+	if len(c.AI.Addrs) == 0 {
+		src.fail("AI_SERVICE_ADDR is required")
+	}
+	for _, addr := range c.AI.Addrs {
+		if strings.Contains(addr, "://") && len(c.AI.Addrs) > 1 {
+			src.fail("AI_SERVICE_ADDR must be either a single resolver target or a list of host:port, got %q in a list", addr)
+		}
+	}
+	if c.AI.Timeout <= 0 {
+		src.fail("AI_TIMEOUT must be positive")
+	}
+	c.AI.TLS.validate(src)
+	c.AI.LB.validate(src)
+	if c.AI.AuthToken != "" && !c.AI.TLS.Enabled {
+		src.fail("AI_AUTH_TOKEN requires AI_TLS_ENABLED=true, tokens are never sent in plaintext")
+	}
+
+	if _, ok := c.Travel.Speeds[c.Travel.DefaultMode]; !ok {
+		src.fail("TRAVEL_DEFAULT_MODE %q is not listed in TRAVEL_SPEEDS", c.Travel.DefaultMode)
+	}
+
+	if c.LocationGC.Interval <= 0 {
+		src.fail("LOCATION_GC_INTERVAL must be positive")
+	}
+	if c.LocationGC.GracePeriod < 0 {
+		src.fail("LOCATION_GC_GRACE_PERIOD must not be negative")
+	}
+	if c.LocationGC.BatchSize < 1 {
+		src.fail("LOCATION_GC_BATCH_SIZE must be at least 1")
+	}
+
+	if c.Geocoder.URL != "" {
+		u, err := url.Parse(c.Geocoder.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.fail("GEOCODER_URL must be an absolute http(s) URL, got %q", c.Geocoder.URL)
+		}
+	}
+	if c.Geocoder.Timeout <= 0 {
+		src.fail("GEOCODER_TIMEOUT must be positive")
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		src.fail("CORS_ALLOW_ORIGINS must contain at least one origin")
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		// Credentials açıkken fiber "*" kabul etmiyor.
+		if origin == "*" {
+			src.fail("CORS_ALLOW_ORIGINS must list explicit origins, \"*\" is not allowed with credentials")
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			src.fail("CORS_ALLOW_ORIGINS contains an invalid origin %q", origin)
+		}
+	}
+}
+
+func (c DBConfig) validate(src *source) {
+	if c.Host == "" {
+		src.fail("DB_HOST is required")
+	}
+	if c.User == "" {
+		src.fail("DB_USERNAME is required")
+	}
+	if c.Name == "" {
+		src.fail("DB_DATABASE is required")
+	}
+	if !isPort(c.Port) {
+		src.fail("DB_PORT must be a port number, got %q", c.Port)
+	}
+	if !validSSLModes[c.SSLMode] {
+		src.fail("DB_SSLMODE must be one of disable, allow, prefer, require, verify-ca, verify-full, got %q", c.SSLMode)
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		src.fail("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		src.fail("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+}
+
+func (c TLSConfig) validate(src *source) {
+	if !c.Enabled {
+		if c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" {
+			src.fail("AI_TLS_* files are set but AI_TLS_ENABLED is false")
+		}
+		return
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		src.fail("AI_TLS_CERT_FILE and AI_TLS_KEY_FILE must be set together")
+	}
+	for _, file := range []struct{ key, path string }{
+		{"AI_TLS_CA_FILE", c.CAFile},
+		{"AI_TLS_CERT_FILE", c.CertFile},
+		{"AI_TLS_KEY_FILE", c.KeyFile},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			src.fail("%s: %v", file.key, err)
+		}
+	}
+	if c.ReloadInterval <= 0 {
+		src.fail("AI_TLS_RELOAD_INTERVAL must be positive")
+	}
+}
+
+func (c LBConfig) validate(src *source) {
+	if c.Policy != LBRoundRobin && c.Policy != LBLeastRequest {
+		src.fail("AI_LB_POLICY must be %s or %s, got %q", LBRoundRobin, LBLeastRequest, c.Policy)
+	}
+	if c.OutlierFailures < 0 {
+		src.fail("AI_OUTLIER_FAILURES must not be negative")
+	}
+	if c.OutlierFailures > 0 && c.OutlierEjectionTime <= 0 {
+		src.fail("AI_OUTLIER_EJECTION_TIME must be positive")
+	}
+	if c.MaxEjectionPercent < 0 || c.MaxEjectionPercent > 100 {
+		src.fail("AI_OUTLIER_MAX_EJECTION_PERCENT must be between 0 and 100")
+	}
+}
+
+// String şifre gibi gizli değerleri maskeleyerek etkin ayarları yazdırır.
+func (c Config) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "app_env=%s port=%s public_base_url=%s admin_token=%s\n", c.AppEnv, c.Port, c.PublicBaseURL, redact(c.AdminToken))
+	fmt.Fprintf(&b, "db: host=%s port=%s user=%s password=%s name=%s schema=%s sslmode=%s max_open=%d max_idle=%d max_lifetime=%s\n",
+		c.DB.Host, c.DB.Port, c.DB.User, redact(c.DB.Password), c.DB.Name, c.DB.Schema, c.DB.SSLMode,
+		c.DB.MaxOpenConns, c.DB.MaxIdleConns, c.DB.ConnMaxLifetime)
+	fmt.Fprintf(&b, "ai: addrs=%s timeout=%s auth_token=%s\n", strings.Join(c.AI.Addrs, ","), c.AI.Timeout, redact(c.AI.AuthToken))
+	fmt.Fprintf(&b, "ai.lb: policy=%s health_check=%t outlier_failures=%d ejection_time=%s max_ejection=%d%%\n",
+		c.AI.LB.Policy, c.AI.LB.HealthCheck, c.AI.LB.OutlierFailures, c.AI.LB.OutlierEjectionTime, c.AI.LB.MaxEjectionPercent)
+	fmt.Fprintf(&b, "ai.tls: enabled=%t mtls=%t ca=%s cert=%s key=%s server_name=%s reload=%s\n",
+		c.AI.TLS.Enabled, c.AI.TLS.MutualTLS(), c.AI.TLS.CAFile, c.AI.TLS.CertFile, c.AI.TLS.KeyFile,
+		c.AI.TLS.ServerName, c.AI.TLS.ReloadInterval)
+	fmt.Fprintf(&b, "cors: allow_origins=%s\n", strings.Join(c.CORS.AllowOrigins, ","))
+
+	modes := make([]string, 0, len(c.Travel.Speeds))
+	for mode, speed := range c.Travel.Speeds {
+		modes = append(modes, fmt.Sprintf("%s=%g", mode, speed))
+	}
+	sort.Strings(modes)
+	fmt.Fprintf(&b, "travel: default_mode=%s speeds=%s\n", c.Travel.DefaultMode, strings.Join(modes, ","))
+
+	fmt.Fprintf(&b, "location_gc: interval=%s grace_period=%s batch_size=%d\n",
+		c.LocationGC.Interval, c.LocationGC.GracePeriod, c.LocationGC.BatchSize)
+	fmt.Fprintf(&b, "geocoder: url=%s timeout=%s user_agent=%s\n", c.Geocoder.URL, c.Geocoder.Timeout, c.Geocoder.UserAgent)
+
+	var enabled []string
+	for name, on := range c.Features {
+		if on {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	fmt.Fprintf(&b, "features: %s", strings.Join(enabled, ","))
+
+	return b.String()
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "****"
+}
+
+func isPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port < 65536
+}
+
+// source ortam değişkenlerini dosyadaki değerlerin önüne koyar ve
+// ayrıştırma hatalarını biriktirir.
+type source struct {
+	file map[string]string
+	errs []string
+}
+
+func (s *source) fail(format string, args ...interface{}) {
+	s.errs = append(s.errs, fmt.Sprintf(format, args...))
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value, true
+	}
+	value, ok := s.file[key]
+	return value, ok && value != ""
+}
+
+func (s *source) str(key, def string) string {
+	if value, ok := s.lookup(key); ok {
+		return strings.TrimSpace(value)
+	}
+	return def
+}
+
+func (s *source) int(key string, def int) int {
+	value, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		s.fail("%s must be an integer, got %q", key, value)
+		return def
+	}
+	return n
+}
+
+func (s *source) bool(key string, def bool) bool {
+	value, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		s.fail("%s must be true or false, got %q", key, value)
+		return def
+	}
+	return b
+}
+
+func (s *source) duration(key string, def time.Duration) time.Duration {
+	value, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		s.fail("%s must be a duration such as 30s or 5m, got %q", key, value)
+		return def
+	}
+	return d
+}
+
+func (s *source) list(key string, def []string) []string {
+	value, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// speeds "mod=km/sa" çiftlerinden oluşan listeyi okur. Ortam değişkeni
+// verilirse varsayılan liste tamamen değişir.
+func (s *source) speeds(key, def string) map[string]float64 {
+	value, ok := s.lookup(key)
+	if !ok {
+		value = def
+	}
+
+	speeds := map[string]float64{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		mode, raw, found := strings.Cut(item, "=")
+		speed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if !found || err != nil || speed <= 0 || strings.TrimSpace(mode) == "" {
+			s.fail("%s must be a list of mode=km/h with positive speeds, got %q", key, item)
+			continue
+		}
+		speeds[strings.ToLower(strings.TrimSpace(mode))] = speed
+	}
+	return speeds
+}
+
+func (s *source) features(key string) Features {
+	features := Features{}
+	for name, on := range knownFeatures {
+		features[name] = on
+	}
+
+	for _, item := range s.list(key, nil) {
+		on := true
+		if strings.HasPrefix(item, "-") {
+			on = false
+			item = strings.TrimPrefix(item, "-")
+		}
+		if _, known := knownFeatures[item]; !known {
+			s.fail("%s contains unknown feature %q", key, item)
+			continue
+		}
+		features[item] = on
+	}
+	return features
+}
+
+// readEnvFile .env biçimindeki KEY=VALUE satırlarını okur.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return values, scanner.Err()
+}
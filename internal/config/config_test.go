@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setRequired Load'un zorunlu tuttuğu değerleri verir; testler yalnızca
+// denedikleri anahtarları değiştirir.
+func setRequired(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USERNAME", "trip")
+	t.Setenv("DB_DATABASE", "trips")
+	t.Setenv("AI_SERVICE_ADDR", "localhost:50051")
+}
+
+func TestLoadDefaultModeIgnoresCase(t *testing.T) {
+	setRequired(t)
+	t.Setenv("TRAVEL_DEFAULT_MODE", " Caravan ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Travel.DefaultMode != "caravan" {
+		t.Errorf("DefaultMode = %q, want caravan", cfg.Travel.DefaultMode)
+	}
+}
+
+func TestLoadRejectsUnknownDefaultMode(t *testing.T) {
+	setRequired(t)
+	t.Setenv("TRAVEL_DEFAULT_MODE", "boat")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), `TRAVEL_DEFAULT_MODE "boat"`) {
+		t.Fatalf("err = %v, want unknown TRAVEL_DEFAULT_MODE", err)
+	}
+}
+
+// writeConfigFile verilen içeriği CONFIG_FILE olarak ayarlar.
+func writeConfigFile(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setRequired(t)
+	for _, key := range []string{"PORT", "DB_PORT", "AI_TIMEOUT", "AI_LB_POLICY", "TRAVEL_DEFAULT_MODE", "TRAVEL_SPEEDS", "FEATURES"} {
+		t.Setenv(key, "")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Port != "8085" || cfg.DB.Port != "5432" || cfg.AI.Timeout != 250*time.Second || cfg.AI.LB.Policy != LBRoundRobin {
+		t.Errorf("defaults = port %s, db port %s, ai timeout %s, policy %s", cfg.Port, cfg.DB.Port, cfg.AI.Timeout, cfg.AI.LB.Policy)
+	}
+	if cfg.Travel.DefaultMode != "car" || cfg.Travel.Speeds["walking"] != 5 || len(cfg.Travel.Speeds) != 4 {
+		t.Errorf("travel = %+v, want the default modes", cfg.Travel)
+	}
+	for name, on := range knownFeatures {
+		if cfg.Features.Enabled(name) != on {
+			t.Errorf("feature %s = %t, want %t", name, cfg.Features.Enabled(name), on)
+		}
+	}
+}
+
+// Ortam değişkeni dosyadaki değerin önüne geçer; boş ortam değişkeni
+// verilmemiş sayılır ve dosyadaki değer kullanılır.
+func TestLoadEnvOverridesFile(t *testing.T) {
+	setRequired(t)
+	writeConfigFile(t, `# yorum satırı
+DB_HOST=file-host
+PORT=9000
+DB_PASSWORD="dosya şifresi"
+AI_TIMEOUT='45s'
+
+APP_ENV = staging
+`)
+	t.Setenv("DB_HOST", "")
+	t.Setenv("PORT", "9100")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("AI_TIMEOUT", "")
+	t.Setenv("APP_ENV", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DB.Host != "file-host" {
+		t.Errorf("DB.Host = %q, want the file value", cfg.DB.Host)
+	}
+	if cfg.Port != "9100" {
+		t.Errorf("Port = %q, want the environment value", cfg.Port)
+	}
+	if cfg.DB.Password != "dosya şifresi" || cfg.AI.Timeout != 45*time.Second {
+		t.Errorf("password = %q, timeout = %s; want quotes stripped", cfg.DB.Password, cfg.AI.Timeout)
+	}
+	if cfg.AppEnv != "staging" {
+		t.Errorf("AppEnv = %q, want staging", cfg.AppEnv)
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	setRequired(t)
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.env"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Errorf("missing file: err = %v", err)
+	}
+
+	writeConfigFile(t, "DB_HOST=localhost\nnot a pair\n")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "line 2: expected KEY=VALUE") {
+		t.Errorf("malformed file: err = %v", err)
+	}
+}
+
+// Load ilk hatada durmaz; tüm hatalar tek mesajda döner.
+func TestLoadCollectsErrors(t *testing.T) {
+	setRequired(t)
+	t.Setenv("DB_HOST", "")
+	t.Setenv("PORT", "http")
+	t.Setenv("DB_SSLMODE", "sometimes")
+	t.Setenv("AI_TIMEOUT", "soon")
+	t.Setenv("AI_LB_POLICY", "random")
+	t.Setenv("TRAVEL_SPEEDS", "car=fast")
+	t.Setenv("LOCATION_GC_BATCH_SIZE", "0")
+	t.Setenv("CORS_ALLOW_ORIGINS", "*")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load succeeded with an invalid configuration")
+	}
+	for _, want := range []string{
+		"DB_HOST is required",
+		`PORT must be a port number, got "http"`,
+		`DB_SSLMODE must be one of`,
+		`AI_TIMEOUT must be a duration such as 30s or 5m, got "soon"`,
+		`AI_LB_POLICY must be round_robin or least_request, got "random"`,
+		`TRAVEL_SPEEDS must be a list of mode=km/h with positive speeds, got "car=fast"`,
+		"LOCATION_GC_BATCH_SIZE must be at least 1",
+		`CORS_ALLOW_ORIGINS must list explicit origins`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadFeatures(t *testing.T) {
+	setRequired(t)
+	t.Setenv("FEATURES", "request_logging, -fallback_planner")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Features.Enabled("request_logging") || cfg.Features.Enabled("fallback_planner") {
+		t.Errorf("features = %v, want request_logging on and fallback_planner off", cfg.Features)
+	}
+	if !cfg.Features.Enabled("location_gc") {
+		t.Errorf("features = %v, want unlisted features to keep their defaults", cfg.Features)
+	}
+
+	t.Setenv("FEATURES", "request_loging")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), `FEATURES contains unknown feature "request_loging"`) {
+		t.Errorf("err = %v, want the unknown feature", err)
+	}
+}
+
+// String gizli değerleri hiçbir zaman yazmamalı, sadece verilip
+// verilmediklerini göstermeli.
+func TestConfigStringRedactsSecrets(t *testing.T) {
+	setRequired(t)
+	secrets := map[string]string{
+		"DB_PASSWORD":   "db-secret-value",
+		"ADMIN_TOKEN":   "admin-secret-value",
+		"AI_AUTH_TOKEN": "ai-secret-value",
+	}
+	for key, value := range secrets {
+		t.Setenv(key, value)
+	}
+	t.Setenv("AI_TLS_ENABLED", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	out := cfg.String()
+	for key, value := range secrets {
+		if strings.Contains(out, value) {
+			t.Errorf("String() leaks %s:\n%s", key, out)
+		}
+	}
+	for _, want := range []string{"password=****", "admin_token=****", "auth_token=****", "user=trip"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("String() does not contain %q:\n%s", want, out)
+		}
+	}
+
+	cfg.AdminToken = ""
+	if out := cfg.String(); !strings.Contains(out, "admin_token=\n") {
+		t.Errorf("String() should leave an unset secret empty:\n%s", out)
+	}
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS locations_latitude_longitude_idx ON locations (latitude, longitude);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS locations_latitude_longitude_idx;
+-- +goose StatementEnd
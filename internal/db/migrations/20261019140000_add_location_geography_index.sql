@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- PostGIS kuruluysa yakınlık araması için geography index'i oluşturulur;
+-- kurulu değilse (latitude, longitude) B-tree index'i kullanılır.
+-- Eklenti sonradan kurulursa bu migration yeniden çalıştırılmalıdır.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis') THEN
+        EXECUTE 'CREATE INDEX IF NOT EXISTS locations_geography_idx ON locations USING gist '
+             || '((ST_SetSRID(ST_MakePoint(longitude::float8, latitude::float8), 4326)::geography))';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS locations_geography_idx;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- country_code ISO 3166-1 alpha-2, admin_region il/eyalet adıdır.
+-- geocoded_at dolu ama country_code boşsa nokta veri setinin dışında kalmıştır;
+-- backfill country_code boş satırları her çalışmada yeniden dener.
+ALTER TABLE locations
+    ADD COLUMN country_code VARCHAR(2),
+    ADD COLUMN admin_region TEXT,
+    ADD COLUMN geocoded_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS locations_country_code_idx ON locations (country_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS locations_country_code_idx;
+ALTER TABLE locations
+    DROP COLUMN geocoded_at,
+    DROP COLUMN admin_region,
+    DROP COLUMN country_code;
+-- +goose StatementEnd
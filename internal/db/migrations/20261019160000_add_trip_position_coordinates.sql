@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- start_position/end_position metinlerinin geocoder ile çözülmüş koordinatları;
+-- çözülemeyen noktalar için NULL kalır.
+ALTER TABLE trips
+    ADD COLUMN start_latitude DECIMAL(9,6),
+    ADD COLUMN start_longitude DECIMAL(9,6),
+    ADD COLUMN end_latitude DECIMAL(9,6),
+    ADD COLUMN end_longitude DECIMAL(9,6);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE trips
+    DROP COLUMN end_longitude,
+    DROP COLUMN end_latitude,
+    DROP COLUMN start_longitude,
+    DROP COLUMN start_latitude;
+-- +goose StatementEnd
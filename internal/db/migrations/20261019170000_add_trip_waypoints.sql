@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Başlangıç ile bitiş arasında sırayla uğranması gereken ara noktalar;
+-- koordinatı çözülemeyenler için latitude/longitude NULL kalır.
+CREATE TABLE trip_waypoints (
+    trip_id INT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name TEXT NOT NULL,
+    latitude DECIMAL(9,6),
+    longitude DECIMAL(9,6),
+    PRIMARY KEY (trip_id, position)
+);
+
+-- Durağın karşıladığı ara noktanın trip_waypoints.position değeri
+ALTER TABLE trip_locations ADD COLUMN waypoint INT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE trip_locations DROP COLUMN waypoint;
+DROP TABLE IF EXISTS trip_waypoints;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notlar trip'e özeldir; aynı lokasyonu kullanan başka bir kullanıcının
+-- trip'inde görünmemeleri için bağlantı satırına taşınır.
+ALTER TABLE trip_locations ADD COLUMN notes TEXT;
+UPDATE trip_locations tl
+SET notes = l.notes
+FROM locations l
+WHERE l.id = tl.location_id AND l.notes IS NOT NULL;
+ALTER TABLE locations DROP COLUMN notes;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE locations ADD COLUMN notes TEXT;
+UPDATE locations l
+SET notes = (
+    SELECT tl.notes FROM trip_locations tl
+    WHERE tl.location_id = l.id AND tl.notes IS NOT NULL
+    ORDER BY tl.trip_id
+    LIMIT 1
+);
+ALTER TABLE trip_locations DROP COLUMN notes;
+-- +goose StatementEnd
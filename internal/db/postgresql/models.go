@@ -9,15 +9,24 @@ import (
 	"time"
 )
 
-type Location struct {
-	ID        int32
-	Name      string
-	Address   sql.NullString
-	SiteUrl   sql.NullString
-	Notes     sql.NullString
+type CalendarFeedToken struct {
+	UserID    string
+	Token     string
 	CreatedAt sql.NullTime
-	Latitude  sql.NullString
-	Longitude sql.NullString
+}
+
+type Location struct {
+	ID          int32
+	Name        string
+	Address     sql.NullString
+	SiteUrl     sql.NullString
+	CreatedAt   sql.NullTime
+	Latitude    sql.NullString
+	Longitude   sql.NullString
+	InCatalogue bool
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
+	GeocodedAt  sql.NullTime
 }
 
 type Trip struct {
@@ -32,10 +41,26 @@ type Trip struct {
 	StartPosition  sql.NullString
 	FinishPosition sql.NullString
 	EndPosition    sql.NullString
+	StartLatitude  sql.NullString
+	StartLongitude sql.NullString
+	EndLatitude    sql.NullString
+	EndLongitude   sql.NullString
 }
 
 type TripLocation struct {
 	TripID     int32
 	LocationID int32
 	Position   int32
+	Day        sql.NullInt32
+	Pinned     bool
+	Waypoint   sql.NullInt32
+	Notes      sql.NullString
+}
+
+type TripWaypoint struct {
+	TripID    int32
+	Position  int32
+	Name      string
+	Latitude  sql.NullString
+	Longitude sql.NullString
 }
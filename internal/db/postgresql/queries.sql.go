@@ -9,63 +9,173 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const addLocationToTrip = `-- name: AddLocationToTrip :exec
 
-INSERT INTO trip_locations (trip_id, location_id, position)
-VALUES ($1, $2, $3)
+INSERT INTO trip_locations (trip_id, location_id, position, day, pinned, waypoint, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type AddLocationToTripParams struct {
 	TripID     int32
 	LocationID int32
 	Position   int32
+	Day        sql.NullInt32
+	Pinned     bool
+	Waypoint   sql.NullInt32
+	Notes      sql.NullString
 }
 
 // trip_locations.sql (İlişkisel Sorgular)
 func (q *Queries) AddLocationToTrip(ctx context.Context, arg AddLocationToTripParams) error {
-	_, err := q.db.ExecContext(ctx, addLocationToTrip, arg.TripID, arg.LocationID, arg.Position)
+	_, err := q.db.ExecContext(ctx, addLocationToTrip,
+		arg.TripID,
+		arg.LocationID,
+		arg.Position,
+		arg.Day,
+		arg.Pinned,
+		arg.Waypoint,
+		arg.Notes,
+	)
 	return err
 }
 
-const createLocation = `-- name: CreateLocation :one
+const addTripWaypoint = `-- name: AddTripWaypoint :exec
 
-INSERT INTO locations (name, address, site_url, notes, latitude, longitude)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, name, address, site_url, notes, latitude, longitude, created_at
+INSERT INTO trip_waypoints (trip_id, position, name, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5)
 `
 
-type CreateLocationParams struct {
+type AddTripWaypointParams struct {
+	TripID    int32
+	Position  int32
 	Name      string
-	Address   sql.NullString
-	SiteUrl   sql.NullString
-	Notes     sql.NullString
 	Latitude  sql.NullString
 	Longitude sql.NullString
 }
 
+// trip_waypoints.sql (Ara noktalar)
+func (q *Queries) AddTripWaypoint(ctx context.Context, arg AddTripWaypointParams) error {
+	_, err := q.db.ExecContext(ctx, addTripWaypoint,
+		arg.TripID,
+		arg.Position,
+		arg.Name,
+		arg.Latitude,
+		arg.Longitude,
+	)
+	return err
+}
+
+const carryOverLocationInCatalogue = `-- name: CarryOverLocationInCatalogue :exec
+UPDATE locations
+SET in_catalogue = true
+WHERE id = $1
+  AND EXISTS (
+    SELECT 1 FROM locations d
+    WHERE d.id = ANY($2::int[])
+      AND d.in_catalogue
+  )
+`
+
+type CarryOverLocationInCatalogueParams struct {
+	CanonicalID  int32
+	DuplicateIds []int32
+}
+
+// Silinecek kopyalardan biri katalogdaysa kanonik lokasyonu da kataloğa alır.
+func (q *Queries) CarryOverLocationInCatalogue(ctx context.Context, arg CarryOverLocationInCatalogueParams) error {
+	_, err := q.db.ExecContext(ctx, carryOverLocationInCatalogue, arg.CanonicalID, pq.Array(arg.DuplicateIds))
+	return err
+}
+
+const countLocations = `-- name: CountLocations :one
+SELECT COUNT(*)
+FROM locations l
+WHERE $1::text = '' OR l.name ILIKE '%' || $1::text || '%'
+`
+
+func (q *Queries) CountLocations(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLocations, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOrphanedLocations = `-- name: CountOrphanedLocations :one
+SELECT COUNT(*) FROM (
+    SELECT l.id FROM locations l
+    WHERE l.id = ANY($1::int[])
+      AND NOT l.in_catalogue
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    FOR UPDATE SKIP LOCKED
+) orphans
+`
+
+// DeleteOrphanedLocations'ın sileceği satırları sayar.
+func (q *Queries) CountOrphanedLocations(ctx context.Context, locationIds []int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOrphanedLocations, pq.Array(locationIds))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTripsByUserID = `-- name: CountTripsByUserID :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = $1
+`
+
+func (q *Queries) CountTripsByUserID(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTripsByUserID, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLocation = `-- name: CreateLocation :one
+
+INSERT INTO locations (name, address, site_url, latitude, longitude, country_code, admin_region, geocoded_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, LOCALTIMESTAMP)
+RETURNING id, name, address, site_url, latitude, longitude, created_at, country_code, admin_region
+`
+
+type CreateLocationParams struct {
+	Name        string
+	Address     sql.NullString
+	SiteUrl     sql.NullString
+	Latitude    sql.NullString
+	Longitude   sql.NullString
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
+}
+
 type CreateLocationRow struct {
-	ID        int32
-	Name      string
-	Address   sql.NullString
-	SiteUrl   sql.NullString
-	Notes     sql.NullString
-	Latitude  sql.NullString
-	Longitude sql.NullString
-	CreatedAt sql.NullTime
+	ID          int32
+	Name        string
+	Address     sql.NullString
+	SiteUrl     sql.NullString
+	Latitude    sql.NullString
+	Longitude   sql.NullString
+	CreatedAt   sql.NullTime
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
 }
 
 // locations.sql
 // GÜNCELLENDİ: latitude ve longitude eklendi. Parametre sayıları arttı ($4 -> $6).
+// GÜNCELLENDİ: Ülke/bölge etiketleri eklendi ($6 -> $8).
+// GÜNCELLENDİ: Notlar trip_locations'a taşındı ($8 -> $7).
 func (q *Queries) CreateLocation(ctx context.Context, arg CreateLocationParams) (CreateLocationRow, error) {
 	row := q.db.QueryRowContext(ctx, createLocation,
 		arg.Name,
 		arg.Address,
 		arg.SiteUrl,
-		arg.Notes,
 		arg.Latitude,
 		arg.Longitude,
+		arg.CountryCode,
+		arg.AdminRegion,
 	)
 	var i CreateLocationRow
 	err := row.Scan(
@@ -73,29 +183,35 @@ func (q *Queries) CreateLocation(ctx context.Context, arg CreateLocationParams)
 		&i.Name,
 		&i.Address,
 		&i.SiteUrl,
-		&i.Notes,
 		&i.Latitude,
 		&i.Longitude,
 		&i.CreatedAt,
+		&i.CountryCode,
+		&i.AdminRegion,
 	)
 	return i, err
 }
 
 const createTrip = `-- name: CreateTrip :one
 
-INSERT INTO trips (user_id, name, description, start_date, end_date, start_position, end_position)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO trips (user_id, name, description, start_date, end_date, start_position, end_position,
+                   start_latitude, start_longitude, end_latitude, end_longitude)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
 `
 
 type CreateTripParams struct {
-	UserID        string
-	Name          string
-	Description   sql.NullString
-	StartDate     time.Time
-	EndDate       time.Time
-	StartPosition sql.NullString
-	EndPosition   sql.NullString
+	UserID         string
+	Name           string
+	Description    sql.NullString
+	StartDate      time.Time
+	EndDate        time.Time
+	StartPosition  sql.NullString
+	EndPosition    sql.NullString
+	StartLatitude  sql.NullString
+	StartLongitude sql.NullString
+	EndLatitude    sql.NullString
+	EndLongitude   sql.NullString
 }
 
 type CreateTripRow struct {
@@ -113,6 +229,7 @@ type CreateTripRow struct {
 
 // trips.sql
 // DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
+// GÜNCELLENDİ: Başlangıç/bitiş koordinatları eklendi ($7 -> $11).
 func (q *Queries) CreateTrip(ctx context.Context, arg CreateTripParams) (CreateTripRow, error) {
 	row := q.db.QueryRowContext(ctx, createTrip,
 		arg.UserID,
@@ -122,6 +239,10 @@ func (q *Queries) CreateTrip(ctx context.Context, arg CreateTripParams) (CreateT
 		arg.EndDate,
 		arg.StartPosition,
 		arg.EndPosition,
+		arg.StartLatitude,
+		arg.StartLongitude,
+		arg.EndLatitude,
+		arg.EndLongitude,
 	)
 	var i CreateTripRow
 	err := row.Scan(
@@ -139,6 +260,110 @@ func (q *Queries) CreateTrip(ctx context.Context, arg CreateTripParams) (CreateT
 	return i, err
 }
 
+const deleteCalendarTokenByUserID = `-- name: DeleteCalendarTokenByUserID :execrows
+DELETE FROM calendar_feed_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteCalendarTokenByUserID(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCalendarTokenByUserID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDuplicateTripLocations = `-- name: DeleteDuplicateTripLocations :execrows
+
+DELETE FROM trip_locations tl
+WHERE tl.location_id = ANY($1::int[])
+  AND EXISTS (
+    SELECT 1 FROM trip_locations o
+    WHERE o.trip_id = tl.trip_id
+      AND (o.location_id = $2
+        OR (o.location_id = ANY($1::int[]) AND o.location_id < tl.location_id))
+  )
+`
+
+type DeleteDuplicateTripLocationsParams struct {
+	DuplicateIds []int32
+	CanonicalID  int32
+}
+
+// location_merge.sql (Admin: kopya lokasyonları birleştirme)
+// Taşıma sırasında birincil anahtar çakışmasın diye, aynı trip'te kanonik
+// lokasyonla ya da daha küçük ID'li başka bir kopyayla birlikte duran kopya
+// bağlantılarını siler.
+func (q *Queries) DeleteDuplicateTripLocations(ctx context.Context, arg DeleteDuplicateTripLocationsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDuplicateTripLocations, pq.Array(arg.DuplicateIds), arg.CanonicalID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLocationsByID = `-- name: DeleteLocationsByID :execrows
+DELETE FROM locations
+WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) DeleteLocationsByID(ctx context.Context, locationIds []int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteLocationsByID, pq.Array(locationIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOrphanedLocations = `-- name: DeleteOrphanedLocations :execrows
+DELETE FROM locations
+WHERE id IN (
+    SELECT l.id FROM locations l
+    WHERE l.id = ANY($1::int[])
+      AND NOT l.in_catalogue
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    FOR UPDATE SKIP LOCKED
+)
+`
+
+// Verilen lokasyonlardan artık hiçbir trip'e bağlı olmayanları siler.
+// Katalogdaki lokasyonlar ve başka bir transaction'ın kilitlediği (ör. o an
+// başka bir kullanıcının trip'ine bağlanan) satırlar atlanır.
+func (q *Queries) DeleteOrphanedLocations(ctx context.Context, locationIds []int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrphanedLocations, pq.Array(locationIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOrphanedLocationsBatch = `-- name: DeleteOrphanedLocationsBatch :execrows
+DELETE FROM locations
+WHERE id IN (
+    SELECT l.id FROM locations l
+    WHERE NOT l.in_catalogue
+      AND (l.created_at IS NULL OR l.created_at < LOCALTIMESTAMP - make_interval(secs => $1::float8))
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    ORDER BY l.id
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+`
+
+type DeleteOrphanedLocationsBatchParams struct {
+	GraceSeconds float64
+	BatchSize    int32
+}
+
+// Başka bir transaction'ın kilitlediği (ör. o an trip'e bağlanan) satırlar atlanır.
+func (q *Queries) DeleteOrphanedLocationsBatch(ctx context.Context, arg DeleteOrphanedLocationsBatchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrphanedLocationsBatch, arg.GraceSeconds, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteTrip = `-- name: DeleteTrip :exec
 DELETE FROM trips
 WHERE id = $1
@@ -149,8 +374,60 @@ func (q *Queries) DeleteTrip(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteTripLocationsByUserID = `-- name: DeleteTripLocationsByUserID :execrows
+DELETE FROM trip_locations
+WHERE trip_id IN (SELECT id FROM trips WHERE user_id = $1)
+`
+
+func (q *Queries) DeleteTripLocationsByUserID(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTripLocationsByUserID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTripsByUserID = `-- name: DeleteTripsByUserID :execrows
+DELETE FROM trips
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTripsByUserID(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTripsByUserID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCalendarTokenByToken = `-- name: GetCalendarTokenByToken :one
+SELECT user_id, token, created_at
+FROM calendar_feed_tokens
+WHERE token = $1
+`
+
+func (q *Queries) GetCalendarTokenByToken(ctx context.Context, token string) (CalendarFeedToken, error) {
+	row := q.db.QueryRowContext(ctx, getCalendarTokenByToken, token)
+	var i CalendarFeedToken
+	err := row.Scan(&i.UserID, &i.Token, &i.CreatedAt)
+	return i, err
+}
+
+const getCalendarTokenByUserID = `-- name: GetCalendarTokenByUserID :one
+SELECT user_id, token, created_at
+FROM calendar_feed_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) GetCalendarTokenByUserID(ctx context.Context, userID string) (CalendarFeedToken, error) {
+	row := q.db.QueryRowContext(ctx, getCalendarTokenByUserID, userID)
+	var i CalendarFeedToken
+	err := row.Scan(&i.UserID, &i.Token, &i.CreatedAt)
+	return i, err
+}
+
 const getLocationByID = `-- name: GetLocationByID :one
-SELECT id, name, address, site_url, notes, latitude, longitude, created_at
+SELECT id, name, address, site_url, latitude, longitude, created_at
 FROM locations
 WHERE id = $1
 `
@@ -160,7 +437,6 @@ type GetLocationByIDRow struct {
 	Name      string
 	Address   sql.NullString
 	SiteUrl   sql.NullString
-	Notes     sql.NullString
 	Latitude  sql.NullString
 	Longitude sql.NullString
 	CreatedAt sql.NullTime
@@ -175,7 +451,6 @@ func (q *Queries) GetLocationByID(ctx context.Context, id int32) (GetLocationByI
 		&i.Name,
 		&i.Address,
 		&i.SiteUrl,
-		&i.Notes,
 		&i.Latitude,
 		&i.Longitude,
 		&i.CreatedAt,
@@ -183,23 +458,56 @@ func (q *Queries) GetLocationByID(ctx context.Context, id int32) (GetLocationByI
 	return i, err
 }
 
+const getOrphanedLocationStats = `-- name: GetOrphanedLocationStats :one
+
+SELECT
+    COUNT(*) FILTER (WHERE NOT l.in_catalogue
+        AND (l.created_at IS NULL OR l.created_at < LOCALTIMESTAMP - make_interval(secs => $1::float8))) AS eligible,
+    COUNT(*) FILTER (WHERE NOT l.in_catalogue
+        AND l.created_at >= LOCALTIMESTAMP - make_interval(secs => $1::float8)) AS in_grace_period,
+    COUNT(*) FILTER (WHERE l.in_catalogue) AS in_catalogue
+FROM locations l
+WHERE NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+`
+
+type GetOrphanedLocationStatsRow struct {
+	Eligible      int64
+	InGracePeriod int64
+	InCatalogue   int64
+}
+
+// location_gc.sql (Sahipsiz lokasyon temizliği)
+// Hiçbir trip'e bağlı olmayan lokasyonları silinebilir, bekleme süresinde ve
+// katalogda olarak sayar.
+func (q *Queries) GetOrphanedLocationStats(ctx context.Context, graceSeconds float64) (GetOrphanedLocationStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getOrphanedLocationStats, graceSeconds)
+	var i GetOrphanedLocationStatsRow
+	err := row.Scan(&i.Eligible, &i.InGracePeriod, &i.InCatalogue)
+	return i, err
+}
+
 const getTripByID = `-- name: GetTripByID :one
-SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at,
+       start_latitude, start_longitude, end_latitude, end_longitude
 FROM trips
 WHERE id = $1
 `
 
 type GetTripByIDRow struct {
-	ID            int32
-	UserID        string
-	Name          string
-	Description   sql.NullString
-	StartDate     time.Time
-	EndDate       time.Time
-	StartPosition sql.NullString
-	EndPosition   sql.NullString
-	CreatedAt     sql.NullTime
-	UpdatedAt     sql.NullTime
+	ID             int32
+	UserID         string
+	Name           string
+	Description    sql.NullString
+	StartDate      time.Time
+	EndDate        time.Time
+	StartPosition  sql.NullString
+	EndPosition    sql.NullString
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	StartLatitude  sql.NullString
+	StartLongitude sql.NullString
+	EndLatitude    sql.NullString
+	EndLongitude   sql.NullString
 }
 
 // DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
@@ -217,12 +525,16 @@ func (q *Queries) GetTripByID(ctx context.Context, id int32) (GetTripByIDRow, er
 		&i.EndPosition,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StartLatitude,
+		&i.StartLongitude,
+		&i.EndLatitude,
+		&i.EndLongitude,
 	)
 	return i, err
 }
 
 const getTripLocations = `-- name: GetTripLocations :many
-SELECT l.id, l.name, l.address, l.site_url, l.notes, l.latitude, l.longitude, l.created_at, tl.position
+SELECT l.id, l.name, l.address, l.site_url, tl.notes, l.latitude, l.longitude, l.created_at, l.country_code, l.admin_region, tl.position, tl.day, tl.pinned, tl.waypoint
 FROM locations l
 JOIN trip_locations tl ON l.id = tl.location_id
 WHERE tl.trip_id = $1
@@ -230,18 +542,24 @@ ORDER BY tl.position
 `
 
 type GetTripLocationsRow struct {
-	ID        int32
-	Name      string
-	Address   sql.NullString
-	SiteUrl   sql.NullString
-	Notes     sql.NullString
-	Latitude  sql.NullString
-	Longitude sql.NullString
-	CreatedAt sql.NullTime
-	Position  int32
+	ID          int32
+	Name        string
+	Address     sql.NullString
+	SiteUrl     sql.NullString
+	Notes       sql.NullString
+	Latitude    sql.NullString
+	Longitude   sql.NullString
+	CreatedAt   sql.NullTime
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
+	Position    int32
+	Day         sql.NullInt32
+	Pinned      bool
+	Waypoint    sql.NullInt32
 }
 
 // GÜNCELLENDİ: "l.*" yerine tüm location kolonları açıkça yazılarak yeni kolonlar eklendi.
+// Notlar trip'e özeldir ve bağlantı satırından okunur.
 func (q *Queries) GetTripLocations(ctx context.Context, tripID int32) ([]GetTripLocationsRow, error) {
 	rows, err := q.db.QueryContext(ctx, getTripLocations, tripID)
 	if err != nil {
@@ -260,7 +578,54 @@ func (q *Queries) GetTripLocations(ctx context.Context, tripID int32) ([]GetTrip
 			&i.Latitude,
 			&i.Longitude,
 			&i.CreatedAt,
+			&i.CountryCode,
+			&i.AdminRegion,
+			&i.Position,
+			&i.Day,
+			&i.Pinned,
+			&i.Waypoint,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTripWaypoints = `-- name: GetTripWaypoints :many
+SELECT position, name, latitude, longitude
+FROM trip_waypoints
+WHERE trip_id = $1
+ORDER BY position
+`
+
+type GetTripWaypointsRow struct {
+	Position  int32
+	Name      string
+	Latitude  sql.NullString
+	Longitude sql.NullString
+}
+
+func (q *Queries) GetTripWaypoints(ctx context.Context, tripID int32) ([]GetTripWaypointsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTripWaypoints, tripID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTripWaypointsRow
+	for rows.Next() {
+		var i GetTripWaypointsRow
+		if err := rows.Scan(
 			&i.Position,
+			&i.Name,
+			&i.Latitude,
+			&i.Longitude,
 		); err != nil {
 			return nil, err
 		}
@@ -275,39 +640,148 @@ func (q *Queries) GetTripLocations(ctx context.Context, tripID int32) ([]GetTrip
 	return items, nil
 }
 
+const listLocationIDsByUserID = `-- name: ListLocationIDsByUserID :many
+
+SELECT DISTINCT tl.location_id
+FROM trip_locations tl
+JOIN trips t ON t.id = tl.trip_id
+WHERE t.user_id = $1
+ORDER BY tl.location_id
+`
+
+// account.sql (GDPR export/silme)
+func (q *Queries) ListLocationIDsByUserID(ctx context.Context, userID string) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, listLocationIDsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var location_id int32
+		if err := rows.Scan(&location_id); err != nil {
+			return nil, err
+		}
+		items = append(items, location_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLocations = `-- name: ListLocations :many
-SELECT id, name, address, site_url, notes, latitude, longitude, created_at
+SELECT l.id, l.name, l.site_url, l.latitude, l.longitude, l.created_at, l.in_catalogue, l.country_code, l.admin_region,
+       (SELECT COUNT(*) FROM trip_locations tl WHERE tl.location_id = l.id) AS trip_count
+FROM locations l
+WHERE $1::text = '' OR l.name ILIKE '%' || $1::text || '%'
+ORDER BY l.name, l.id
+LIMIT $2 OFFSET $3
+`
+
+type ListLocationsParams struct {
+	Query      string
+	PageLimit  int32
+	PageOffset int32
+}
+
+type ListLocationsRow struct {
+	ID          int32
+	Name        string
+	SiteUrl     sql.NullString
+	Latitude    sql.NullString
+	Longitude   sql.NullString
+	CreatedAt   sql.NullTime
+	InCatalogue bool
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
+	TripCount   int64
+}
+
+// GÜNCELLENDİ: Katalog araması için isim filtresi, sayfalama ve kullanım sayısı eklendi.
+// Katalog herkese açıktır; kullanıcıların girdiği adresler listelenmez.
+func (q *Queries) ListLocations(ctx context.Context, arg ListLocationsParams) ([]ListLocationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLocations, arg.Query, arg.PageLimit, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLocationsRow
+	for rows.Next() {
+		var i ListLocationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.SiteUrl,
+			&i.Latitude,
+			&i.Longitude,
+			&i.CreatedAt,
+			&i.InCatalogue,
+			&i.CountryCode,
+			&i.AdminRegion,
+			&i.TripCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLocationsInBox = `-- name: ListLocationsInBox :many
+SELECT id, name, address, site_url, latitude, longitude, created_at
 FROM locations
+WHERE latitude BETWEEN $1::numeric AND $2::numeric
+  AND longitude BETWEEN $3::numeric AND $4::numeric
 ORDER BY id
 `
 
-type ListLocationsRow struct {
+type ListLocationsInBoxParams struct {
+	MinLatitude  string
+	MaxLatitude  string
+	MinLongitude string
+	MaxLongitude string
+}
+
+type ListLocationsInBoxRow struct {
 	ID        int32
 	Name      string
 	Address   sql.NullString
 	SiteUrl   sql.NullString
-	Notes     sql.NullString
 	Latitude  sql.NullString
 	Longitude sql.NullString
 	CreatedAt sql.NullTime
 }
 
-// GÜNCELLENDİ: "*" yerine tüm kolonlar açıkça yazılarak yeni kolonlar eklendi.
-func (q *Queries) ListLocations(ctx context.Context) ([]ListLocationsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listLocations)
+// (latitude, longitude) index'ini kullanır; kesin mesafe kontrolü uygulamada yapılır.
+func (q *Queries) ListLocationsInBox(ctx context.Context, arg ListLocationsInBoxParams) ([]ListLocationsInBoxRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLocationsInBox,
+		arg.MinLatitude,
+		arg.MaxLatitude,
+		arg.MinLongitude,
+		arg.MaxLongitude,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListLocationsRow
+	var items []ListLocationsInBoxRow
 	for rows.Next() {
-		var i ListLocationsRow
+		var i ListLocationsInBoxRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.Address,
 			&i.SiteUrl,
-			&i.Notes,
 			&i.Latitude,
 			&i.Longitude,
 			&i.CreatedAt,
@@ -325,14 +799,203 @@ func (q *Queries) ListLocations(ctx context.Context) ([]ListLocationsRow, error)
 	return items, nil
 }
 
+const listLocationsToGeocode = `-- name: ListLocationsToGeocode :many
+
+SELECT id, latitude, longitude
+FROM locations
+WHERE id > $1
+  AND latitude IS NOT NULL AND longitude IS NOT NULL
+  AND ($2::bool OR country_code IS NULL)
+ORDER BY id
+LIMIT $3
+`
+
+type ListLocationsToGeocodeParams struct {
+	AfterID   int32
+	AllRows   bool
+	BatchSize int32
+}
+
+type ListLocationsToGeocodeRow struct {
+	ID        int32
+	Latitude  sql.NullString
+	Longitude sql.NullString
+}
+
+// location_region.sql (Ülke/bölge etiketleme)
+// Koordinatı olan lokasyonları ID sırasıyla sayfalar; all_rows false ise
+// yalnızca henüz bir ülkeye atanmamış olanlar döner. Veri setinin dışında
+// kalan noktalar böylece veri seti genişledikçe yeniden denenir.
+func (q *Queries) ListLocationsToGeocode(ctx context.Context, arg ListLocationsToGeocodeParams) ([]ListLocationsToGeocodeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLocationsToGeocode, arg.AfterID, arg.AllRows, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLocationsToGeocodeRow
+	for rows.Next() {
+		var i ListLocationsToGeocodeRow
+		if err := rows.Scan(&i.ID, &i.Latitude, &i.Longitude); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTripsByUserID = `-- name: ListTripsByUserID :many
-SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at,
+       start_latitude, start_longitude, end_latitude, end_longitude
 FROM trips
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
 
 type ListTripsByUserIDRow struct {
+	ID             int32
+	UserID         string
+	Name           string
+	Description    sql.NullString
+	StartDate      time.Time
+	EndDate        time.Time
+	StartPosition  sql.NullString
+	EndPosition    sql.NullString
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	StartLatitude  sql.NullString
+	StartLongitude sql.NullString
+	EndLatitude    sql.NullString
+	EndLongitude   sql.NullString
+}
+
+// DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
+func (q *Queries) ListTripsByUserID(ctx context.Context, userID string) ([]ListTripsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTripsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTripsByUserIDRow
+	for rows.Next() {
+		var i ListTripsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.StartDate,
+			&i.EndDate,
+			&i.StartPosition,
+			&i.EndPosition,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartLatitude,
+			&i.StartLongitude,
+			&i.EndLatitude,
+			&i.EndLongitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTripsByUserIDAndCountries = `-- name: ListTripsByUserIDAndCountries :many
+SELECT t.id, t.user_id, t.name, t.description, t.start_date, t.end_date, t.start_position, t.end_position, t.created_at, t.updated_at,
+       t.start_latitude, t.start_longitude, t.end_latitude, t.end_longitude
+FROM trips t
+WHERE t.user_id = $1
+  AND EXISTS (
+    SELECT 1 FROM trip_locations tl
+    JOIN locations l ON l.id = tl.location_id
+    WHERE tl.trip_id = t.id AND l.country_code = ANY($2::text[])
+  )
+ORDER BY t.created_at DESC
+`
+
+type ListTripsByUserIDAndCountriesParams struct {
+	UserID       string
+	CountryCodes []string
+}
+
+type ListTripsByUserIDAndCountriesRow struct {
+	ID             int32
+	UserID         string
+	Name           string
+	Description    sql.NullString
+	StartDate      time.Time
+	EndDate        time.Time
+	StartPosition  sql.NullString
+	EndPosition    sql.NullString
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+	StartLatitude  sql.NullString
+	StartLongitude sql.NullString
+	EndLatitude    sql.NullString
+	EndLongitude   sql.NullString
+}
+
+// Verilen ülkelerden en az birinde lokasyonu olan trip'leri döner.
+func (q *Queries) ListTripsByUserIDAndCountries(ctx context.Context, arg ListTripsByUserIDAndCountriesParams) ([]ListTripsByUserIDAndCountriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTripsByUserIDAndCountries, arg.UserID, pq.Array(arg.CountryCodes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTripsByUserIDAndCountriesRow
+	for rows.Next() {
+		var i ListTripsByUserIDAndCountriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.StartDate,
+			&i.EndDate,
+			&i.StartPosition,
+			&i.EndPosition,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartLatitude,
+			&i.StartLongitude,
+			&i.EndLatitude,
+			&i.EndLongitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingTripsByUserID = `-- name: ListUpcomingTripsByUserID :many
+
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+FROM trips
+WHERE user_id = $1 AND end_date >= CURRENT_DATE
+ORDER BY start_date
+`
+
+type ListUpcomingTripsByUserIDRow struct {
 	ID            int32
 	UserID        string
 	Name          string
@@ -345,16 +1008,100 @@ type ListTripsByUserIDRow struct {
 	UpdatedAt     sql.NullTime
 }
 
-// DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
-func (q *Queries) ListTripsByUserID(ctx context.Context, userID string) ([]ListTripsByUserIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, listTripsByUserID, userID)
+// calendar.sql
+func (q *Queries) ListUpcomingTripsByUserID(ctx context.Context, userID string) ([]ListUpcomingTripsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUpcomingTripsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListTripsByUserIDRow
+	var items []ListUpcomingTripsByUserIDRow
 	for rows.Next() {
-		var i ListTripsByUserIDRow
+		var i ListUpcomingTripsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.StartDate,
+			&i.EndDate,
+			&i.StartPosition,
+			&i.EndPosition,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTripsInBox = `-- name: ListUserTripsInBox :many
+
+SELECT t.id, t.user_id, t.name, t.description, t.start_date, t.end_date, t.start_position, t.end_position, t.created_at, t.updated_at,
+       COUNT(*) AS matching_locations
+FROM trips t
+JOIN trip_locations tl ON tl.trip_id = t.id
+JOIN locations l ON l.id = tl.location_id
+WHERE t.user_id = $1
+  AND l.latitude BETWEEN $2::numeric AND $3::numeric
+  AND (
+    ($4::numeric <= $5::numeric
+        AND l.longitude BETWEEN $4::numeric AND $5::numeric)
+    OR ($4::numeric > $5::numeric
+        AND (l.longitude >= $4::numeric OR l.longitude <= $5::numeric))
+  )
+GROUP BY t.id
+ORDER BY t.start_date DESC, t.id
+`
+
+type ListUserTripsInBoxParams struct {
+	UserID       string
+	MinLatitude  string
+	MaxLatitude  string
+	MinLongitude string
+	MaxLongitude string
+}
+
+type ListUserTripsInBoxRow struct {
+	ID                int32
+	UserID            string
+	Name              string
+	Description       sql.NullString
+	StartDate         time.Time
+	EndDate           time.Time
+	StartPosition     sql.NullString
+	EndPosition       sql.NullString
+	CreatedAt         sql.NullTime
+	UpdatedAt         sql.NullTime
+	MatchingLocations int64
+}
+
+// spatial.sql (Alan araması)
+// Kullanıcının en az bir lokasyonu kutunun içinde kalan trip'lerini döner.
+// min_longitude > max_longitude ise kutu 180. meridyeni geçer.
+func (q *Queries) ListUserTripsInBox(ctx context.Context, arg ListUserTripsInBoxParams) ([]ListUserTripsInBoxRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTripsInBox,
+		arg.UserID,
+		arg.MinLatitude,
+		arg.MaxLatitude,
+		arg.MinLongitude,
+		arg.MaxLongitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserTripsInBoxRow
+	for rows.Next() {
+		var i ListUserTripsInBoxRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -366,6 +1113,7 @@ func (q *Queries) ListTripsByUserID(ctx context.Context, userID string) ([]ListT
 			&i.EndPosition,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MatchingLocations,
 		); err != nil {
 			return nil, err
 		}
@@ -380,6 +1128,34 @@ func (q *Queries) ListTripsByUserID(ctx context.Context, userID string) ([]ListT
 	return items, nil
 }
 
+const lockLocationByID = `-- name: LockLocationByID :one
+SELECT id FROM locations
+WHERE id = $1
+FOR KEY SHARE
+`
+
+// Yeniden kullanılacak lokasyonu transaction sonuna kadar sahipsiz lokasyon
+// temizliğine karşı kilitler; satır bu arada silindiyse sonuç dönmez.
+func (q *Queries) LockLocationByID(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, lockLocationByID, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const removeAllLocationsFromTrip = `-- name: RemoveAllLocationsFromTrip :execrows
+DELETE FROM trip_locations
+WHERE trip_id = $1
+`
+
+// Trip'in tüm lokasyon bağlantılarını siler; lokasyonların kendisi kalır.
+func (q *Queries) RemoveAllLocationsFromTrip(ctx context.Context, tripID int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeAllLocationsFromTrip, tripID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const removeLocationFromTrip = `-- name: RemoveLocationFromTrip :exec
 DELETE FROM trip_locations
 WHERE trip_id = $1 AND location_id = $2
@@ -394,3 +1170,154 @@ func (q *Queries) RemoveLocationFromTrip(ctx context.Context, arg RemoveLocation
 	_, err := q.db.ExecContext(ctx, removeLocationFromTrip, arg.TripID, arg.LocationID)
 	return err
 }
+
+const repointTripLocations = `-- name: RepointTripLocations :execrows
+UPDATE trip_locations
+SET location_id = $1
+WHERE location_id = ANY($2::int[])
+`
+
+type RepointTripLocationsParams struct {
+	CanonicalID  int32
+	DuplicateIds []int32
+}
+
+func (q *Queries) RepointTripLocations(ctx context.Context, arg RepointTripLocationsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, repointTripLocations, arg.CanonicalID, pq.Array(arg.DuplicateIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setLocationInCatalogue = `-- name: SetLocationInCatalogue :execrows
+UPDATE locations
+SET in_catalogue = $2
+WHERE id = $1
+`
+
+type SetLocationInCatalogueParams struct {
+	ID          int32
+	InCatalogue bool
+}
+
+func (q *Queries) SetLocationInCatalogue(ctx context.Context, arg SetLocationInCatalogueParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setLocationInCatalogue, arg.ID, arg.InCatalogue)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setLocationRegion = `-- name: SetLocationRegion :execrows
+UPDATE locations
+SET country_code = $1, admin_region = $2, geocoded_at = LOCALTIMESTAMP
+WHERE id = $3
+`
+
+type SetLocationRegionParams struct {
+	CountryCode sql.NullString
+	AdminRegion sql.NullString
+	ID          int32
+}
+
+func (q *Queries) SetLocationRegion(ctx context.Context, arg SetLocationRegionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setLocationRegion, arg.CountryCode, arg.AdminRegion, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTripLocationPin = `-- name: SetTripLocationPin :execrows
+UPDATE trip_locations
+SET day = COALESCE($1, day), pinned = $2
+WHERE trip_id = $3 AND location_id = $4
+`
+
+type SetTripLocationPinParams struct {
+	Day        sql.NullInt32
+	Pinned     bool
+	TripID     int32
+	LocationID int32
+}
+
+// Lokasyonu bir güne sabitler ya da sabitlemeyi kaldırır (day NULL ise gün değişmez).
+func (q *Queries) SetTripLocationPin(ctx context.Context, arg SetTripLocationPinParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setTripLocationPin,
+		arg.Day,
+		arg.Pinned,
+		arg.TripID,
+		arg.LocationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateTripLocationPosition = `-- name: UpdateTripLocationPosition :execrows
+UPDATE trip_locations
+SET position = $3
+WHERE trip_id = $1 AND location_id = $2
+`
+
+type UpdateTripLocationPositionParams struct {
+	TripID     int32
+	LocationID int32
+	Position   int32
+}
+
+func (q *Queries) UpdateTripLocationPosition(ctx context.Context, arg UpdateTripLocationPositionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateTripLocationPosition, arg.TripID, arg.LocationID, arg.Position)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateTripLocationSchedule = `-- name: UpdateTripLocationSchedule :execrows
+UPDATE trip_locations
+SET position = $3, day = $4
+WHERE trip_id = $1 AND location_id = $2
+`
+
+type UpdateTripLocationScheduleParams struct {
+	TripID     int32
+	LocationID int32
+	Position   int32
+	Day        sql.NullInt32
+}
+
+func (q *Queries) UpdateTripLocationSchedule(ctx context.Context, arg UpdateTripLocationScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateTripLocationSchedule,
+		arg.TripID,
+		arg.LocationID,
+		arg.Position,
+		arg.Day,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertCalendarToken = `-- name: UpsertCalendarToken :one
+INSERT INTO calendar_feed_tokens (user_id, token)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP
+RETURNING user_id, token, created_at
+`
+
+type UpsertCalendarTokenParams struct {
+	UserID string
+	Token  string
+}
+
+// Kullanıcının feed token'ını oluşturur ya da yenisiyle değiştirir.
+func (q *Queries) UpsertCalendarToken(ctx context.Context, arg UpsertCalendarTokenParams) (CalendarFeedToken, error) {
+	row := q.db.QueryRowContext(ctx, upsertCalendarToken, arg.UserID, arg.Token)
+	var i CalendarFeedToken
+	err := row.Scan(&i.UserID, &i.Token, &i.CreatedAt)
+	return i, err
+}
@@ -0,0 +1,96 @@
+// internal/db/postgresql/spatial.go for trip-plan-service
+//
+// Bu dosya sqlc ile üretilmez. PostGIS fonksiyonları sqlc'nin şema
+// kataloğunda olmadığı ve eklenti her ortamda kurulu olmadığı için bu
+// sorgular elle yazılmıştır; HasPostGIS true dönmeden çağrılmamalıdır.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const hasPostGIS = `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')`
+
+// HasPostGIS veritabanında postgis eklentisinin kurulu olup olmadığını döner.
+func (q *Queries) HasPostGIS(ctx context.Context) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasPostGIS)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+// Nokta ifadesi locations_geography_idx index'iyle birebir aynı olmalıdır,
+// yoksa planlayıcı index'i kullanmaz.
+const listLocationsNearGeography = `
+WITH center AS (
+    SELECT ST_SetSRID(ST_MakePoint($2::float8, $1::float8), 4326)::geography AS g
+)
+SELECT l.id, l.name, l.site_url, l.latitude, l.longitude, l.created_at,
+       ST_Distance(ST_SetSRID(ST_MakePoint(l.longitude::float8, l.latitude::float8), 4326)::geography, center.g) / 1000 AS distance_km
+FROM locations l, center
+WHERE l.latitude IS NOT NULL AND l.longitude IS NOT NULL
+  AND ST_DWithin(ST_SetSRID(ST_MakePoint(l.longitude::float8, l.latitude::float8), 4326)::geography, center.g, $3::float8 * 1000)
+  AND l.id <> $4
+ORDER BY distance_km, l.id
+LIMIT $5
+`
+
+type ListLocationsNearGeographyParams struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	ExcludeID int32
+	Limit     int32
+}
+
+type ListLocationsNearGeographyRow struct {
+	ID         int32
+	Name       string
+	SiteUrl    sql.NullString
+	Latitude   sql.NullString
+	Longitude  sql.NullString
+	CreatedAt  sql.NullTime
+	DistanceKm float64
+}
+
+// ListLocationsNearGeography noktaya RadiusKm içinde kalan lokasyonları
+// küre üzerindeki gerçek mesafeye göre sıralı döner. Sonuçlar herkese açık
+// uçta listelendiği için adres kolonu seçilmez.
+func (q *Queries) ListLocationsNearGeography(ctx context.Context, arg ListLocationsNearGeographyParams) ([]ListLocationsNearGeographyRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLocationsNearGeography,
+		arg.Latitude,
+		arg.Longitude,
+		arg.RadiusKm,
+		arg.ExcludeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLocationsNearGeographyRow
+	for rows.Next() {
+		var i ListLocationsNearGeographyRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.SiteUrl,
+			&i.Latitude,
+			&i.Longitude,
+			&i.CreatedAt,
+			&i.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
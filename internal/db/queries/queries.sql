@@ -2,23 +2,40 @@
 
 -- name: CreateTrip :one
 -- DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
-INSERT INTO trips (user_id, name, description, start_date, end_date, start_position, end_position)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+-- GÜNCELLENDİ: Başlangıç/bitiş koordinatları eklendi ($7 -> $11).
+INSERT INTO trips (user_id, name, description, start_date, end_date, start_position, end_position,
+                   start_latitude, start_longitude, end_latitude, end_longitude)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at;
 
 -- name: GetTripByID :one
 -- DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
-SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at,
+       start_latitude, start_longitude, end_latitude, end_longitude
 FROM trips
 WHERE id = $1;
 
 -- name: ListTripsByUserID :many
 -- DÜZELTİLDİ: "finish_position" -> "end_position" olarak değiştirildi.
-SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at,
+       start_latitude, start_longitude, end_latitude, end_longitude
 FROM trips
 WHERE user_id = $1
 ORDER BY created_at DESC;
 
+-- name: ListTripsByUserIDAndCountries :many
+-- Verilen ülkelerden en az birinde lokasyonu olan trip'leri döner.
+SELECT t.id, t.user_id, t.name, t.description, t.start_date, t.end_date, t.start_position, t.end_position, t.created_at, t.updated_at,
+       t.start_latitude, t.start_longitude, t.end_latitude, t.end_longitude
+FROM trips t
+WHERE t.user_id = sqlc.arg(user_id)
+  AND EXISTS (
+    SELECT 1 FROM trip_locations tl
+    JOIN locations l ON l.id = tl.location_id
+    WHERE tl.trip_id = t.id AND l.country_code = ANY(sqlc.arg(country_codes)::text[])
+  )
+ORDER BY t.created_at DESC;
+
 -- name: DeleteTrip :exec
 DELETE FROM trips
 WHERE id = $1;
@@ -28,32 +45,59 @@ WHERE id = $1;
 
 -- name: CreateLocation :one
 -- GÜNCELLENDİ: latitude ve longitude eklendi. Parametre sayıları arttı ($4 -> $6).
-INSERT INTO locations (name, address, site_url, notes, latitude, longitude)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, name, address, site_url, notes, latitude, longitude, created_at;
+-- GÜNCELLENDİ: Ülke/bölge etiketleri eklendi ($6 -> $8).
+-- GÜNCELLENDİ: Notlar trip_locations'a taşındı ($8 -> $7).
+INSERT INTO locations (name, address, site_url, latitude, longitude, country_code, admin_region, geocoded_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, LOCALTIMESTAMP)
+RETURNING id, name, address, site_url, latitude, longitude, created_at, country_code, admin_region;
 
 -- name: GetLocationByID :one
 -- GÜNCELLENDİ: "*" yerine tüm kolonlar açıkça yazılarak yeni kolonlar eklendi.
-SELECT id, name, address, site_url, notes, latitude, longitude, created_at
+SELECT id, name, address, site_url, latitude, longitude, created_at
 FROM locations
 WHERE id = $1;
 
 -- name: ListLocations :many
--- GÜNCELLENDİ: "*" yerine tüm kolonlar açıkça yazılarak yeni kolonlar eklendi.
-SELECT id, name, address, site_url, notes, latitude, longitude, created_at
+-- GÜNCELLENDİ: Katalog araması için isim filtresi, sayfalama ve kullanım sayısı eklendi.
+-- Katalog herkese açıktır; kullanıcıların girdiği adresler listelenmez.
+SELECT l.id, l.name, l.site_url, l.latitude, l.longitude, l.created_at, l.in_catalogue, l.country_code, l.admin_region,
+       (SELECT COUNT(*) FROM trip_locations tl WHERE tl.location_id = l.id) AS trip_count
+FROM locations l
+WHERE sqlc.arg(query)::text = '' OR l.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY l.name, l.id
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountLocations :one
+SELECT COUNT(*)
+FROM locations l
+WHERE sqlc.arg(query)::text = '' OR l.name ILIKE '%' || sqlc.arg(query)::text || '%';
+
+-- name: ListLocationsInBox :many
+-- (latitude, longitude) index'ini kullanır; kesin mesafe kontrolü uygulamada yapılır.
+SELECT id, name, address, site_url, latitude, longitude, created_at
 FROM locations
+WHERE latitude BETWEEN sqlc.arg(min_latitude)::numeric AND sqlc.arg(max_latitude)::numeric
+  AND longitude BETWEEN sqlc.arg(min_longitude)::numeric AND sqlc.arg(max_longitude)::numeric
 ORDER BY id;
 
+-- name: LockLocationByID :one
+-- Yeniden kullanılacak lokasyonu transaction sonuna kadar sahipsiz lokasyon
+-- temizliğine karşı kilitler; satır bu arada silindiyse sonuç dönmez.
+SELECT id FROM locations
+WHERE id = $1
+FOR KEY SHARE;
+
 
 -- trip_locations.sql (İlişkisel Sorgular)
 
 -- name: AddLocationToTrip :exec
-INSERT INTO trip_locations (trip_id, location_id, position)
-VALUES ($1, $2, $3);
+INSERT INTO trip_locations (trip_id, location_id, position, day, pinned, waypoint, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
 
 -- name: GetTripLocations :many
 -- GÜNCELLENDİ: "l.*" yerine tüm location kolonları açıkça yazılarak yeni kolonlar eklendi.
-SELECT l.id, l.name, l.address, l.site_url, l.notes, l.latitude, l.longitude, l.created_at, tl.position
+-- Notlar trip'e özeldir ve bağlantı satırından okunur.
+SELECT l.id, l.name, l.address, l.site_url, tl.notes, l.latitude, l.longitude, l.created_at, l.country_code, l.admin_region, tl.position, tl.day, tl.pinned, tl.waypoint
 FROM locations l
 JOIN trip_locations tl ON l.id = tl.location_id
 WHERE tl.trip_id = $1
@@ -61,4 +105,224 @@ ORDER BY tl.position;
 
 -- name: RemoveLocationFromTrip :exec
 DELETE FROM trip_locations
-WHERE trip_id = $1 AND location_id = $2;
\ No newline at end of file
+WHERE trip_id = $1 AND location_id = $2;
+
+-- name: RemoveAllLocationsFromTrip :execrows
+-- Trip'in tüm lokasyon bağlantılarını siler; lokasyonların kendisi kalır.
+DELETE FROM trip_locations
+WHERE trip_id = $1;
+
+-- name: UpdateTripLocationPosition :execrows
+UPDATE trip_locations
+SET position = $3
+WHERE trip_id = $1 AND location_id = $2;
+
+-- name: UpdateTripLocationSchedule :execrows
+UPDATE trip_locations
+SET position = $3, day = $4
+WHERE trip_id = $1 AND location_id = $2;
+
+-- name: SetTripLocationPin :execrows
+-- Lokasyonu bir güne sabitler ya da sabitlemeyi kaldırır (day NULL ise gün değişmez).
+UPDATE trip_locations
+SET day = COALESCE(sqlc.narg(day), day), pinned = sqlc.arg(pinned)
+WHERE trip_id = sqlc.arg(trip_id) AND location_id = sqlc.arg(location_id);
+
+
+-- trip_waypoints.sql (Ara noktalar)
+
+-- name: AddTripWaypoint :exec
+INSERT INTO trip_waypoints (trip_id, position, name, latitude, longitude)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetTripWaypoints :many
+SELECT position, name, latitude, longitude
+FROM trip_waypoints
+WHERE trip_id = $1
+ORDER BY position;
+
+
+-- location_merge.sql (Admin: kopya lokasyonları birleştirme)
+
+-- name: DeleteDuplicateTripLocations :execrows
+-- Taşıma sırasında birincil anahtar çakışmasın diye, aynı trip'te kanonik
+-- lokasyonla ya da daha küçük ID'li başka bir kopyayla birlikte duran kopya
+-- bağlantılarını siler.
+DELETE FROM trip_locations tl
+WHERE tl.location_id = ANY(sqlc.arg(duplicate_ids)::int[])
+  AND EXISTS (
+    SELECT 1 FROM trip_locations o
+    WHERE o.trip_id = tl.trip_id
+      AND (o.location_id = sqlc.arg(canonical_id)
+        OR (o.location_id = ANY(sqlc.arg(duplicate_ids)::int[]) AND o.location_id < tl.location_id))
+  );
+
+-- name: RepointTripLocations :execrows
+UPDATE trip_locations
+SET location_id = sqlc.arg(canonical_id)
+WHERE location_id = ANY(sqlc.arg(duplicate_ids)::int[]);
+
+-- name: CarryOverLocationInCatalogue :exec
+-- Silinecek kopyalardan biri katalogdaysa kanonik lokasyonu da kataloğa alır.
+UPDATE locations
+SET in_catalogue = true
+WHERE id = sqlc.arg(canonical_id)
+  AND EXISTS (
+    SELECT 1 FROM locations d
+    WHERE d.id = ANY(sqlc.arg(duplicate_ids)::int[])
+      AND d.in_catalogue
+  );
+
+-- name: DeleteLocationsByID :execrows
+DELETE FROM locations
+WHERE id = ANY(sqlc.arg(location_ids)::int[]);
+
+
+-- location_gc.sql (Sahipsiz lokasyon temizliği)
+
+-- name: GetOrphanedLocationStats :one
+-- Hiçbir trip'e bağlı olmayan lokasyonları silinebilir, bekleme süresinde ve
+-- katalogda olarak sayar.
+SELECT
+    COUNT(*) FILTER (WHERE NOT l.in_catalogue
+        AND (l.created_at IS NULL OR l.created_at < LOCALTIMESTAMP - make_interval(secs => sqlc.arg(grace_seconds)::float8))) AS eligible,
+    COUNT(*) FILTER (WHERE NOT l.in_catalogue
+        AND l.created_at >= LOCALTIMESTAMP - make_interval(secs => sqlc.arg(grace_seconds)::float8)) AS in_grace_period,
+    COUNT(*) FILTER (WHERE l.in_catalogue) AS in_catalogue
+FROM locations l
+WHERE NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id);
+
+-- name: DeleteOrphanedLocationsBatch :execrows
+-- Başka bir transaction'ın kilitlediği (ör. o an trip'e bağlanan) satırlar atlanır.
+DELETE FROM locations
+WHERE id IN (
+    SELECT l.id FROM locations l
+    WHERE NOT l.in_catalogue
+      AND (l.created_at IS NULL OR l.created_at < LOCALTIMESTAMP - make_interval(secs => sqlc.arg(grace_seconds)::float8))
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    ORDER BY l.id
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+);
+
+-- name: SetLocationInCatalogue :execrows
+UPDATE locations
+SET in_catalogue = $2
+WHERE id = $1;
+
+
+-- spatial.sql (Alan araması)
+
+-- name: ListUserTripsInBox :many
+-- Kullanıcının en az bir lokasyonu kutunun içinde kalan trip'lerini döner.
+-- min_longitude > max_longitude ise kutu 180. meridyeni geçer.
+SELECT t.id, t.user_id, t.name, t.description, t.start_date, t.end_date, t.start_position, t.end_position, t.created_at, t.updated_at,
+       COUNT(*) AS matching_locations
+FROM trips t
+JOIN trip_locations tl ON tl.trip_id = t.id
+JOIN locations l ON l.id = tl.location_id
+WHERE t.user_id = sqlc.arg(user_id)
+  AND l.latitude BETWEEN sqlc.arg(min_latitude)::numeric AND sqlc.arg(max_latitude)::numeric
+  AND (
+    (sqlc.arg(min_longitude)::numeric <= sqlc.arg(max_longitude)::numeric
+        AND l.longitude BETWEEN sqlc.arg(min_longitude)::numeric AND sqlc.arg(max_longitude)::numeric)
+    OR (sqlc.arg(min_longitude)::numeric > sqlc.arg(max_longitude)::numeric
+        AND (l.longitude >= sqlc.arg(min_longitude)::numeric OR l.longitude <= sqlc.arg(max_longitude)::numeric))
+  )
+GROUP BY t.id
+ORDER BY t.start_date DESC, t.id;
+
+
+-- location_region.sql (Ülke/bölge etiketleme)
+
+-- name: ListLocationsToGeocode :many
+-- Koordinatı olan lokasyonları ID sırasıyla sayfalar; all_rows false ise
+-- yalnızca henüz bir ülkeye atanmamış olanlar döner. Veri setinin dışında
+-- kalan noktalar böylece veri seti genişledikçe yeniden denenir.
+SELECT id, latitude, longitude
+FROM locations
+WHERE id > sqlc.arg(after_id)
+  AND latitude IS NOT NULL AND longitude IS NOT NULL
+  AND (sqlc.arg(all_rows)::bool OR country_code IS NULL)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: SetLocationRegion :execrows
+UPDATE locations
+SET country_code = sqlc.narg(country_code), admin_region = sqlc.narg(admin_region), geocoded_at = LOCALTIMESTAMP
+WHERE id = sqlc.arg(id);
+
+
+-- calendar.sql
+
+-- name: ListUpcomingTripsByUserID :many
+SELECT id, user_id, name, description, start_date, end_date, start_position, end_position, created_at, updated_at
+FROM trips
+WHERE user_id = $1 AND end_date >= CURRENT_DATE
+ORDER BY start_date;
+
+-- name: GetCalendarTokenByUserID :one
+SELECT user_id, token, created_at
+FROM calendar_feed_tokens
+WHERE user_id = $1;
+
+-- name: GetCalendarTokenByToken :one
+SELECT user_id, token, created_at
+FROM calendar_feed_tokens
+WHERE token = $1;
+
+-- name: UpsertCalendarToken :one
+-- Kullanıcının feed token'ını oluşturur ya da yenisiyle değiştirir.
+INSERT INTO calendar_feed_tokens (user_id, token)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP
+RETURNING user_id, token, created_at;
+
+
+-- account.sql (GDPR export/silme)
+
+-- name: ListLocationIDsByUserID :many
+SELECT DISTINCT tl.location_id
+FROM trip_locations tl
+JOIN trips t ON t.id = tl.trip_id
+WHERE t.user_id = $1
+ORDER BY tl.location_id;
+
+-- name: CountTripsByUserID :one
+SELECT COUNT(*) FROM trips
+WHERE user_id = $1;
+
+-- name: DeleteTripLocationsByUserID :execrows
+DELETE FROM trip_locations
+WHERE trip_id IN (SELECT id FROM trips WHERE user_id = $1);
+
+-- name: DeleteTripsByUserID :execrows
+DELETE FROM trips
+WHERE user_id = $1;
+
+-- name: DeleteOrphanedLocations :execrows
+-- Verilen lokasyonlardan artık hiçbir trip'e bağlı olmayanları siler.
+-- Katalogdaki lokasyonlar ve başka bir transaction'ın kilitlediği (ör. o an
+-- başka bir kullanıcının trip'ine bağlanan) satırlar atlanır.
+DELETE FROM locations
+WHERE id IN (
+    SELECT l.id FROM locations l
+    WHERE l.id = ANY(sqlc.arg(location_ids)::int[])
+      AND NOT l.in_catalogue
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    FOR UPDATE SKIP LOCKED
+);
+
+-- name: CountOrphanedLocations :one
+-- DeleteOrphanedLocations'ın sileceği satırları sayar.
+SELECT COUNT(*) FROM (
+    SELECT l.id FROM locations l
+    WHERE l.id = ANY(sqlc.arg(location_ids)::int[])
+      AND NOT l.in_catalogue
+      AND NOT EXISTS (SELECT 1 FROM trip_locations tl WHERE tl.location_id = l.id)
+    FOR UPDATE SKIP LOCKED
+) orphans;
+
+-- name: DeleteCalendarTokenByUserID :execrows
+DELETE FROM calendar_feed_tokens
+WHERE user_id = $1;
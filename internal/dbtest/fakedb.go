@@ -0,0 +1,183 @@
+// internal/dbtest/fakedb.go for trip-plan-service
+
+// Package dbtest sqlc sorgularını "-- name:" yorumlarına göre tanıyan ve
+// testin verdiği satırları dönen bir database/sql sürücüsüdür. Amaç servis
+// ve handler'ları veritabanı olmadan denemektir; SQL'in kendisi doğrulanmaz.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RowsFunc sorgu argümanlarından dönülecek satırları üretir; nil dönerse
+// sorgu satır döndürmez (sql.ErrNoRows).
+type RowsFunc func(args []driver.Value) [][]driver.Value
+
+// DB sahte veritabanıdır. Sıfır değeri kullanılabilir; Rows'ta olmayan
+// sorgular hata döner, Affected'da olmayan komutlar bir satır etkiler.
+type DB struct {
+	// Rows :one ve :many sorgularının satırlarıdır.
+	Rows map[string]RowsFunc
+	// Affected :exec ve :execrows komutlarının etkilediği satır sayısıdır.
+	Affected map[string]func(args []driver.Value) int64
+
+	mu        sync.Mutex
+	calls     []Call
+	commits   int
+	rollbacks int
+}
+
+// Call çalıştırılan bir sorgunun adı ve argümanlarıdır.
+type Call struct {
+	Name string
+	Args []driver.Value
+}
+
+// Open DB'yi kullanan bir *sql.DB döner.
+func (d *DB) Open() *sql.DB {
+	return sql.OpenDB(connector{d})
+}
+
+// Calls çalıştırılan sorguları sırasıyla döner.
+func (d *DB) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call(nil), d.calls...)
+}
+
+// Called adı verilen sorgunun kaç kez çalıştırıldığını döner.
+func (d *DB) Called(name string) int {
+	n := 0
+	for _, call := range d.Calls() {
+		if call.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+// Commits ve Rollbacks sonlanan transaction sayılarıdır.
+func (d *DB) Commits() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.commits
+}
+
+func (d *DB) Rollbacks() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rollbacks
+}
+
+func (d *DB) record(name string, args []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, Call{Name: name, Args: args})
+}
+
+type connector struct{ db *DB }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return conn{c.db}, nil }
+func (c connector) Driver() driver.Driver                        { return c }
+func (c connector) Open(string) (driver.Conn, error)             { return conn{c.db}, nil }
+
+type conn struct{ db *DB }
+
+func (c conn) Prepare(query string) (driver.Stmt, error) { return stmt{db: c.db, query: query}, nil }
+func (c conn) Close() error                              { return nil }
+func (c conn) Begin() (driver.Tx, error)                 { return tx{c.db}, nil }
+
+type tx struct{ db *DB }
+
+func (t tx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.commits++
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.rollbacks++
+	return nil
+}
+
+type stmt struct {
+	db    *DB
+	query string
+}
+
+func (s stmt) Close() error  { return nil }
+func (s stmt) NumInput() int { return -1 }
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	name := QueryName(s.query)
+	s.db.record(name, args)
+	if affected, ok := s.db.Affected[name]; ok {
+		return driver.RowsAffected(affected(args)), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	name := QueryName(s.query)
+	s.db.record(name, args)
+	rows, ok := s.db.Rows[name]
+	if !ok {
+		return nil, errors.New("fake database has no rows for query " + name)
+	}
+	return &result{rows: rows(args)}, nil
+}
+
+var queryNamePattern = regexp.MustCompile(`-- name: (\w+)`)
+
+// QueryName sqlc sorgusunun adını döner. PostGIS kontrolü "HasPostGIS"
+// olarak adlandırılır; tanınmayan sorgular olduğu gibi döner.
+func QueryName(query string) string {
+	if match := queryNamePattern.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	if strings.Contains(query, "pg_extension") {
+		return "HasPostGIS"
+	}
+	return query
+}
+
+type result struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *result) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *result) Close() error { return nil }
+
+func (r *result) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+// Count tek satırlık bir COUNT(*) sonucu döner.
+func Count(n int64) RowsFunc {
+	return func([]driver.Value) [][]driver.Value { return [][]driver.Value{{n}} }
+}
+
+// NoRows hiç satır dönmez.
+func NoRows([]driver.Value) [][]driver.Value { return nil }
@@ -0,0 +1,133 @@
+// internal/distance/distance.go for trip-plan-service
+package distance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// Profiles ulaşım türünden ortalama hıza (km/sa) eşlemedir.
+type Profiles struct {
+	DefaultMode string
+	Speeds      map[string]float64
+}
+
+// DefaultProfiles konfigürasyon verilmediğinde kullanılan hızlardır.
+var DefaultProfiles = Profiles{
+	DefaultMode: "car",
+	Speeds: map[string]float64{
+		"car":     80,
+		"caravan": 65,
+		"bicycle": 15,
+		"walking": 5,
+	},
+}
+
+// UnknownModeError tanımlı olmayan bir ulaşım türü istendiğinde döner.
+type UnknownModeError struct {
+	Mode  string
+	Modes []string
+}
+
+func (e *UnknownModeError) Error() string {
+	return fmt.Sprintf("distance: unknown travel mode %q, expected one of %s", e.Mode, strings.Join(e.Modes, ", "))
+}
+
+// Speed mod için hızı döner; mode boşsa varsayılan mod kullanılır.
+func (p Profiles) Speed(mode string) (string, float64, error) {
+	if mode == "" {
+		mode = p.DefaultMode
+	}
+	mode = strings.ToLower(mode)
+
+	speed, ok := p.Speeds[mode]
+	if !ok {
+		modes := make([]string, 0, len(p.Speeds))
+		for name := range p.Speeds {
+			modes = append(modes, name)
+		}
+		sort.Strings(modes)
+		return "", 0, &UnknownModeError{Mode: mode, Modes: modes}
+	}
+	return mode, speed, nil
+}
+
+// Compute lokasyonlar arasındaki mesafeleri pozisyon sırasıyla hesaplar.
+// Koordinatı olmayan duraklar atlanır ve bir önceki koordinatlı duraktan bir
+// sonrakine tek yol olarak sayılır. Günü olmayan duraklara varan yollar gün
+// toplamlarına girmez ama trip toplamına girer.
+func (p Profiles) Compute(locations []models.Location, mode string) (*models.TripDistances, error) {
+	mode, speed, err := p.Speed(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TripDistances{
+		Mode:     mode,
+		SpeedKmh: speed,
+		Days:     []models.DayDistance{},
+		Legs:     []models.Leg{},
+	}
+
+	totalKm := 0.0
+	dayKm := map[int]float64{}
+	previous := -1
+
+	for i, loc := range locations {
+		if loc.Latitude == 0 && loc.Longitude == 0 {
+			continue
+		}
+		if previous >= 0 {
+			from := locations[previous]
+			km := geo.HaversineKm(
+				geo.Point{Latitude: from.Latitude, Longitude: from.Longitude},
+				geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude},
+			)
+			result.Legs = append(result.Legs, models.Leg{
+				FromPosition:    previous + 1,
+				ToPosition:      i + 1,
+				From:            from.Name,
+				To:              loc.Name,
+				Day:             loc.Day,
+				DistanceKm:      round(km),
+				DurationMinutes: minutes(km, speed),
+			})
+			totalKm += km
+			if loc.Day > 0 {
+				dayKm[loc.Day] += km
+			}
+		}
+		previous = i
+	}
+
+	days := make([]int, 0, len(dayKm))
+	for day := range dayKm {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	for _, day := range days {
+		result.Days = append(result.Days, models.DayDistance{
+			Day:             day,
+			DistanceKm:      round(dayKm[day]),
+			DurationMinutes: minutes(dayKm[day], speed),
+		})
+	}
+
+	result.TotalDistanceKm = round(totalKm)
+	result.TotalDurationMinutes = minutes(totalKm, speed)
+	return result, nil
+}
+
+func minutes(km, speed float64) int {
+	return int(math.Round(km / speed * 60))
+}
+
+// round mesafeyi 100 metre hassasiyetine yuvarlar.
+func round(km float64) float64 {
+	return math.Round(km*10) / 10
+}
@@ -0,0 +1,107 @@
+package distance
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+func stop(name string, day int, lat, lon float64) models.Location {
+	return models.Location{Name: name, Day: day, Latitude: lat, Longitude: lon}
+}
+
+// Aynı boylamda bir derecelik enlem farkı 111.19 km'dir; arabayla 83.4 dakika.
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations []models.Location
+		mode      string
+		want      *models.TripDistances
+	}{
+		{
+			name: "koordinatsız durak atlanır",
+			locations: []models.Location{
+				stop("A", 1, 36, 30),
+				stop("B", 1, 0, 0),
+				stop("C", 1, 37, 30),
+			},
+			want: &models.TripDistances{
+				Mode: "car", SpeedKmh: 80, TotalDistanceKm: 111.2, TotalDurationMinutes: 83,
+				Days: []models.DayDistance{{Day: 1, DistanceKm: 111.2, DurationMinutes: 83}},
+				Legs: []models.Leg{
+					{FromPosition: 1, ToPosition: 3, From: "A", To: "C", Day: 1, DistanceKm: 111.2, DurationMinutes: 83},
+				},
+			},
+		},
+		{
+			name: "gün atanmamış durağa varan yol gün toplamına girmez",
+			locations: []models.Location{
+				stop("A", 1, 36, 30),
+				stop("B", 0, 37, 30),
+				stop("C", 2, 38, 30),
+			},
+			mode: "car",
+			want: &models.TripDistances{
+				Mode: "car", SpeedKmh: 80, TotalDistanceKm: 222.4, TotalDurationMinutes: 167,
+				Days: []models.DayDistance{{Day: 2, DistanceKm: 111.2, DurationMinutes: 83}},
+				Legs: []models.Leg{
+					{FromPosition: 1, ToPosition: 2, From: "A", To: "B", DistanceKm: 111.2, DurationMinutes: 83},
+					{FromPosition: 2, ToPosition: 3, From: "B", To: "C", Day: 2, DistanceKm: 111.2, DurationMinutes: 83},
+				},
+			},
+		},
+		{
+			name: "mesafe 100 metreye, süre dakikaya yuvarlanır",
+			locations: []models.Location{
+				stop("A", 1, 36, 30),
+				stop("B", 1, 36.0005, 30),
+			},
+			mode: "Walking",
+			want: &models.TripDistances{
+				Mode: "walking", SpeedKmh: 5, TotalDistanceKm: 0.1, TotalDurationMinutes: 1,
+				Days: []models.DayDistance{{Day: 1, DistanceKm: 0.1, DurationMinutes: 1}},
+				Legs: []models.Leg{
+					{FromPosition: 1, ToPosition: 2, From: "A", To: "B", Day: 1, DistanceKm: 0.1, DurationMinutes: 1},
+				},
+			},
+		},
+		{
+			name:      "tek durakta yol yoktur",
+			locations: []models.Location{stop("A", 1, 36, 30)},
+			want: &models.TripDistances{
+				Mode: "car", SpeedKmh: 80,
+				Days: []models.DayDistance{},
+				Legs: []models.Leg{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultProfiles.Compute(tt.locations, tt.mode)
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compute =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeUnknownMode(t *testing.T) {
+	_, err := DefaultProfiles.Compute([]models.Location{stop("A", 1, 36, 30)}, "Boat")
+
+	var unknown *UnknownModeError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("err = %v, want *UnknownModeError", err)
+	}
+	if unknown.Mode != "boat" {
+		t.Errorf("Mode = %q, want boat", unknown.Mode)
+	}
+	if want := []string{"bicycle", "car", "caravan", "walking"}; !reflect.DeepEqual(unknown.Modes, want) {
+		t.Errorf("Modes = %v, want %v", unknown.Modes, want)
+	}
+}
@@ -0,0 +1,67 @@
+// internal/export/bundle.go for trip-plan-service
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"trip-plan-service/internal/models"
+)
+
+// Bundle çevrimdışı kullanım için trip'i tek bir ZIP arşivinde toplar:
+// JSON, GPX, ICS, GeoJSON ve yazdırılabilir HTML plan.
+func Bundle(trip *models.TripWithLocations) ([]byte, error) {
+	tripJSON, err := json.MarshalIndent(trip, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to encode trip JSON: %v", err)
+	}
+	gpx, err := GPX(trip)
+	if err != nil {
+		return nil, err
+	}
+	geoJSON, err := GeoJSON(trip)
+	if err != nil {
+		return nil, err
+	}
+	itinerary, err := ItineraryHTML(trip)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		body []byte
+	}{
+		{"itinerary.html", itinerary},
+		{"trip.json", tripJSON},
+		{"trip.gpx", gpx},
+		{"trip.ics", ICS(trip.Trip.Name, *trip)},
+		{"trip.geojson", geoJSON},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	modified := time.Now()
+
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export: failed to add %s to bundle: %v", file.name, err)
+		}
+		if _, err := w.Write(file.body); err != nil {
+			return nil, fmt.Errorf("export: failed to add %s to bundle: %v", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("export: failed to finish bundle: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+// Arşivde her biçim bir kez, sabit adla ve açılabilir halde bulunmalı.
+func TestBundleContents(t *testing.T) {
+	trip := gpxTestTrip(
+		models.Location{ID: 1, Name: "Efes", Day: 1, Latitude: 37.9395, Longitude: 27.3417, Notes: stringPtr("Sabah")},
+		models.Location{ID: 2, Name: "Bodrum Kalesi", Day: 2, Latitude: 37.0317, Longitude: 27.4286},
+	)
+
+	out, err := Bundle(trip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("invalid ZIP: %v", err)
+	}
+
+	files := map[string][]byte{}
+	var names []string
+	for _, file := range archive.File {
+		if file.Method != zip.Deflate {
+			t.Errorf("%s method = %d, want Deflate", file.Name, file.Method)
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", file.Name, err)
+		}
+		names = append(names, file.Name)
+		files[file.Name] = body
+	}
+
+	want := []string{"itinerary.html", "trip.json", "trip.gpx", "trip.ics", "trip.geojson"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+
+	if html := string(files["itinerary.html"]); !strings.Contains(html, "<html") || !strings.Contains(html, "Bodrum Kalesi") {
+		t.Errorf("itinerary.html does not look like the rendered itinerary:\n%s", html)
+	}
+	var decoded models.TripWithLocations
+	if err := json.Unmarshal(files["trip.json"], &decoded); err != nil {
+		t.Errorf("trip.json: %v", err)
+	} else if decoded.Trip.Name != trip.Trip.Name || len(decoded.Locations) != 2 {
+		t.Errorf("trip.json = %+v", decoded)
+	}
+	var gpx struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(files["trip.gpx"], &gpx); err != nil || gpx.XMLName.Local != "gpx" {
+		t.Errorf("trip.gpx root = %q, err = %v", gpx.XMLName.Local, err)
+	}
+	if ics := string(files["trip.ics"]); !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("trip.ics is not a calendar:\n%s", ics)
+	}
+	var geoJSON struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(files["trip.geojson"], &geoJSON); err != nil || geoJSON.Type != "FeatureCollection" || len(geoJSON.Features) == 0 {
+		t.Errorf("trip.geojson = %+v, err = %v", geoJSON, err)
+	}
+}
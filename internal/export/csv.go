@@ -0,0 +1,70 @@
+// internal/export/csv.go for trip-plan-service
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/importer"
+	"trip-plan-service/internal/models"
+)
+
+// CSVHeader CSV export'unun sütunlarıdır; aynı başlıklar CSV import'ta da
+// tanındığı için dosya düzenlenip geri yüklenebilir.
+var CSVHeader = []string{"position", "day", "name", "address", "latitude", "longitude", "site_url", "notes"}
+
+// CSV trip'in lokasyonlarını pozisyon sırasıyla CSV olarak yazar. Koordinatı
+// ya da günü olmayan lokasyonlarda ilgili hücreler boş bırakılır. Metin
+// hücreleri csvText ile formül olarak çalıştırılmaya karşı korunur.
+func CSV(trip *models.TripWithLocations) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(CSVHeader); err != nil {
+		return nil, fmt.Errorf("export: failed to encode CSV: %v", err)
+	}
+
+	for i, loc := range trip.Locations {
+		day, latitude, longitude := "", "", ""
+		if loc.Day > 0 {
+			day = strconv.Itoa(loc.Day)
+		}
+		if hasCoordinates(loc) {
+			latitude = strconv.FormatFloat(loc.Latitude, 'f', -1, 64)
+			longitude = strconv.FormatFloat(loc.Longitude, 'f', -1, 64)
+		}
+
+		record := []string{
+			strconv.Itoa(i + 1),
+			day,
+			csvText(loc.Name),
+			csvText(deref(loc.Address)),
+			latitude,
+			longitude,
+			csvText(deref(loc.SiteURL)),
+			csvText(deref(loc.Notes)),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("export: failed to encode CSV: %v", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("export: failed to encode CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvText formül gibi başlayan hücrelerin başına ' ekler (CSV injection).
+// Tablo programları bu öneki göstermez; CSV import da geri alır. Zaten ' ile
+// başlayan metinler de öneklenir, yoksa import kendi önekini kaldırır.
+func csvText(value string) string {
+	if value != "" && (value[0] == '\'' || strings.ContainsRune(importer.CSVFormulaPrefixes, rune(value[0]))) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/importer"
+	"trip-plan-service/internal/models"
+)
+
+var formulaLocations = []models.Location{
+	{Name: "=HYPERLINK(\"http://evil.example\",\"Efes\")", Latitude: 37.9395, Longitude: 27.3417, Day: 1,
+		Address: stringPtr("+90 232 892 60 10"), Notes: stringPtr("-2+3")},
+	{Name: "Bodrum Kalesi", Latitude: 37.0317, Longitude: 27.4286, Day: 2,
+		Notes: stringPtr("Giriş ücreti: =50 TL değil")},
+	{Name: "'Ksamil' plajı", Latitude: 39.7706, Longitude: 20.0003, Day: 3,
+		Notes: stringPtr("'-5 °C at night")},
+}
+
+// site_url import'ta URL olarak doğrulandığı için yalnızca export'ta denenir
+var formulaSiteURL = models.Location{Name: "Kuşadası", SiteURL: stringPtr("@SUM(1+1)")}
+
+// Formül gibi başlayan metin hücreleri ' ile başlamalı; diğerleri olduğu
+// gibi kalmalı.
+func TestCSVEscapesFormulaCells(t *testing.T) {
+	out, err := CSV(gpxTestTrip(append(formulaLocations, formulaSiteURL)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"'=HYPERLINK(\"http://evil.example\",\"Efes\")", "'+90 232 892 60 10", "", "'-2+3"},
+		{"Bodrum Kalesi", "", "", "Giriş ücreti: =50 TL değil"},
+		{"''Ksamil' plajı", "", "", "''-5 °C at night"},
+		{"Kuşadası", "", "'@SUM(1+1)", ""},
+	}
+	for i, row := range records[1:] {
+		got := []string{row[2], row[3], row[6], row[7]}
+		if strings.Join(got, "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d text cells = %q, want %q", i+1, got, want[i])
+		}
+	}
+}
+
+// Export edilen dosya geri yüklendiğinde metinler ' öneki olmadan dönmeli.
+func TestCSVRoundTripsFormulaCells(t *testing.T) {
+	out, err := CSV(gpxTestTrip(formulaLocations...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations, rowErrors, err := importer.ParseCSV(out, importer.CSVOptions{})
+	if err != nil || len(rowErrors) > 0 {
+		t.Fatalf("ParseCSV: %v %v", err, rowErrors)
+	}
+
+	for i, loc := range locations {
+		want := formulaLocations[i]
+		if loc.Name != want.Name || deref(loc.Address) != deref(want.Address) ||
+			deref(loc.SiteURL) != deref(want.SiteURL) || deref(loc.Notes) != deref(want.Notes) {
+			t.Errorf("location %d = %q %q %q %q, want %q %q %q %q", i+1,
+				loc.Name, deref(loc.Address), deref(loc.SiteURL), deref(loc.Notes),
+				want.Name, deref(want.Address), deref(want.SiteURL), deref(want.Notes))
+		}
+	}
+}
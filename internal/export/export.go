@@ -0,0 +1,27 @@
+// internal/export/export.go for trip-plan-service
+package export
+
+import (
+	"fmt"
+
+	"trip-plan-service/internal/models"
+)
+
+func hasCoordinates(loc models.Location) bool {
+	return loc.Latitude != 0 || loc.Longitude != 0
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func tripSummary(trip models.Trip) string {
+	summary := fmt.Sprintf("%s - %s", trip.StartDate, trip.EndDate)
+	if trip.StartPosition != "" || trip.EndPosition != "" {
+		summary += fmt.Sprintf(" | %s → %s", trip.StartPosition, trip.EndPosition)
+	}
+	return summary
+}
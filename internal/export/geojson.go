@@ -0,0 +1,146 @@
+// internal/export/geojson.go for trip-plan-service
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// GeoJSON koordinatları RFC 7946'ya göre [boylam, enlem] sırasındadır.
+type geoJSONFeatureCollection struct {
+	Type     string                `json:"type"`
+	BBox     []float64             `json:"bbox,omitempty"`
+	Centroid []float64             `json:"centroid,omitempty"`
+	Trip     geoJSONTripProperties `json:"properties"`
+	Features []geoJSONFeature      `json:"features"`
+}
+
+type geoJSONTripProperties struct {
+	ID        int    `json:"trip_id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoJSON kayıtlı bir trip'i harita kütüphanelerinin doğrudan çizebileceği
+// bir FeatureCollection'a çevirir: her lokasyon için bir Point ve pozisyon
+// sırasıyla rotayı çizen bir LineString. Koleksiyon trip'in sınır kutusunu
+// (bbox) ve ağırlık merkezini (centroid) de taşır. Koordinatı olmayan
+// lokasyonlar atlanır. Başlangıç/bitiş noktalarının koordinatı biliniyorsa
+// "start"/"end" türünde ayrı Point'ler eklenir ve sınır kutusuna dahil
+// edilir; rota ve ağırlık merkezi yalnızca lokasyonlardan hesaplanır.
+func GeoJSON(trip *models.TripWithLocations) ([]byte, error) {
+	collection := geoJSONFeatureCollection{
+		Type: "FeatureCollection",
+		Trip: geoJSONTripProperties{
+			ID:        trip.Trip.ID,
+			Name:      trip.Trip.Name,
+			StartDate: trip.Trip.StartDate,
+			EndDate:   trip.Trip.EndDate,
+		},
+		Features: []geoJSONFeature{},
+	}
+
+	var (
+		points []geo.Point
+		line   [][]float64
+	)
+
+	for i, loc := range trip.Locations {
+		if !hasCoordinates(loc) {
+			continue
+		}
+
+		coordinates := []float64{loc.Longitude, loc.Latitude}
+		properties := map[string]interface{}{
+			"kind":     "location",
+			"id":       loc.ID,
+			"name":     loc.Name,
+			"position": i + 1,
+		}
+		if loc.Day > 0 {
+			properties["day"] = loc.Day
+			properties["date"] = loc.Date
+		}
+		if notes := deref(loc.Notes); notes != "" {
+			properties["notes"] = notes
+		}
+		if address := deref(loc.Address); address != "" {
+			properties["address"] = address
+		}
+		if url := deref(loc.SiteURL); url != "" {
+			properties["site_url"] = url
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: coordinates},
+			Properties: properties,
+		})
+
+		points = append(points, geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude})
+		line = append(line, coordinates)
+	}
+
+	// LineString en az iki nokta ister
+	if len(line) >= 2 {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "LineString", Coordinates: line},
+			Properties: map[string]interface{}{
+				"kind": "route",
+				"name": trip.Trip.Name,
+			},
+		})
+	}
+
+	bounds := append([]geo.Point(nil), points...)
+	for _, endpoint := range []struct {
+		kind  string
+		name  string
+		point *geo.Point
+	}{
+		{"start", trip.Trip.StartPosition, trip.Trip.StartPoint},
+		{"end", trip.Trip.EndPosition, trip.Trip.EndPoint},
+	} {
+		if endpoint.point == nil {
+			continue
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "Point", Coordinates: []float64{endpoint.point.Longitude, endpoint.point.Latitude}},
+			Properties: map[string]interface{}{
+				"kind": endpoint.kind,
+				"name": endpoint.name,
+			},
+		})
+		bounds = append(bounds, *endpoint.point)
+	}
+
+	if box, ok := geo.Bounds(bounds); ok {
+		collection.BBox = []float64{box.MinLongitude, box.MinLatitude, box.MaxLongitude, box.MaxLatitude}
+	}
+	if center, ok := geo.Centroid(points); ok {
+		collection.Centroid = []float64{center.Longitude, center.Latitude}
+	}
+
+	out, err := json.Marshal(collection)
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to encode GeoJSON: %v", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+type decodedGeoJSON struct {
+	Type     string    `json:"type"`
+	BBox     []float64 `json:"bbox"`
+	Centroid []float64 `json:"centroid"`
+	Features []struct {
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	} `json:"features"`
+}
+
+func decodeGeoJSON(t *testing.T, trip *models.TripWithLocations) decodedGeoJSON {
+	t.Helper()
+	out, err := GeoJSON(trip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc decodedGeoJSON
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	return doc
+}
+
+// kinds feature'ları "tür:ad" olarak sırasıyla döner.
+func (d decodedGeoJSON) kinds() []string {
+	var kinds []string
+	for _, feature := range d.Features {
+		kinds = append(kinds, feature.Properties["kind"].(string)+":"+feature.Geometry.Type)
+	}
+	return kinds
+}
+
+// Koordinatsız duraklar atlanır ama pozisyon numarası sayılır; başlangıç
+// ve bitiş sınır kutusuna girer, ağırlık merkezine girmez.
+func TestGeoJSONBoundsAndCentroid(t *testing.T) {
+	trip := gpxTestTrip(
+		models.Location{ID: 1, Name: "Batı", Day: 1, Latitude: 38, Longitude: 27},
+		models.Location{ID: 2, Name: "Koordinatsız"},
+		models.Location{ID: 3, Name: "Doğu", Day: 2, Latitude: 38, Longitude: 29},
+	)
+	trip.Trip.StartPoint = &geo.Point{Latitude: 41, Longitude: 28.5}
+	trip.Trip.EndPoint = &geo.Point{Latitude: 36.5, Longitude: 30}
+
+	doc := decodeGeoJSON(t, trip)
+
+	want := []string{"location:Point", "location:Point", "route:LineString", "start:Point", "end:Point"}
+	if got := doc.kinds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("features = %v, want %v", got, want)
+	}
+	if position := doc.Features[1].Properties["position"]; position != float64(3) {
+		t.Errorf("second point position = %v, want 3", position)
+	}
+	var line [][]float64
+	if err := json.Unmarshal(doc.Features[2].Geometry.Coordinates, &line); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(line, [][]float64{{27, 38}, {29, 38}}) {
+		t.Errorf("route = %v, want [lon, lat] pairs of the located stops", line)
+	}
+
+	if want := []float64{27, 36.5, 30, 41}; !reflect.DeepEqual(doc.BBox, want) {
+		t.Errorf("bbox = %v, want %v", doc.BBox, want)
+	}
+	// Büyük daire üzerinde orta nokta aynı enlemde biraz kuzeye kayar
+	if len(doc.Centroid) != 2 || math.Abs(doc.Centroid[0]-28) > 1e-9 || doc.Centroid[1] <= 38 || doc.Centroid[1] > 38.2 {
+		t.Errorf("centroid = %v, want about [28, 38.1]", doc.Centroid)
+	}
+}
+
+func TestGeoJSONWithoutCoordinates(t *testing.T) {
+	doc := decodeGeoJSON(t, gpxTestTrip(models.Location{Name: "Koordinatsız", Day: 1}))
+
+	if doc.Type != "FeatureCollection" || doc.Features == nil || len(doc.Features) != 0 {
+		t.Errorf("features = %v, want an empty FeatureCollection", doc.Features)
+	}
+	if doc.BBox != nil || doc.Centroid != nil {
+		t.Errorf("bbox = %v, centroid = %v; want both omitted", doc.BBox, doc.Centroid)
+	}
+}
+
+// Tek durakta rota çizilmez; sınır kutusu ve merkez durağın kendisidir.
+func TestGeoJSONSingleStop(t *testing.T) {
+	doc := decodeGeoJSON(t, gpxTestTrip(models.Location{Name: "Efes", Day: 1, Latitude: 37.9395, Longitude: 27.3417,
+		Notes: stringPtr("Sabah"), SiteURL: stringPtr("https://muze.gov.tr/efes")}))
+
+	if got := doc.kinds(); !reflect.DeepEqual(got, []string{"location:Point"}) {
+		t.Errorf("features = %v, want a single point", got)
+	}
+	if props := doc.Features[0].Properties; props["notes"] != "Sabah" || props["site_url"] != "https://muze.gov.tr/efes" || props["day"] != float64(1) {
+		t.Errorf("properties = %v", props)
+	}
+	if want := []float64{27.3417, 37.9395, 27.3417, 37.9395}; !reflect.DeepEqual(doc.BBox, want) {
+		t.Errorf("bbox = %v, want %v", doc.BBox, want)
+	}
+	if len(doc.Centroid) != 2 || math.Abs(doc.Centroid[0]-27.3417) > 1e-9 || math.Abs(doc.Centroid[1]-37.9395) > 1e-9 {
+		t.Errorf("centroid = %v, want the stop itself", doc.Centroid)
+	}
+}
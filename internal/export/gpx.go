@@ -0,0 +1,148 @@
+// internal/export/gpx.go for trip-plan-service
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"trip-plan-service/internal/models"
+)
+
+const (
+	gpxNamespace      = "http://www.topografix.com/GPX/1/1"
+	gpxSchemaLocation = "http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd"
+	gpxCreator        = "trip-plan-service"
+)
+
+// Alan sırası GPX 1.1 şemasındaki xsd:sequence sırasına uymalıdır.
+type gpxDocument struct {
+	XMLName        xml.Name    `xml:"gpx"`
+	Version        string      `xml:"version,attr"`
+	Creator        string      `xml:"creator,attr"`
+	Xmlns          string      `xml:"xmlns,attr"`
+	XmlnsXsi       string      `xml:"xmlns:xsi,attr"`
+	SchemaLocation string      `xml:"xsi:schemaLocation,attr"`
+	Metadata       gpxMetadata `xml:"metadata"`
+	Waypoints      []gpxPoint  `xml:"wpt"`
+	Routes         []gpxRoute  `xml:"rte"`
+	Tracks         []gpxTrack  `xml:"trk"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+	Time string `xml:"time,omitempty"`
+}
+
+// Koordinatlar xsd:decimal olmalıdır; float64 alanlar küçük değerlerde
+// üstel gösterimle (1e-07) yazıldığı için metin olarak tutulur.
+type gpxPoint struct {
+	Lat  string   `xml:"lat,attr"`
+	Lon  string   `xml:"lon,attr"`
+	Name string   `xml:"name,omitempty"`
+	Desc string   `xml:"desc,omitempty"`
+	Link *gpxLink `xml:"link,omitempty"`
+}
+
+type gpxLink struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:"text,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Desc   string     `xml:"desc,omitempty"`
+	Number int        `xml:"number,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// GPX kayıtlı bir trip'i GPX 1.1 dokümanına çevirir: her lokasyon için bir
+// waypoint, pozisyon sırasıyla bir rota ve gün bilgisi varsa her gün için
+// bir segment içeren bir track üretir. Koordinatı olmayan lokasyonlar atlanır.
+func GPX(trip *models.TripWithLocations) ([]byte, error) {
+	doc := gpxDocument{
+		Version:        "1.1",
+		Creator:        gpxCreator,
+		Xmlns:          gpxNamespace,
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: gpxSchemaLocation,
+		Metadata: gpxMetadata{
+			Name: trip.Trip.Name,
+			Desc: trip.Trip.Description,
+			Time: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	route := gpxRoute{Name: trip.Trip.Name, Desc: tripSummary(trip.Trip)}
+	var days []gpxSegment
+	dayIndex := map[int]int{}
+
+	for _, loc := range trip.Locations {
+		if !hasCoordinates(loc) {
+			continue
+		}
+
+		point := gpxPoint{
+			Lat:  gpxCoordinate(loc.Latitude),
+			Lon:  gpxLongitude(loc.Longitude),
+			Name: loc.Name,
+			Desc: deref(loc.Notes),
+		}
+		if url := deref(loc.SiteURL); url != "" {
+			point.Link = &gpxLink{Href: url, Text: loc.Name}
+		}
+
+		doc.Waypoints = append(doc.Waypoints, point)
+
+		routePoint := point
+		routePoint.Link = nil
+		route.Points = append(route.Points, routePoint)
+
+		if loc.Day > 0 {
+			i, ok := dayIndex[loc.Day]
+			if !ok {
+				i = len(days)
+				dayIndex[loc.Day] = i
+				days = append(days, gpxSegment{})
+			}
+			days[i].Points = append(days[i].Points, gpxPoint{Lat: point.Lat, Lon: point.Lon, Name: loc.Name})
+		}
+	}
+
+	if len(route.Points) > 0 {
+		doc.Routes = append(doc.Routes, route)
+	}
+	if len(days) > 0 {
+		doc.Tracks = append(doc.Tracks, gpxTrack{Name: trip.Trip.Name + " (günlük)", Segments: days})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("export: failed to encode GPX: %v", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func gpxCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// gpxLongitude şemanın dışarıda bıraktığı 180'i aynı meridyen olan -180
+// olarak yazar.
+func gpxLongitude(value float64) string {
+	if value == 180 {
+		value = -180
+	}
+	return gpxCoordinate(value)
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+func gpxTestTrip(locations ...models.Location) *models.TripWithLocations {
+	return &models.TripWithLocations{
+		Trip: models.Trip{
+			Name:          "Ege & Akdeniz <turu>",
+			Description:   "Yaz tatili",
+			StartPosition: "İzmir",
+			EndPosition:   "Fiji",
+			StartDate:     "2026-06-01",
+			EndDate:       "2026-06-03",
+		},
+		Locations: locations,
+	}
+}
+
+func stringPtr(value string) *string { return &value }
+
+// TestGPXValidatesAgainstSchema üretilen dokümanları GPX 1.1 şemasına göre
+// xmllint ile doğrular.
+func TestGPXValidatesAgainstSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+	schema, err := filepath.Abs(filepath.Join("testdata", "gpx.xsd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		trip *models.TripWithLocations
+	}{
+		{
+			name: "empty_trip",
+			trip: gpxTestTrip(),
+		},
+		{
+			name: "days_links_and_notes",
+			trip: gpxTestTrip(
+				models.Location{Name: "Efes Antik Kenti", Latitude: 37.9395, Longitude: 27.3417, Day: 1,
+					Notes: stringPtr("Sabah erken gidin & su alın"), SiteURL: stringPtr("https://muze.gov.tr/efes?a=1&b=2")},
+				// Koordinatı olmayan lokasyon atlanır
+				models.Location{Name: "Bilinmeyen", Day: 1},
+				models.Location{Name: "Bodrum Kalesi", Latitude: 37.0317, Longitude: 27.4286, Day: 2},
+				models.Location{Name: "Gün atanmamış", Latitude: 37.5, Longitude: 27.2},
+			),
+		},
+		{
+			name: "edge_coordinates",
+			trip: gpxTestTrip(
+				// Şema boylamda 180'i dışarıda bırakır (maxExclusive)
+				models.Location{Name: "Taveuni", Latitude: -16.8, Longitude: 180, Day: 3},
+				models.Location{Name: "Güney Kutbu", Latitude: -90, Longitude: -180, Day: 3},
+				// Üstel gösterim xsd:decimal değildir
+				models.Location{Name: "Null Island yakını", Latitude: 0.0000001, Longitude: -0.00000005},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := GPX(tt.trip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), tt.name+".gpx")
+			if err := os.WriteFile(path, out, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := exec.Command(xmllint, "--noout", "--nonet", "--schema", schema, path)
+			if result, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("GPX does not validate against GPX 1.1 schema: %v\n%s\n%s", err, result, out)
+			}
+		})
+	}
+}
+
+func TestGPXCoordinateFormat(t *testing.T) {
+	out, err := GPX(gpxTestTrip(
+		models.Location{Name: "Taveuni", Latitude: -16.8, Longitude: 180},
+		models.Location{Name: "Null Island yakını", Latitude: 0.0000001, Longitude: 27.5},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`lat="-16.8" lon="-180"`, `lat="0.0000001" lon="27.5"`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("GPX does not contain %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(string(out), "e-") {
+		t.Errorf("GPX contains a coordinate in exponent notation:\n%s", out)
+	}
+}
@@ -0,0 +1,33 @@
+// internal/export/html.go for trip-plan-service
+package export
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+
+	"trip-plan-service/internal/models"
+)
+
+//go:embed templates/itinerary.html
+var templateFS embed.FS
+
+var itineraryTemplate = template.Must(template.New("itinerary.html").
+	Funcs(template.FuncMap{"km": formatKm}).
+	ParseFS(templateFS, "templates/itinerary.html"))
+
+// ItineraryHTML trip'i güne göre gruplanmış, dış kaynağa ihtiyaç duymayan
+// (CSS gömülü) yazdırılabilir bir HTML sayfası olarak üretir. Her gün adres,
+// bağlantı, not ve duraklar arası mesafeleri içeren bir tablodur.
+func ItineraryHTML(trip *models.TripWithLocations) ([]byte, error) {
+	view, err := newItinerary(trip)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := itineraryTemplate.Execute(&buf, view); err != nil {
+		return nil, fmt.Errorf("export: failed to render itinerary: %v", err)
+	}
+	return buf.Bytes(), nil
+}
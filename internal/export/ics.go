@@ -0,0 +1,179 @@
+// internal/export/ics.go for trip-plan-service
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trip-plan-service/internal/models"
+)
+
+const icsLineLimit = 75
+
+// ICS trip'leri tek bir iCalendar (RFC 5545) takvimine çevirir. Her trip için
+// başlangıçtan bitişe uzanan tüm gün bir etkinlik ve gün bilgisi olan her gün
+// için o günün lokasyonlarını listeleyen ayrı bir etkinlik üretilir.
+// name takvim uygulamalarında görünen takvim adıdır.
+func ICS(name string, trips ...models.TripWithLocations) []byte {
+	w := &icsWriter{}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//trip-plan-service//Trip Calendar//TR")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escapeText(name))
+
+	for _, trip := range trips {
+		start, err := time.Parse("2006-01-02", trip.Trip.StartDate)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse("2006-01-02", trip.Trip.EndDate)
+		if err != nil || end.Before(start) {
+			end = start
+		}
+
+		w.line("BEGIN:VEVENT")
+		w.line(fmt.Sprintf("UID:trip-%d@trip-plan-service", trip.Trip.ID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		// DTEND tüm gün etkinliklerde hariçtir, son günü de kapsaması için +1
+		w.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102"))
+		w.line("SUMMARY:" + escapeText(trip.Trip.Name))
+		w.line("DESCRIPTION:" + escapeText(tripDescription(trip.Trip)))
+		if trip.Trip.StartPosition != "" {
+			w.line("LOCATION:" + escapeText(trip.Trip.StartPosition))
+		}
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+
+		for _, day := range groupByDay(trip.Locations) {
+			date := start.AddDate(0, 0, day.number-1)
+
+			w.line("BEGIN:VEVENT")
+			w.line(fmt.Sprintf("UID:trip-%d-day-%d@trip-plan-service", trip.Trip.ID, day.number))
+			w.line("DTSTAMP:" + stamp)
+			w.line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+			w.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+			w.line(fmt.Sprintf("SUMMARY:%s - %d. gün", escapeText(trip.Trip.Name), day.number))
+			w.line("DESCRIPTION:" + escapeText(dayDescription(day.locations)))
+
+			names := make([]string, 0, len(day.locations))
+			for _, loc := range day.locations {
+				names = append(names, loc.Name)
+			}
+			w.line("LOCATION:" + escapeText(strings.Join(names, ", ")))
+
+			// GEO etkinlik başına tek konum alır; günün ilk koordinatlı durağı kullanılır
+			for _, loc := range day.locations {
+				if hasCoordinates(loc) {
+					w.line(fmt.Sprintf("GEO:%.6f;%.6f", loc.Latitude, loc.Longitude))
+					break
+				}
+			}
+			w.line("TRANSP:TRANSPARENT")
+			w.line("END:VEVENT")
+		}
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.String())
+}
+
+type dayLocations struct {
+	number    int
+	locations []models.Location
+}
+
+// groupByDay lokasyonları pozisyon sırasını koruyarak günlere ayırır; günü
+// olmayan lokasyonlar atlanır.
+func groupByDay(locations []models.Location) []dayLocations {
+	index := map[int]int{}
+	var days []dayLocations
+	for _, loc := range locations {
+		if loc.Day < 1 {
+			continue
+		}
+		i, ok := index[loc.Day]
+		if !ok {
+			i = len(days)
+			index[loc.Day] = i
+			days = append(days, dayLocations{number: loc.Day})
+		}
+		days[i].locations = append(days[i].locations, loc)
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].number < days[j].number })
+	return days
+}
+
+func tripDescription(trip models.Trip) string {
+	if trip.Description == "" {
+		return tripSummary(trip)
+	}
+	return tripSummary(trip) + "\n\n" + trip.Description
+}
+
+func dayDescription(locations []models.Location) string {
+	var b strings.Builder
+	for i, loc := range locations {
+		fmt.Fprintf(&b, "%d. %s", i+1, loc.Name)
+		if address := deref(loc.Address); address != "" {
+			fmt.Fprintf(&b, " (%s)", address)
+		}
+		if hasCoordinates(loc) {
+			fmt.Fprintf(&b, " [%.5f, %.5f]", loc.Latitude, loc.Longitude)
+		}
+		if notes := deref(loc.Notes); notes != "" {
+			b.WriteString(" - " + notes)
+		}
+		if url := deref(loc.SiteURL); url != "" {
+			b.WriteString(" " + url)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// escapeText TEXT değerlerindeki özel karakterleri RFC 5545'e göre kaçırır.
+// Tek başına \r de satır sonu sayılır; kaçırılmazsa satırı böler.
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\r", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+type icsWriter struct {
+	strings.Builder
+}
+
+// line satırı CRLF ile yazar ve 75 oktetten uzun satırları UTF-8 karakterlerini
+// bölmeden katlar.
+func (w *icsWriter) line(content string) {
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		// Devam satırlarının başındaki boşluk da sınıra dahildir
+		limit = icsLineLimit - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
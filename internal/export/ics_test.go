@@ -0,0 +1,105 @@
+package export
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"trip-plan-service/internal/models"
+)
+
+var updateGolden = flag.Bool("update", false, "testdata altındaki golden dosyaları yeniden yazar")
+
+var dtstamp = regexp.MustCompile(`DTSTAMP:\d{8}T\d{6}Z`)
+
+func icsTestTrip() models.TripWithLocations {
+	trip := gpxTestTrip(
+		models.Location{Name: "Efes Antik Kenti", Day: 1, Latitude: 37.9395, Longitude: 27.3417,
+			Address: stringPtr("Selçuk, İzmir"), Notes: stringPtr("Sabah erken gidin; öğlen çok sıcak")},
+		models.Location{Name: "Şirince", Day: 1},
+		models.Location{Name: "Gün atanmamış", Latitude: 37.8579, Longitude: 27.2610},
+		models.Location{Name: "Bodrum Kalesi", Day: 3, Latitude: 37.0317, Longitude: 27.4286,
+			SiteURL: stringPtr("https://muze.gov.tr/bodrum")},
+	)
+	trip.Trip.ID = 7
+	trip.Trip.Name = "Ege & Akdeniz kıyıları boyunca uzun, çok duraklı ve adı özellikle uzun tutulmuş bir yaz turu"
+	trip.Trip.Description = "Satır bir\r\nsatır iki\rsatır üç\nsatır dört"
+	return *trip
+}
+
+// Üretilen takvim testdata/trip.ics ile aynı olmalı (DTSTAMP hariç).
+// Güncellemek için: go test ./internal/export -run TestICSGolden -update
+func TestICSGolden(t *testing.T) {
+	got := dtstamp.ReplaceAllString(string(ICS("Gezilerim", icsTestTrip())), "DTSTAMP:20260101T000000Z")
+
+	golden := filepath.Join("testdata", "trip.ics")
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("ICS output differs from %s:\n%s", golden, got)
+	}
+}
+
+// Her satır CRLF ile bitmeli, 75 okteti geçmemeli ve katlama UTF-8
+// karakterlerini bölmemeli; açıldığında metin aynen geri gelmeli.
+func TestICSLineFolding(t *testing.T) {
+	out := string(ICS("Gezilerim", icsTestTrip()))
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatal("output does not end with CRLF")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	folded := 0
+	for i, line := range lines {
+		if len(line) > icsLineLimit {
+			t.Errorf("line %d is %d octets: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 character: %q", i+1, line)
+		}
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("line %d contains a raw line break: %q", i+1, line)
+		}
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+	if folded == 0 {
+		t.Fatal("no line was folded")
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "\r\nSUMMARY:Ege & Akdeniz kıyıları boyunca uzun\\, çok duraklı ve adı özellikle uzun tutulmuş bir yaz turu\r\n") {
+		t.Errorf("unfolded summary does not match:\n%s", unfolded)
+	}
+	if !strings.Contains(unfolded, `Satır bir\nsatır iki\nsatır üç\nsatır dört`) {
+		t.Errorf("description line breaks are not escaped:\n%s", unfolded)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := map[string]string{
+		`a\b`:        `a\\b`,
+		"a;b,c":      `a\;b\,c`,
+		"a\r\nb":     `a\nb`,
+		"a\rb\nc":    `a\nb\nc`,
+		"a\r\r\nb":   `a\n\nb`,
+		"düz metin.": "düz metin.",
+	}
+	for in, want := range tests {
+		if got := escapeText(in); got != want {
+			t.Errorf("escapeText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// internal/export/itinerary.go for trip-plan-service
+package export
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"trip-plan-service/internal/distance"
+	"trip-plan-service/internal/models"
+)
+
+// itinerary HTML ve Markdown çıktılarının ortak, güne göre gruplanmış görünümüdür.
+type itinerary struct {
+	Trip          models.Trip
+	Days          []itineraryDay
+	TotalDistance float64
+	GeneratedAt   string
+}
+
+// itineraryDay Number 0 ise gün atanmamış durakları tutar.
+type itineraryDay struct {
+	Number    int
+	Date      string
+	Locations []itineraryLocation
+	Distance  float64
+}
+
+type itineraryLocation struct {
+	Index          int
+	Name           string
+	Address        string
+	Notes          string
+	SiteURL        string
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+	// LegKm bir önceki koordinatlı duraktan bu durağa kuş uçuşu mesafedir;
+	// HasLeg false ise hesaplanamamıştır (ilk durak ya da koordinat yok).
+	LegKm  float64
+	HasLeg bool
+}
+
+// newItinerary durakları günlere ayırır; mesafeler trip.Distances'tan okunur,
+// boşsa varsayılan ulaşım türüyle hesaplanır. Gün atanmamış duraklar en sona
+// konur.
+func newItinerary(trip *models.TripWithLocations) (itinerary, error) {
+	view := itinerary{
+		Trip:        trip.Trip,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04"),
+	}
+
+	distances := trip.Distances
+	if distances == nil {
+		var err error
+		distances, err = distance.DefaultProfiles.Compute(trip.Locations, "")
+		if err != nil {
+			return itinerary{}, fmt.Errorf("export: failed to compute distances: %v", err)
+		}
+	}
+	view.TotalDistance = distances.TotalDistanceKm
+
+	legs := map[int]float64{}
+	for _, leg := range distances.Legs {
+		legs[leg.ToPosition-1] = leg.DistanceKm
+	}
+	dayKm := map[int]float64{}
+	for _, day := range distances.Days {
+		dayKm[day.Day] = day.DistanceKm
+	}
+
+	index := map[int]int{}
+	var unscheduled itineraryDay
+	for i, loc := range trip.Locations {
+		entry := itineraryLocation{
+			Index:          i + 1,
+			Name:           loc.Name,
+			Address:        deref(loc.Address),
+			Notes:          deref(loc.Notes),
+			SiteURL:        deref(loc.SiteURL),
+			Latitude:       loc.Latitude,
+			Longitude:      loc.Longitude,
+			HasCoordinates: hasCoordinates(loc),
+		}
+		entry.LegKm, entry.HasLeg = legs[i]
+
+		if loc.Day < 1 {
+			unscheduled.Locations = append(unscheduled.Locations, entry)
+			unscheduled.Distance += entry.LegKm
+			continue
+		}
+
+		d, ok := index[loc.Day]
+		if !ok {
+			d = len(view.Days)
+			index[loc.Day] = d
+			view.Days = append(view.Days, itineraryDay{Number: loc.Day, Date: loc.Date, Distance: dayKm[loc.Day]})
+		}
+		view.Days[d].Locations = append(view.Days[d].Locations, entry)
+	}
+
+	sort.SliceStable(view.Days, func(i, j int) bool { return view.Days[i].Number < view.Days[j].Number })
+	if len(unscheduled.Locations) > 0 {
+		view.Days = append(view.Days, unscheduled)
+	}
+	return view, nil
+}
+
+// formatKm mesafeyi okunabilir biçimde yazar (1 km altı metre olarak).
+func formatKm(km float64) string {
+	if km < 1 {
+		return fmt.Sprintf("%.0f m", km*1000)
+	}
+	return fmt.Sprintf("%.1f km", km)
+}
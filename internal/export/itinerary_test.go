@@ -0,0 +1,43 @@
+package export
+
+import (
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+// Handler'ın eklediği mesafeler (seçilen ulaşım türüyle) yeniden
+// hesaplanmadan kullanılmalı.
+func TestItineraryUsesTripDistances(t *testing.T) {
+	trip := gpxTestTrip(
+		models.Location{Name: "A", Day: 1, Latitude: 38.42, Longitude: 27.14},
+		models.Location{Name: "B", Day: 1, Latitude: 37.04, Longitude: 27.43},
+		models.Location{Name: "C", Latitude: 36.89, Longitude: 30.71},
+	)
+	trip.Distances = &models.TripDistances{
+		TotalDistanceKm: 500,
+		Days:            []models.DayDistance{{Day: 1, DistanceKm: 200}},
+		Legs: []models.Leg{
+			{FromPosition: 1, ToPosition: 2, Day: 1, DistanceKm: 200},
+			{FromPosition: 2, ToPosition: 3, DistanceKm: 300},
+		},
+	}
+
+	view, err := newItinerary(trip)
+	if err != nil {
+		t.Fatalf("newItinerary: %v", err)
+	}
+	if view.TotalDistance != 500 {
+		t.Errorf("TotalDistance = %v, want 500", view.TotalDistance)
+	}
+	if len(view.Days) != 2 || view.Days[0].Distance != 200 || view.Days[1].Distance != 300 {
+		t.Fatalf("days = %+v, want day 1 with 200 km and unscheduled with 300 km", view.Days)
+	}
+	first := view.Days[0].Locations[0]
+	if first.HasLeg {
+		t.Errorf("first stop has a leg of %v km", first.LegKm)
+	}
+	if second := view.Days[0].Locations[1]; !second.HasLeg || second.LegKm != 200 {
+		t.Errorf("second stop leg = %v (%t), want 200", second.LegKm, second.HasLeg)
+	}
+}
@@ -0,0 +1,100 @@
+// internal/export/markdown.go for trip-plan-service
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"trip-plan-service/internal/models"
+)
+
+// ItineraryMarkdown trip'i ItineraryHTML ile aynı içerikte, her gün için bir
+// tablo içeren Markdown dokümanı olarak üretir.
+func ItineraryMarkdown(trip *models.TripWithLocations) ([]byte, error) {
+	view, err := newItinerary(trip)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", markdownText(view.Trip.Name))
+
+	meta := fmt.Sprintf("%s – %s", view.Trip.StartDate, view.Trip.EndDate)
+	if view.Trip.TotalDays > 0 {
+		meta += fmt.Sprintf(" · %d gün", view.Trip.TotalDays)
+	}
+	if view.Trip.StartPosition != "" || view.Trip.EndPosition != "" {
+		meta += fmt.Sprintf(" · %s → %s", markdownText(view.Trip.StartPosition), markdownText(view.Trip.EndPosition))
+	}
+	if view.TotalDistance > 0 {
+		meta += " · toplam " + formatKm(view.TotalDistance)
+	}
+	fmt.Fprintf(&b, "%s\n\n", meta)
+
+	if view.Trip.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", markdownText(view.Trip.Description))
+	}
+
+	if len(view.Days) == 0 {
+		b.WriteString("Bu trip için kayıtlı durak yok.\n")
+		return []byte(b.String()), nil
+	}
+
+	for _, day := range view.Days {
+		title := "Gün atanmamış duraklar"
+		if day.Number > 0 {
+			title = fmt.Sprintf("%d. gün", day.Number)
+			if day.Date != "" {
+				title += " · " + day.Date
+			}
+		}
+		if day.Distance > 0 {
+			title += fmt.Sprintf(" (%s)", formatKm(day.Distance))
+		}
+		fmt.Fprintf(&b, "## %s\n\n", title)
+
+		b.WriteString("| # | Durak | Adres | Notlar | Bağlantı | Mesafe |\n")
+		b.WriteString("|--:|-------|-------|--------|----------|-------:|\n")
+		for _, loc := range day.Locations {
+			link := ""
+			if loc.SiteURL != "" {
+				link = fmt.Sprintf("<%s>", markdownURL(loc.SiteURL))
+			}
+			leg := "–"
+			if loc.HasLeg {
+				leg = formatKm(loc.LegKm)
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n",
+				loc.Index, markdownCell(loc.Name), markdownCell(loc.Address), markdownCell(loc.Notes), link, leg)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("_Mesafeler bir önceki duraktan kuş uçuşu hesaplanmıştır._\n")
+	return []byte(b.String()), nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func markdownText(value string) string {
+	return markdownEscaper.Replace(value)
+}
+
+var markdownURLEscaper = strings.NewReplacer(
+	"<", "%3C", ">", "%3E", "|", "%7C", " ", "%20", "\r", "%0D", "\n", "%0A", "\t", "%09",
+)
+
+// markdownURL bağlantıyı <...> içinde satırı ya da tabloyu bozmayacak
+// şekilde yazar; açılı parantez, boru, boşluk ve satır sonları kodlanır.
+func markdownURL(value string) string {
+	return markdownURLEscaper.Replace(value)
+}
+
+// markdownCell tablo hücresini bozacak boru ve satır sonlarını da kaçırır.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(markdownText(value), "|", `\|`)
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
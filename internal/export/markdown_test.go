@@ -0,0 +1,43 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+// Kullanıcı metinleri ve bağlantılar Markdown yapısını bozmamalı: her durak
+// tek bir tablo satırında kalmalı, açıklama başlık ya da bağlantıya dönmemeli.
+func TestItineraryMarkdownEscapesUserText(t *testing.T) {
+	trip := gpxTestTrip(models.Location{
+		Name:      "Efes | Selçuk",
+		Day:       1,
+		Latitude:  37.9395,
+		Longitude: 27.3417,
+		Notes:     stringPtr("sabah\nerken"),
+		SiteURL:   stringPtr("https://example.com/a|b>c\nd e"),
+	})
+	trip.Trip.Description = "# [tıkla](https://evil.example)"
+
+	out, err := ItineraryMarkdown(trip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(out)
+
+	if !strings.Contains(doc, "\n\\# \\[tıkla\\](https://evil.example)\n") {
+		t.Errorf("description is not escaped:\n%s", doc)
+	}
+
+	var rows []string
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, "| 1 |") {
+			rows = append(rows, line)
+		}
+	}
+	want := `| 1 | Efes \| Selçuk |  | sabah<br>erken | <https://example.com/a%7Cb%3Ec%0Ad%20e> | – |`
+	if len(rows) != 1 || rows[0] != want {
+		t.Errorf("stop rows = %q, want %q", rows, want)
+	}
+}
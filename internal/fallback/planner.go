@@ -0,0 +1,233 @@
+// internal/fallback/planner.go for trip-plan-service
+package fallback
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trip-plan-service/internal/geo"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+// maxDays yedek planın üreteceği en uzun gün sayısıdır.
+const maxDays = 60
+
+// nearbyRadiusKm başlangıç/bitiş şehri bilinmiyorsa ya da gidiş-dönüş
+// rotalarda aday noktaların aranacağı yarıçaptır.
+const nearbyRadiusKm = 150
+
+// ErrInvalidDates tarih aralığı yedek plan üretmeye uygun değilse döner.
+var ErrInvalidDates = errors.New("fallback: invalid start_date/end_date")
+
+//go:embed pois.json
+var poiData []byte
+
+type city struct {
+	Name      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	POIs      []poi   `json:"pois"`
+}
+
+type poi struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Notes     string  `json:"notes"`
+
+	city string
+}
+
+func (p poi) point() geo.Point {
+	return geo.Point{Latitude: p.Latitude, Longitude: p.Longitude}
+}
+
+// Planner AI servisi yanıt veremediğinde kullanılan, kural tabanlı ve
+// deterministik plan üreticisidir. AIClient ile aynı sözleşmeyi uygular.
+type Planner struct {
+	cities []city
+}
+
+func NewPlanner() (*Planner, error) {
+	var cities []city
+	if err := json.Unmarshal(poiData, &cities); err != nil {
+		return nil, fmt.Errorf("fallback: failed to parse POI dataset: %v", err)
+	}
+	for i := range cities {
+		for j := range cities[i].POIs {
+			cities[i].POIs[j].city = cities[i].Name
+		}
+	}
+	return &Planner{cities: cities}, nil
+}
+
+// GenerateTripPlan başlangıç ve bitiş tarihleri arasındaki her gün için bir
+// durak içeren tek seçenekli bir iskelet plan döner. İlk gün başlangıç, son
+// gün bitiş noktasıdır; aradaki günler rota üzerindeki POI'lerle doldurulur.
+func (p *Planner) GenerateTripPlan(ctx context.Context, req *proto.PromptRequest) (*proto.TripOptionsResponse, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidDates
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil || endDate.Before(startDate) {
+		return nil, ErrInvalidDates
+	}
+
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	if days > maxDays {
+		return nil, fmt.Errorf("%w: trip longer than %d days", ErrInvalidDates, maxDays)
+	}
+
+	start := p.resolve(req.StartPosition)
+	end := p.resolve(req.EndPosition)
+
+	dateOf := func(day int) string {
+		return startDate.AddDate(0, 0, day-1).Format("2006-01-02")
+	}
+
+	var plan []*proto.DailyPlan
+	plan = append(plan, &proto.DailyPlan{
+		Day:      1,
+		Date:     dateOf(1),
+		Location: positionLocation(req.StartPosition, start),
+	})
+
+	if days > 2 {
+		stops := p.stopsBetween(start, end, days-2)
+		for day := 2; day < days; day++ {
+			dailyPlan := &proto.DailyPlan{Day: int32(day), Date: dateOf(day)}
+			if i := day - 2; i < len(stops) {
+				dailyPlan.Location = &proto.Location{
+					Name:      stops[i].Name,
+					Address:   stops[i].city,
+					Latitude:  stops[i].Latitude,
+					Longitude: stops[i].Longitude,
+					Notes:     stops[i].Notes,
+				}
+			}
+			plan = append(plan, dailyPlan)
+		}
+	}
+
+	plan = append(plan, &proto.DailyPlan{
+		Day:      int32(days),
+		Date:     dateOf(days),
+		Location: positionLocation(req.EndPosition, end),
+	})
+
+	return &proto.TripOptionsResponse{
+		TripOptions: []*proto.TripOption{{
+			Theme:       "Temel Plan",
+			Description: "AI servisine ulaşılamadığı için otomatik oluşturulan temel gün planı. Durakları dilediğiniz gibi düzenleyebilirsiniz.",
+			Trip: &proto.Trip{
+				UserId:        req.UserId,
+				Name:          req.Name,
+				Description:   req.Description,
+				StartPosition: req.StartPosition,
+				EndPosition:   req.EndPosition,
+				StartDate:     req.StartDate,
+				EndDate:       req.EndDate,
+				TotalDays:     int32(days),
+			},
+			DailyPlan: plan,
+		}},
+	}, nil
+}
+
+// resolve serbest metin konumu veri setindeki bir POI ya da şehirle eşleştirir.
+func (p *Planner) resolve(position string) *poi {
+	text := geo.NormalizeName(position)
+	if text == "" {
+		return nil
+	}
+
+	for _, c := range p.cities {
+		for _, candidate := range c.POIs {
+			if strings.Contains(text, geo.NormalizeName(candidate.Name)) {
+				match := candidate
+				return &match
+			}
+		}
+	}
+	for _, c := range p.cities {
+		if strings.Contains(text, geo.NormalizeName(c.Name)) {
+			return &poi{Name: c.Name, Latitude: c.Latitude, Longitude: c.Longitude, city: c.Name}
+		}
+	}
+	return nil
+}
+
+// stopsBetween başlangıç ile bitiş arasındaki koridorda kalan POI'lerden
+// en fazla count tanesini rota sırasına göre seçer.
+func (p *Planner) stopsBetween(start, end *poi, count int) []poi {
+	if start == nil && end == nil {
+		return nil
+	}
+
+	var candidates []poi
+	for _, c := range p.cities {
+		for _, candidate := range c.POIs {
+			if onRoute(start, end, candidate.point()) && !sameName(candidate, start) && !sameName(candidate, end) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	origin := start
+	if origin == nil {
+		origin = end
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return geo.HaversineKm(origin.point(), candidates[i].point()) < geo.HaversineKm(origin.point(), candidates[j].point())
+	})
+
+	if len(candidates) <= count {
+		return candidates
+	}
+
+	// Gün sayısından fazla aday varsa rotayı eşit aralıklarla örnekle.
+	stops := make([]poi, 0, count)
+	for i := 0; i < count; i++ {
+		stops = append(stops, candidates[i*len(candidates)/count])
+	}
+	return stops
+}
+
+func onRoute(start, end *poi, point geo.Point) bool {
+	if start == nil || end == nil {
+		known := start
+		if known == nil {
+			known = end
+		}
+		return geo.HaversineKm(known.point(), point) <= nearbyRadiusKm
+	}
+
+	direct := geo.HaversineKm(start.point(), end.point())
+	if direct < nearbyRadiusKm {
+		return geo.HaversineKm(start.point(), point) <= nearbyRadiusKm
+	}
+	detour := geo.HaversineKm(start.point(), point) + geo.HaversineKm(point, end.point())
+	return detour <= direct*1.3
+}
+
+func sameName(candidate poi, other *poi) bool {
+	return other != nil && candidate.Name == other.Name
+}
+
+func positionLocation(position string, resolved *poi) *proto.Location {
+	location := &proto.Location{Name: position, Address: position}
+	if resolved != nil {
+		location.Latitude = resolved.Latitude
+		location.Longitude = resolved.Longitude
+		location.Notes = resolved.Notes
+	}
+	return location
+}
@@ -0,0 +1,217 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/geo"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+// testPlanner aynı enlemde doğuya uzanan bir rota ile yanında koridor
+// dışında kalan iki POI içerir. Boylamda bir derece burada ~88 km'dir.
+func testPlanner() *Planner {
+	return &Planner{cities: []city{
+		{Name: "Batı", Latitude: 38, Longitude: 27, POIs: []poi{
+			{Name: "Geri Durak", Latitude: 38, Longitude: 26.2, city: "Batı"},
+			{Name: "Liman", Latitude: 38.01, Longitude: 27.01, city: "Batı"},
+		}},
+		{Name: "Orta", Latitude: 38, Longitude: 29, POIs: []poi{
+			{Name: "Üçüncü Durak", Latitude: 38, Longitude: 30, city: "Orta"},
+			{Name: "Birinci Durak", Latitude: 38, Longitude: 28, city: "Orta"},
+			{Name: "Kuzey Durak", Latitude: 40, Longitude: 29, city: "Orta"},
+			{Name: "İkinci Durak", Latitude: 38, Longitude: 29, city: "Orta"},
+		}},
+		{Name: "Doğu", Latitude: 38, Longitude: 31},
+	}}
+}
+
+func request(start, end, startDate, endDate string) *proto.PromptRequest {
+	return &proto.PromptRequest{
+		UserId:        "user-1",
+		Name:          "Yedek",
+		StartPosition: start,
+		EndPosition:   end,
+		StartDate:     startDate,
+		EndDate:       endDate,
+	}
+}
+
+// stopNames planı "gün:ad" olarak yazar; lokasyonu olmayan günler "gün:-" olur.
+func stopNames(resp *proto.TripOptionsResponse) string {
+	var names []string
+	for _, dailyPlan := range resp.TripOptions[0].DailyPlan {
+		name := "-"
+		if dailyPlan.Location != nil {
+			name = dailyPlan.Location.Name
+		}
+		names = append(names, fmt.Sprintf("%d:%s", dailyPlan.Day, name))
+	}
+	return strings.Join(names, ", ")
+}
+
+func TestGenerateTripPlanSelectsCorridorStops(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		endDate    string
+		want       string
+	}{
+		{
+			name:    "koridordaki POI'ler rota sırasıyla seçilir",
+			start:   "Batı",
+			end:     "Doğu",
+			endDate: "2026-06-06",
+			want:    "1:Batı, 2:Liman, 3:Birinci Durak, 4:İkinci Durak, 5:Üçüncü Durak, 6:Doğu",
+		},
+		{
+			name:    "gün sayısından fazla aday eşit aralıklarla örneklenir",
+			start:   "Batı",
+			end:     "Doğu",
+			endDate: "2026-06-04",
+			want:    "1:Batı, 2:Liman, 3:İkinci Durak, 4:Doğu",
+		},
+		{
+			name:    "aday kalmayan günler boş kalır",
+			start:   "Batı",
+			end:     "Doğu",
+			endDate: "2026-06-08",
+			want:    "1:Batı, 2:Liman, 3:Birinci Durak, 4:İkinci Durak, 5:Üçüncü Durak, 6:-, 7:-, 8:Doğu",
+		},
+		{
+			name:    "başlangıç ve bitiş olan POI'ler ara durak olmaz",
+			start:   "Liman",
+			end:     "İkinci Durak",
+			endDate: "2026-06-04",
+			want:    "1:Liman, 2:Birinci Durak, 3:-, 4:İkinci Durak",
+		},
+		{
+			name:    "sadece başlangıç biliniyorsa yakın çevre kullanılır",
+			start:   "Batı",
+			end:     "Bilinmeyen Kasaba",
+			endDate: "2026-06-05",
+			want:    "1:Batı, 2:Liman, 3:Geri Durak, 4:Birinci Durak, 5:Bilinmeyen Kasaba",
+		},
+		{
+			name:    "iki uç da bilinmiyorsa ara durak yoktur",
+			start:   "Bilinmeyen",
+			end:     "Başka Yer",
+			endDate: "2026-06-03",
+			want:    "1:Bilinmeyen, 2:-, 3:Başka Yer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := testPlanner().GenerateTripPlan(context.Background(), request(tt.start, tt.end, "2026-06-01", tt.endDate))
+			if err != nil {
+				t.Fatalf("GenerateTripPlan: %v", err)
+			}
+			if got := stopNames(resp); got != tt.want {
+				t.Errorf("plan = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Kısa rotalarda (uçlar arası nearbyRadiusKm altında) koridor yerine
+// başlangıç çevresi kullanılır.
+func TestGenerateTripPlanShortRouteUsesRadius(t *testing.T) {
+	resp, err := testPlanner().GenerateTripPlan(context.Background(), request("Batı", "Birinci Durak", "2026-06-01", "2026-06-04"))
+	if err != nil {
+		t.Fatalf("GenerateTripPlan: %v", err)
+	}
+	if got, want := stopNames(resp), "1:Batı, 2:Liman, 3:Geri Durak, 4:Birinci Durak"; got != want {
+		t.Errorf("plan = %s\nwant   %s", got, want)
+	}
+}
+
+func TestGenerateTripPlanFillsTripAndDates(t *testing.T) {
+	resp, err := testPlanner().GenerateTripPlan(context.Background(), request("Batı", "Doğu", "2026-06-30", "2026-07-02"))
+	if err != nil {
+		t.Fatalf("GenerateTripPlan: %v", err)
+	}
+
+	option := resp.TripOptions[0]
+	if option.Trip.TotalDays != 3 || option.Trip.UserId != "user-1" || option.Trip.StartPosition != "Batı" {
+		t.Errorf("trip = %+v", option.Trip)
+	}
+	var dates []string
+	for _, dailyPlan := range option.DailyPlan {
+		dates = append(dates, dailyPlan.Date)
+	}
+	if got, want := strings.Join(dates, ","), "2026-06-30,2026-07-01,2026-07-02"; got != want {
+		t.Errorf("dates = %s, want %s", got, want)
+	}
+	if first := option.DailyPlan[0].Location; first.Latitude != 38 || first.Longitude != 27 {
+		t.Errorf("start = %+v, want the resolved coordinates of Batı", first)
+	}
+}
+
+func TestGenerateTripPlanInvalidDates(t *testing.T) {
+	tests := []struct {
+		name               string
+		startDate, endDate string
+	}{
+		{name: "başlangıç tarihi geçersiz", startDate: "01.06.2026", endDate: "2026-06-03"},
+		{name: "bitiş tarihi geçersiz", startDate: "2026-06-01", endDate: ""},
+		{name: "bitiş başlangıçtan önce", startDate: "2026-06-03", endDate: "2026-06-01"},
+		{name: "60 günden uzun", startDate: "2026-06-01", endDate: "2026-07-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testPlanner().GenerateTripPlan(context.Background(), request("Batı", "Doğu", tt.startDate, tt.endDate))
+			if !errors.Is(err, ErrInvalidDates) {
+				t.Errorf("err = %v, want ErrInvalidDates", err)
+			}
+		})
+	}
+}
+
+// maxDays sınırın kendisi kabul edilir.
+func TestGenerateTripPlanMaxDays(t *testing.T) {
+	resp, err := testPlanner().GenerateTripPlan(context.Background(), request("Batı", "Doğu", "2026-06-01", "2026-07-30"))
+	if err != nil {
+		t.Fatalf("GenerateTripPlan: %v", err)
+	}
+	plan := resp.TripOptions[0].DailyPlan
+	if len(plan) != maxDays || plan[len(plan)-1].Day != maxDays || resp.TripOptions[0].Trip.TotalDays != maxDays {
+		t.Errorf("plan has %d days ending on day %d, want %d", len(plan), plan[len(plan)-1].Day, maxDays)
+	}
+}
+
+// Gömülü veri setiyle de seçilen her durak koridorun içinde kalmalı.
+func TestGenerateTripPlanWithDataset(t *testing.T) {
+	planner, err := NewPlanner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := planner.GenerateTripPlan(context.Background(), request("İzmir", "Antalya", "2026-06-01", "2026-06-06"))
+	if err != nil {
+		t.Fatalf("GenerateTripPlan: %v", err)
+	}
+
+	plan := resp.TripOptions[0].DailyPlan
+	start, end := plan[0].Location, plan[len(plan)-1].Location
+	if start.Latitude == 0 || end.Latitude == 0 {
+		t.Fatalf("start %+v or end %+v was not resolved", start, end)
+	}
+	from := geo.Point{Latitude: start.Latitude, Longitude: start.Longitude}
+	to := geo.Point{Latitude: end.Latitude, Longitude: end.Longitude}
+	direct := geo.HaversineKm(from, to)
+
+	for _, dailyPlan := range plan[1 : len(plan)-1] {
+		if dailyPlan.Location == nil {
+			continue
+		}
+		point := geo.Point{Latitude: dailyPlan.Location.Latitude, Longitude: dailyPlan.Location.Longitude}
+		if detour := geo.HaversineKm(from, point) + geo.HaversineKm(point, to); detour > direct*1.3 {
+			t.Errorf("day %d %q is off the corridor: %.0f km detour for a %.0f km route", dailyPlan.Day, dailyPlan.Location.Name, detour, direct)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// internal/geo/geo.go for trip-plan-service
+package geo
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const earthRadiusKm = 6371.0
+
+// Point enlem/boylam çiftidir (derece).
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// IsZero koordinatın hiç doldurulmadığını (0,0) gösterir.
+func (p Point) IsZero() bool {
+	return p.Latitude == 0 && p.Longitude == 0
+}
+
+// HaversineKm iki nokta arasındaki büyük daire mesafesini kilometre olarak döner.
+func HaversineKm(a, b Point) float64 {
+	lat1 := toRadians(a.Latitude)
+	lat2 := toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+var nameReplacer = strings.NewReplacer(
+	"ı", "i", "İ", "i", "I", "i",
+	"ş", "s", "Ş", "s",
+	"ğ", "g", "Ğ", "g",
+	"ü", "u", "Ü", "u",
+	"ö", "o", "Ö", "o",
+	"ç", "c", "Ç", "c",
+	"â", "a", "î", "i", "û", "u",
+)
+
+// NormalizeName yer adlarını karşılaştırmak için küçük harfe çevirir,
+// Türkçe karakterleri sadeleştirir ve noktalama/fazla boşlukları atar.
+func NormalizeName(name string) string {
+	name = strings.ToLower(nameReplacer.Replace(name))
+
+	var b strings.Builder
+	space := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+// BBox noktaları kapsayan en küçük enlem/boylam kutusudur.
+type BBox struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// Bounds noktaların sınır kutusunu döner; nokta yoksa ok false olur.
+func Bounds(points []Point) (box BBox, ok bool) {
+	for i, p := range points {
+		if i == 0 {
+			box = BBox{p.Latitude, p.Longitude, p.Latitude, p.Longitude}
+			continue
+		}
+		box.MinLatitude = math.Min(box.MinLatitude, p.Latitude)
+		box.MinLongitude = math.Min(box.MinLongitude, p.Longitude)
+		box.MaxLatitude = math.Max(box.MaxLatitude, p.Latitude)
+		box.MaxLongitude = math.Max(box.MaxLongitude, p.Longitude)
+	}
+	return box, len(points) > 0
+}
+
+// BoxesAround center'a radiusKm mesafedeki tüm noktaları kapsayan
+// enlem/boylam kutularını döner. Kutu 180. meridyeni geçiyorsa iki parçaya
+// bölünür; kutupları içeriyorsa tüm boylamları kapsar. Kutular mesafe
+// kontrolü yerine geçmez, sadece index'li ön eleme içindir.
+func BoxesAround(center Point, radiusKm float64) []BBox {
+	latDelta := toDegrees(radiusKm / earthRadiusKm)
+	minLat := math.Max(-90, center.Latitude-latDelta)
+	maxLat := math.Min(90, center.Latitude+latDelta)
+	if minLat == -90 || maxLat == 90 {
+		return []BBox{{minLat, -180, maxLat, 180}}
+	}
+
+	// Boylam payı kutunun ekvatordan en uzak kenarına göre hesaplanır
+	widest := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	lonDelta := latDelta / math.Cos(toRadians(widest))
+	if lonDelta >= 180 {
+		return []BBox{{minLat, -180, maxLat, 180}}
+	}
+
+	minLon, maxLon := center.Longitude-lonDelta, center.Longitude+lonDelta
+	switch {
+	case minLon < -180:
+		return []BBox{{minLat, minLon + 360, maxLat, 180}, {minLat, -180, maxLat, maxLon}}
+	case maxLon > 180:
+		return []BBox{{minLat, minLon, maxLat, 180}, {minLat, -180, maxLat, maxLon - 360}}
+	}
+	return []BBox{{minLat, minLon, maxLat, maxLon}}
+}
+
+// Centroid noktaların küre üzerindeki ağırlık merkezini döner; noktalar
+// birim vektörlere çevrilip ortalandığı için uzak noktalarda da doğrudur.
+func Centroid(points []Point) (Point, bool) {
+	if len(points) == 0 {
+		return Point{}, false
+	}
+
+	var x, y, z float64
+	for _, p := range points {
+		lat, lon := toRadians(p.Latitude), toRadians(p.Longitude)
+		x += math.Cos(lat) * math.Cos(lon)
+		y += math.Cos(lat) * math.Sin(lon)
+		z += math.Sin(lat)
+	}
+	n := float64(len(points))
+	x, y, z = x/n, y/n, z/n
+
+	return Point{
+		Latitude:  toDegrees(math.Atan2(z, math.Hypot(x, y))),
+		Longitude: toDegrees(math.Atan2(y, x)),
+	}, true
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
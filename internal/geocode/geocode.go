@@ -0,0 +1,287 @@
+// internal/geocode/geocode.go for trip-plan-service
+package geocode
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"trip-plan-service/internal/geo"
+)
+
+// offshoreToleranceKm sınır poligonlarının dışında kalan bir noktanın en
+// yakın ülkeye atanabileceği en uzun mesafedir (kıyıya yakın tekne turu,
+// iskele vb. için).
+const offshoreToleranceKm = 10
+
+// boundaries.geojson elle derlenmiş, kaba çözünürlüklü bir veri setidir:
+// Türkiye ve komşu ülkeler için kara sınırlarını izleyen, deniz tarafında
+// ise cömert tutulmuş poligonlar, Türkiye kıyısına yakın Yunan adaları için
+// ayrı poligonlar, Türkiye'deki il merkezleri ve belli başlı ilçe merkezleri
+// ile komşu ülkelerin birinci düzey bölge merkezlerini içerir. İl sınırları
+// yoktur; bölge, aynı ülkedeki en yakın yerleşimin ilidir (Voronoi
+// yaklaşımı). Bu yüzden il sınırına birkaç kilometre yakın noktalar komşu
+// ile etiketlenebilir. Veri setinin dışındaki ülkeler etiketlenmez.
+//
+//go:embed boundaries.geojson
+var boundaryData []byte
+
+// Region bir koordinatın düştüğü ülke ve idari bölgedir.
+type Region struct {
+	CountryCode string `json:"country_code"`
+	Country     string `json:"country"`
+	AdminRegion string `json:"admin_region,omitempty"`
+	RegionCode  string `json:"region_code,omitempty"`
+}
+
+// Place veri setindeki bir yerleşimdir. Seat il merkezlerini işaretler.
+type Place struct {
+	Name        string    `json:"name"`
+	CountryCode string    `json:"country_code"`
+	Region      string    `json:"region"`
+	RegionCode  string    `json:"region_code"`
+	Seat        bool      `json:"seat"`
+	Point       geo.Point `json:"point"`
+}
+
+type country struct {
+	code     string
+	name     string
+	polygons []polygon
+}
+
+// polygon ilk halkası dış sınır, kalanları delik olan bir poligondur.
+// Noktalar [boylam, enlem] sırasındadır.
+type polygon struct {
+	rings [][][2]float64
+	area  float64
+}
+
+// ReverseGeocoder koordinatları gömülü veri setine göre ülke ve bölgeye
+// çevirir. Oluşturulduktan sonra salt okunurdur; eşzamanlı kullanılabilir.
+type ReverseGeocoder struct {
+	countries []country
+	places    []Place
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties struct {
+			Kind        string `json:"kind"`
+			CountryCode string `json:"country_code"`
+			Name        string `json:"name"`
+			Region      string `json:"region"`
+			RegionCode  string `json:"region_code"`
+			Seat        bool   `json:"seat"`
+		} `json:"properties"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+func New() (*ReverseGeocoder, error) {
+	var fc featureCollection
+	if err := json.Unmarshal(boundaryData, &fc); err != nil {
+		return nil, fmt.Errorf("geocode: failed to parse boundary dataset: %v", err)
+	}
+
+	g := &ReverseGeocoder{}
+	for i, f := range fc.Features {
+		props := f.Properties
+		switch props.Kind {
+		case "country":
+			var multi [][][][2]float64
+			switch f.Geometry.Type {
+			case "Polygon":
+				var rings [][][2]float64
+				if err := json.Unmarshal(f.Geometry.Coordinates, &rings); err != nil {
+					return nil, fmt.Errorf("geocode: feature %d: %v", i, err)
+				}
+				multi = [][][][2]float64{rings}
+			case "MultiPolygon":
+				if err := json.Unmarshal(f.Geometry.Coordinates, &multi); err != nil {
+					return nil, fmt.Errorf("geocode: feature %d: %v", i, err)
+				}
+			default:
+				return nil, fmt.Errorf("geocode: feature %d: unsupported geometry %q", i, f.Geometry.Type)
+			}
+			c := country{code: props.CountryCode, name: props.Name}
+			for _, rings := range multi {
+				if len(rings) == 0 {
+					continue
+				}
+				c.polygons = append(c.polygons, polygon{rings: rings, area: ringArea(rings[0])})
+			}
+			g.countries = append(g.countries, c)
+		case "place":
+			var coords [2]float64
+			if f.Geometry.Type != "Point" {
+				return nil, fmt.Errorf("geocode: feature %d: place must be a Point", i)
+			}
+			if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err != nil {
+				return nil, fmt.Errorf("geocode: feature %d: %v", i, err)
+			}
+			g.places = append(g.places, Place{
+				Name:        props.Name,
+				CountryCode: props.CountryCode,
+				Region:      props.Region,
+				RegionCode:  props.RegionCode,
+				Seat:        props.Seat,
+				Point:       geo.Point{Latitude: coords[1], Longitude: coords[0]},
+			})
+		}
+	}
+	return g, nil
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultGeocoder *ReverseGeocoder
+	defaultErr      error
+)
+
+// Default gömülü veri setinden bir kez yüklenen paylaşımlı örneği döner.
+func Default() (*ReverseGeocoder, error) {
+	defaultOnce.Do(func() {
+		defaultGeocoder, defaultErr = New()
+	})
+	return defaultGeocoder, defaultErr
+}
+
+// Lookup paylaşımlı örnekle Reverse çağırır. Veri seti yüklenemezse
+// ok=false döner; sunucu başlangıçta Default'u çağırıp bu hatayı yakalar.
+func Lookup(p geo.Point) (Region, bool) {
+	g, err := Default()
+	if err != nil {
+		return Region{}, false
+	}
+	return g.Reverse(p)
+}
+
+// Reverse noktanın düştüğü ülkeyi ve (veri setinde yerleşim varsa) idari
+// bölgeyi döner. Örtüşen poligonlarda alanı en küçük olan kazanır; böylece
+// anakara poligonunun içine uzanan adalar doğru ülkeye atanır. Hiçbir
+// poligona düşmeyen noktalar offshoreToleranceKm içindeki en yakın ülkeye
+// atanır, o da yoksa ok=false döner.
+func (g *ReverseGeocoder) Reverse(p geo.Point) (Region, bool) {
+	if p.IsZero() {
+		return Region{}, false
+	}
+
+	var best *country
+	bestArea := math.Inf(1)
+	for i := range g.countries {
+		for _, poly := range g.countries[i].polygons {
+			if poly.area < bestArea && poly.contains(p) {
+				best, bestArea = &g.countries[i], poly.area
+			}
+		}
+	}
+	if best == nil {
+		bestDist := float64(offshoreToleranceKm)
+		for i := range g.countries {
+			for _, poly := range g.countries[i].polygons {
+				if d := poly.distanceKm(p); d <= bestDist {
+					best, bestDist = &g.countries[i], d
+				}
+			}
+		}
+	}
+	if best == nil {
+		return Region{}, false
+	}
+
+	region := Region{CountryCode: best.code, Country: best.name}
+	if place, ok := g.nearestPlace(best.code, p); ok {
+		region.AdminRegion = place.Region
+		region.RegionCode = place.RegionCode
+	}
+	return region, true
+}
+
+// Places veri setindeki yerleşimlerin bir kopyasını döner.
+func (g *ReverseGeocoder) Places() []Place {
+	return append([]Place(nil), g.places...)
+}
+
+func (g *ReverseGeocoder) nearestPlace(countryCode string, p geo.Point) (Place, bool) {
+	var best Place
+	found := false
+	bestDist := math.Inf(1)
+	for _, place := range g.places {
+		if place.CountryCode != countryCode {
+			continue
+		}
+		if d := geo.HaversineKm(p, place.Point); d < bestDist {
+			best, bestDist, found = place, d, true
+		}
+	}
+	return best, found
+}
+
+// contains çift-tek kuralıyla (ray casting) noktanın dış halkanın içinde ve
+// deliklerin dışında olup olmadığını söyler.
+func (poly polygon) contains(p geo.Point) bool {
+	if !ringContains(poly.rings[0], p) {
+		return false
+	}
+	for _, hole := range poly.rings[1:] {
+		if ringContains(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func ringContains(ring [][2]float64, p geo.Point) bool {
+	x, y := p.Longitude, p.Latitude
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distanceKm noktanın poligonun dış halkasına olan yaklaşık uzaklığıdır.
+// Kısa mesafeler için eşdikdörtgen izdüşüm yeterlidir.
+func (poly polygon) distanceKm(p geo.Point) float64 {
+	ring := poly.rings[0]
+	kmPerLat := 111.32
+	kmPerLon := kmPerLat * math.Cos(p.Latitude*math.Pi/180)
+	best := math.Inf(1)
+	for i := 1; i < len(ring); i++ {
+		ax := (ring[i-1][0] - p.Longitude) * kmPerLon
+		ay := (ring[i-1][1] - p.Latitude) * kmPerLat
+		bx := (ring[i][0] - p.Longitude) * kmPerLon
+		by := (ring[i][1] - p.Latitude) * kmPerLat
+		best = math.Min(best, originToSegment(ax, ay, bx, by))
+	}
+	return best
+}
+
+func originToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// ringArea halkanın derece kare cinsinden alanıdır; yalnızca örtüşen
+// poligonları kıyaslamak için kullanılır.
+func ringArea(ring [][2]float64) float64 {
+	sum := 0.0
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		sum += ring[j][0]*ring[i][1] - ring[i][0]*ring[j][1]
+	}
+	return math.Abs(sum) / 2
+}
@@ -0,0 +1,91 @@
+package geocode
+
+import (
+	"testing"
+
+	"trip-plan-service/internal/geo"
+)
+
+// Yunan adaları Türkiye kıyısına birkaç kilometre yakındır; ada üzerindeki
+// noktalar GR, karşı kıyıdakiler TR olmalı.
+func TestReverseAegeanIslands(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		point       geo.Point
+		countryCode string
+		adminRegion string
+	}{
+		{"Rodos", geo.Point{Latitude: 36.434, Longitude: 28.217}, "GR", "Güney Ege"},
+		{"Lindos", geo.Point{Latitude: 36.092, Longitude: 28.088}, "GR", "Güney Ege"},
+		{"Sisam", geo.Point{Latitude: 37.757, Longitude: 26.977}, "GR", "Kuzey Ege"},
+		{"Karlovasi", geo.Point{Latitude: 37.792, Longitude: 26.705}, "GR", "Kuzey Ege"},
+		{"Pisagor", geo.Point{Latitude: 37.691, Longitude: 26.943}, "GR", "Kuzey Ege"},
+		{"Midilli", geo.Point{Latitude: 39.105, Longitude: 26.555}, "GR", "Kuzey Ege"},
+		{"İstanköy", geo.Point{Latitude: 36.893, Longitude: 27.288}, "GR", "Güney Ege"},
+		{"Sakız", geo.Point{Latitude: 38.371, Longitude: 26.136}, "GR", "Kuzey Ege"},
+		{"Meis", geo.Point{Latitude: 36.150, Longitude: 29.590}, "GR", "Güney Ege"},
+
+		{"Marmaris", geo.Point{Latitude: 36.855, Longitude: 28.274}, "TR", "Muğla"},
+		{"Bodrum", geo.Point{Latitude: 37.034, Longitude: 27.430}, "TR", "Muğla"},
+		{"Datça", geo.Point{Latitude: 36.727, Longitude: 27.687}, "TR", "Muğla"},
+		{"Kuşadası", geo.Point{Latitude: 37.858, Longitude: 27.261}, "TR", "Aydın"},
+		{"Güzelçamlı", geo.Point{Latitude: 37.718, Longitude: 27.220}, "TR", "Aydın"},
+		{"Çeşme", geo.Point{Latitude: 38.323, Longitude: 26.303}, "TR", "İzmir"},
+		{"Ayvalık", geo.Point{Latitude: 39.319, Longitude: 26.693}, "TR", "Balıkesir"},
+		{"Kaş", geo.Point{Latitude: 36.201, Longitude: 29.637}, "TR", "Antalya"},
+		{"Gökçeada", geo.Point{Latitude: 40.198, Longitude: 25.907}, "TR", "Çanakkale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, ok := g.Reverse(tt.point)
+			if !ok {
+				t.Fatal("no region")
+			}
+			if region.CountryCode != tt.countryCode || region.AdminRegion != tt.adminRegion {
+				t.Errorf("Reverse = %s/%s, want %s/%s", region.CountryCode, region.AdminRegion, tt.countryCode, tt.adminRegion)
+			}
+		})
+	}
+}
+
+// Komşu ülkelerde de bölge bulunmalı; veri setinin kapsamadığı noktalar
+// etiketlenmemeli.
+func TestReverseOutsideTurkey(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		point      geo.Point
+		regionCode string
+	}{
+		{"Atina", geo.Point{Latitude: 37.976, Longitude: 23.735}, "GR-I"},
+		{"Selanik", geo.Point{Latitude: 40.640, Longitude: 22.944}, "GR-B"},
+		{"Filibe", geo.Point{Latitude: 42.150, Longitude: 24.750}, "BG-16"},
+		{"Batum", geo.Point{Latitude: 41.645, Longitude: 41.640}, "GE-AJ"},
+		{"Tebriz", geo.Point{Latitude: 38.080, Longitude: 46.290}, "IR-03"},
+		{"Erbil", geo.Point{Latitude: 36.190, Longitude: 44.010}, "IQ-AR"},
+		{"Halep", geo.Point{Latitude: 36.200, Longitude: 37.160}, "SY-HL"},
+		{"Limasol", geo.Point{Latitude: 34.680, Longitude: 33.040}, "CY-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, ok := g.Reverse(tt.point)
+			if !ok || region.RegionCode != tt.regionCode || region.AdminRegion == "" {
+				t.Errorf("Reverse = %+v, %v, want region %s", region, ok, tt.regionCode)
+			}
+		})
+	}
+
+	if region, ok := g.Reverse(geo.Point{Latitude: 41.902, Longitude: 12.496}); ok {
+		t.Errorf("Rome tagged as %+v, want no match", region)
+	}
+}
@@ -0,0 +1,106 @@
+// internal/geocode/geocoder.go for trip-plan-service
+package geocode
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+)
+
+// ErrNotFound sorgu hiçbir yerle eşleşmediğinde döner.
+var ErrNotFound = errors.New("geocode: no match")
+
+// Result bir yer adının çözülmüş koordinatıdır.
+type Result struct {
+	Point geo.Point `json:"point"`
+	// Name sağlayıcının eşleştirdiği yerin adıdır (ör. "Selçuk, İzmir").
+	Name string `json:"name"`
+}
+
+// Geocoder serbest metin bir yer adını koordinata çevirir.
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (Result, error)
+}
+
+// WithFallback önce primary'yi dener; hata verirse ya da eşleşme bulamazsa
+// fallback'e sorar. primary nil ise doğrudan fallback kullanılır.
+func WithFallback(primary, fallback Geocoder) Geocoder {
+	if primary == nil {
+		return fallback
+	}
+	return chain{primary: primary, fallback: fallback}
+}
+
+type chain struct {
+	primary  Geocoder
+	fallback Geocoder
+}
+
+func (c chain) Geocode(ctx context.Context, query string) (Result, error) {
+	result, err := c.primary.Geocode(ctx, query)
+	if err == nil {
+		return result, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Printf("⚠️ Geocoder hatası, gömülü yer listesi kullanılıyor: %v", err)
+	}
+	return c.fallback.Geocode(ctx, query)
+}
+
+// Gazetteer gömülü veri setindeki yerleşimleri adla arayan, ağ gerektirmeyen
+// bir Geocoder'dır. "Selçuk", "Selçuk, İzmir" ya da "Ereğli, Zonguldak"
+// gibi sorguları çözer; adı aynı yerleşimlerden il merkezi önceliklidir.
+type Gazetteer struct {
+	places []Place
+}
+
+func NewGazetteer(g *ReverseGeocoder) *Gazetteer {
+	return &Gazetteer{places: g.places}
+}
+
+// Geocode sorgunun virgülden önceki kısmını yerleşim adlarıyla, kalan
+// kısımları (varsa) il adlarıyla karşılaştırır.
+func (z *Gazetteer) Geocode(ctx context.Context, query string) (Result, error) {
+	parts := strings.Split(query, ",")
+	name := geo.NormalizeName(parts[0])
+	if name == "" {
+		return Result{}, ErrNotFound
+	}
+	hints := map[string]bool{}
+	for _, part := range parts[1:] {
+		if hint := geo.NormalizeName(part); hint != "" {
+			hints[hint] = true
+		}
+	}
+
+	var best *Place
+	bestScore := -1
+	for i := range z.places {
+		place := &z.places[i]
+		if geo.NormalizeName(place.Name) != name {
+			continue
+		}
+		score := 0
+		if hints[geo.NormalizeName(place.Region)] {
+			score += 2
+		}
+		if place.Seat {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = place, score
+		}
+	}
+	if best == nil {
+		return Result{}, ErrNotFound
+	}
+
+	display := best.Name
+	if !best.Seat {
+		display += ", " + best.Region
+	}
+	return Result{Point: best.Point, Name: display}, nil
+}
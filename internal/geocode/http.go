@@ -0,0 +1,99 @@
+// internal/geocode/http.go for trip-plan-service
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"trip-plan-service/internal/geo"
+)
+
+// maxResponseBytes sağlayıcı yanıtından okunacak en fazla bayttır.
+const maxResponseBytes = 1 << 20
+
+// HTTPGeocoder Nominatim uyumlu bir arama servisini kullanır:
+// GET {BaseURL}/search?q=...&format=jsonv2&limit=1 isteğine
+// [{"lat": "..", "lon": "..", "display_name": ".."}] biçiminde yanıt
+// bekler. Kendi barındırılan bir Nominatim ya da aynı sözleşmeyi uygulayan
+// herhangi bir servis kullanılabilir.
+type HTTPGeocoder struct {
+	BaseURL string
+	// UserAgent her istekle gönderilir; genel Nominatim sunucuları tanımlayıcı
+	// bir User-Agent olmadan istekleri reddeder.
+	UserAgent string
+	Client    *http.Client
+}
+
+func NewHTTPGeocoder(baseURL, userAgent string, timeout time.Duration) *HTTPGeocoder {
+	return &HTTPGeocoder{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		UserAgent: userAgent,
+		Client:    &http.Client{Timeout: timeout},
+	}
+}
+
+type searchResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+}
+
+func (g *HTTPGeocoder) Geocode(ctx context.Context, query string) (Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return Result{}, ErrNotFound
+	}
+
+	params := url.Values{"q": {query}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("geocode: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if g.UserAgent != "" {
+		req.Header.Set("User-Agent", g.UserAgent)
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("geocode: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("geocode: provider returned %s", resp.Status)
+	}
+
+	var results []searchResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&results); err != nil {
+		return Result{}, fmt.Errorf("geocode: invalid provider response: %v", err)
+	}
+	if len(results) == 0 {
+		return Result{}, ErrNotFound
+	}
+
+	lat, errLat := strconv.ParseFloat(results[0].Lat, 64)
+	lon, errLon := strconv.ParseFloat(results[0].Lon, 64)
+	if errLat != nil || errLon != nil || !validCoordinate(lat, 90) || !validCoordinate(lon, 180) {
+		return Result{}, fmt.Errorf("geocode: provider returned invalid coordinates %q, %q", results[0].Lat, results[0].Lon)
+	}
+	return Result{
+		Point: geo.Point{Latitude: lat, Longitude: lon},
+		Name:  results[0].DisplayName,
+	}, nil
+}
+
+// validCoordinate değerin sonlu ve [-limit, limit] aralığında olduğunu
+// kontrol eder. ParseFloat "NaN" ve "Inf" metinlerini hatasız okur; NaN her
+// karşılaştırmada false döndüğünden aralık kontrolünü tek başına geçer.
+func validCoordinate(value, limit float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0) && value >= -limit && value <= limit
+}
@@ -0,0 +1,130 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"trip-plan-service/internal/geo"
+)
+
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *HTTPGeocoder {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewHTTPGeocoder(server.URL+"/", "trip-plan-service-test", time.Second)
+}
+
+func TestHTTPGeocoder(t *testing.T) {
+	var got *http.Request
+	g := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`[{"lat": "37.9395", "lon": "27.3417", "display_name": "Selçuk, İzmir, Türkiye"}]`))
+	})
+
+	result, err := g.Geocode(context.Background(), " Selçuk ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Point != (geo.Point{Latitude: 37.9395, Longitude: 27.3417}) || result.Name != "Selçuk, İzmir, Türkiye" {
+		t.Errorf("result = %+v", result)
+	}
+	if got.URL.Path != "/search" || got.URL.Query().Get("q") != "Selçuk" || got.URL.Query().Get("limit") != "1" {
+		t.Errorf("request = %s", got.URL)
+	}
+	if got.Header.Get("User-Agent") != "trip-plan-service-test" {
+		t.Errorf("User-Agent = %q", got.Header.Get("User-Agent"))
+	}
+}
+
+func TestHTTPGeocoderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		notFound bool
+		contains string
+	}{
+		{name: "non_200", status: http.StatusTooManyRequests, body: `[]`, contains: "429"},
+		{name: "server_error", status: http.StatusInternalServerError, body: `oops`, contains: "500"},
+		{name: "empty_result", status: http.StatusOK, body: `[]`, notFound: true},
+		{name: "invalid_json", status: http.StatusOK, body: `{"error": "bad"}`, contains: "invalid provider response"},
+		{name: "unparsable_coordinates", status: http.StatusOK, body: `[{"lat": "north", "lon": "27.1"}]`, contains: "invalid coordinates"},
+		{name: "out_of_range_latitude", status: http.StatusOK, body: `[{"lat": "91", "lon": "27.1"}]`, contains: "invalid coordinates"},
+		{name: "out_of_range_longitude", status: http.StatusOK, body: `[{"lat": "38.4", "lon": "-180.5"}]`, contains: "invalid coordinates"},
+		{name: "nan_latitude", status: http.StatusOK, body: `[{"lat": "NaN", "lon": "27.1"}]`, contains: "invalid coordinates"},
+		{name: "nan_longitude", status: http.StatusOK, body: `[{"lat": "38.4", "lon": "nan"}]`, contains: "invalid coordinates"},
+		{name: "infinite_longitude", status: http.StatusOK, body: `[{"lat": "38.4", "lon": "-Inf"}]`, contains: "invalid coordinates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := g.Geocode(context.Background(), "İzmir")
+			if err == nil {
+				t.Fatal("Geocode succeeded, want error")
+			}
+			if errors.Is(err, ErrNotFound) != tt.notFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v (%v)", !tt.notFound, tt.notFound, err)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.contains)
+			}
+		})
+	}
+}
+
+// Sağlayıcı hata verirse ya da eşleşme bulamazsa gömülü yer listesi
+// kullanılmalı.
+func TestHTTPGeocoderFallsBackToGazetteer(t *testing.T) {
+	boundaries, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gazetteer := NewGazetteer(boundaries)
+
+	tests := map[string]http.HandlerFunc{
+		"provider_down": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		"no_match": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[]`))
+		},
+		"bad_coordinates": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"lat": "", "lon": ""}]`))
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := WithFallback(newTestProvider(t, handler), gazetteer)
+
+			result, err := g.Geocode(context.Background(), "Selçuk, İzmir")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Name != "Selçuk, İzmir" || result.Point.IsZero() {
+				t.Errorf("result = %+v, want Selçuk from the gazetteer", result)
+			}
+
+			if _, err := g.Geocode(context.Background(), "Atlantis"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("unknown place: err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+
+	// Sağlayıcı yanıt verirse yer listesine sorulmaz
+	g := WithFallback(newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"lat": "1.5", "lon": "2.5", "display_name": "Sağlayıcı"}]`))
+	}), gazetteer)
+	if result, err := g.Geocode(context.Background(), "Selçuk, İzmir"); err != nil || result.Name != "Sağlayıcı" {
+		t.Errorf("result = %+v, %v, want the provider's answer", result, err)
+	}
+}
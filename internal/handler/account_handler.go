@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminOnly isteğin "Authorization: Bearer <token>" ile yapılandırılmış
+// admin token'ını taşımasını ister. Token boşsa admin uçları kapalıdır.
+func AdminOnly(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "admin API is disabled"})
+		}
+
+		given := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid admin token"})
+		}
+		return c.Next()
+	}
+}
+
+// ExportAccountHandler kullanıcıya ait tüm kayıtları JSON arşivi olarak gönderir.
+func (h *TripHandler) ExportAccountHandler(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+
+	log.Printf("🔐 Hesap verisi export: %s", userID)
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	archive, err := tripService.ExportAccount(context.Background(), userID)
+	if err != nil {
+		log.Printf("❌ Hesap export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export account data"})
+	}
+
+	body, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		log.Printf("❌ Hesap export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export account data"})
+	}
+
+	log.Printf("✅ Hesap verisi export edildi: %s, %d trip", userID, len(archive.Trips))
+	return sendAttachment(c, fiber.MIMEApplicationJSON, fmt.Sprintf("account-%s.json", safeFilename(userID)), body)
+}
+
+// DeleteAccountHandler kullanıcıya ait tüm kayıtları siler ve doğrulama
+// raporunu döner.
+func (h *TripHandler) DeleteAccountHandler(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+
+	log.Printf("🔐 Hesap verisi siliniyor: %s", userID)
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	report, err := tripService.DeleteAccount(context.Background(), userID)
+	if err != nil {
+		log.Printf("❌ Hesap silme hatası: %v", err)
+		if report != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":  "account deletion could not be verified and was rolled back",
+				"report": report,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete account data"})
+	}
+
+	log.Printf("✅ Hesap verisi silindi: %s %+v", userID, report.Deleted)
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// safeFilename dosya adında sorun çıkaracak karakterleri "_" yapar.
+func safeFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, name)
+}
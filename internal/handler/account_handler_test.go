@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"trip-plan-service/internal/dbtest"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Doğrulanamayan silme geri alınmalı ve rapor 500 ile dönmeli.
+func TestDeleteAccountReportsFailedVerification(t *testing.T) {
+	fake := &dbtest.DB{Rows: map[string]dbtest.RowsFunc{
+		"ListLocationIDsByUserID":  func([]driver.Value) [][]driver.Value { return [][]driver.Value{{int64(10)}} },
+		"CountTripsByUserID":       dbtest.Count(1),
+		"CountOrphanedLocations":   dbtest.Count(0),
+		"GetCalendarTokenByUserID": dbtest.NoRows,
+	}}
+	app := fiber.New()
+	app.Delete("/account/:user_id", NewTripHandler(fake.Open(), nil, nil).DeleteAccountHandler)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/account/user-1", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+
+	var body struct {
+		Error  string                         `json:"error"`
+		Report *service.AccountDeletionReport `json:"report"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Report == nil || body.Report.Verification.Verified || body.Report.Verification.RemainingTrips != 1 {
+		t.Errorf("report = %+v, want the failed verification", body.Report)
+	}
+	if fake.Commits() != 0 || fake.Rollbacks() != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want a rollback only", fake.Commits(), fake.Rollbacks())
+	}
+}
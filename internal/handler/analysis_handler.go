@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"trip-plan-service/internal/analysis"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyzeTripHandler kayıtlı bir trip'i puanlar ve sorunlu günleri döner.
+// Yol süreleri ?mode= ile verilen ulaşım türüne göre hesaplanır.
+func (h *TripHandler) AnalyzeTripHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	if err := h.attachDistances(trip, c.Query("mode")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := analysis.DefaultLimits.Analyze(trip)
+	if err != nil {
+		if errors.Is(err, analysis.ErrTooManyDays) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("❌ Plan analizi hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to analyse trip"})
+	}
+	log.Printf("🩺 Plan analizi: trip=%d, puan=%d, %d uyarı", trip.Trip.ID, result.Score, len(result.Warnings))
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
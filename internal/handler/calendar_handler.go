@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"trip-plan-service/internal/export"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+func (h *TripHandler) ExportICSHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	log.Printf("📅 ICS export: %d", trip.Trip.ID)
+
+	body := export.ICS(trip.Trip.Name, *trip)
+	return sendAttachment(c, calendarContentType, fmt.Sprintf("trip-%d.ics", trip.Trip.ID), body)
+}
+
+// CreateCalendarFeedHandler kullanıcı için takvim feed linkini döner.
+// rotate=true eski linki geçersiz kılıp yenisini üretir.
+func (h *TripHandler) CreateCalendarFeedHandler(c *fiber.Ctx) error {
+	userID := c.Query("user_id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "user_id is required"})
+	}
+	rotate := c.QueryBool("rotate", false)
+
+	log.Printf("📅 Calendar feed: user=%s rotate=%t", userID, rotate)
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	token, err := tripService.CalendarToken(context.Background(), userID, rotate)
+	if err != nil {
+		log.Printf("❌ Calendar token hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create calendar feed"})
+	}
+
+	baseURL := h.PublicBaseURL
+	if baseURL == "" {
+		baseURL = c.BaseURL()
+	}
+	feedURL := baseURL + "/api/v1/trip/calendar/" + token + "/feed.ics"
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"token":      token,
+		"url":        feedURL,
+		"webcal_url": "webcal://" + strings.TrimPrefix(strings.TrimPrefix(feedURL, "https://"), "http://"),
+	})
+}
+
+// CalendarFeedHandler token sahibinin yaklaşan triplerini her istekte
+// veritabanından yeniden üretir.
+func (h *TripHandler) CalendarFeedHandler(c *fiber.Ctx) error {
+	tripService := service.NewTripService(nil, h.DB, nil)
+
+	userID, err := tripService.UserIDForCalendarToken(context.Background(), c.Params("token"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "calendar feed not found"})
+		}
+		log.Printf("❌ Calendar token hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get calendar feed"})
+	}
+
+	trips, err := tripService.GetUpcomingTrips(context.Background(), userID)
+	if err != nil {
+		log.Printf("❌ Calendar feed hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get calendar feed"})
+	}
+
+	log.Printf("📅 Calendar feed: user=%s trips=%d", userID, len(trips))
+
+	c.Set(fiber.HeaderContentType, calendarContentType)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	return c.Status(fiber.StatusOK).Send(export.ICS("Seyahatlerim", trips...))
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"trip-plan-service/internal/models"
+	"trip-plan-service/internal/validator"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+// go test ./internal/handler -run TestConvertTripOptionsGolden -update
+var update = flag.Bool("update", false, "update golden files")
+
+func TestConvertTripOptionsGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *proto.TripOptionsResponse
+		reports  []validator.OptionReport
+	}{
+		{
+			name:     "nil_response",
+			response: nil,
+		},
+		{
+			name: "two_options",
+			response: &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{
+				{
+					Theme:       "Tarih",
+					Description: "Antik kentler",
+					Trip: &proto.Trip{
+						UserId:        "user-1",
+						Name:          "Ege turu",
+						Description:   "Yaz tatili",
+						StartPosition: "İzmir",
+						EndPosition:   "Bodrum",
+						StartDate:     "2026-06-01",
+						EndDate:       "2026-06-02",
+						TotalDays:     2,
+					},
+					DailyPlan: []*proto.DailyPlan{
+						{Day: 1, Date: "2026-06-01", Location: &proto.Location{
+							Name:      "Efes Antik Kenti",
+							Address:   "Selçuk, İzmir",
+							SiteUrl:   "https://muze.gov.tr/efes",
+							Latitude:  37.9395,
+							Longitude: 27.3417,
+							Notes:     "Sabah erken gidin",
+						}},
+						// Konumu olmayan gün kaydedilecek bir durak üretmez
+						{Day: 1, Date: "2026-06-01"},
+						{Day: 2, Date: "2026-06-02", Location: &proto.Location{
+							Name:      "Bodrum Kalesi",
+							Latitude:  37.0317,
+							Longitude: 27.4286,
+						}},
+					},
+				},
+				{
+					Theme: "Sahil",
+					// Trip bilgisi olmayan seçenek
+					DailyPlan: []*proto.DailyPlan{
+						{Day: 1, Location: &proto.Location{Name: "Çeşme Limanı", Latitude: 38.3236, Longitude: 26.3031}},
+					},
+				},
+			}},
+			reports: []validator.OptionReport{
+				{
+					Warnings:  []string{"day 3 (2026-06-03) is outside the trip dates and was dropped"},
+					Waypoints: map[int]int{2: 1},
+				},
+				{},
+			},
+		},
+		{
+			name: "missing_reports",
+			response: &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{
+				{Theme: "Doğa", DailyPlan: []*proto.DailyPlan{}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(convertTripOptionsToModel(tt.response, tt.reports), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "convert", tt.name+".golden.json")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+// TestPreviewOptionIsASaveBody önizleme seçeneğinin JSON'u olduğu gibi
+// /save gövdesi olarak çözülebilmeli.
+func TestPreviewOptionIsASaveBody(t *testing.T) {
+	response := convertTripOptionsToModel(&proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{
+		Trip: &proto.Trip{Name: "Ege turu", StartDate: "2026-06-01", EndDate: "2026-06-01"},
+		DailyPlan: []*proto.DailyPlan{
+			{Day: 1, Date: "2026-06-01", Location: &proto.Location{Name: "Efes", Latitude: 37.94, Longitude: 27.34, Notes: "not"}},
+		},
+	}}}, []validator.OptionReport{{Waypoints: map[int]int{0: 1}}})
+
+	body, err := json.Marshal(response.TripOptions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var save models.TripWithLocations
+	if err := json.Unmarshal(body, &save); err != nil {
+		t.Fatal(err)
+	}
+
+	option := response.TripOptions[0].TripWithLocations
+	if !reflect.DeepEqual(save.Trip, option.Trip) || !reflect.DeepEqual(save.Locations, option.Locations) {
+		t.Errorf("save body = %+v, want %+v", save, option)
+	}
+}
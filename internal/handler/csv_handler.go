@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"trip-plan-service/internal/export"
+	"trip-plan-service/internal/importer"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *TripHandler) ExportCSVHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	log.Printf("📄 CSV export: %d", trip.Trip.ID)
+
+	body, err := export.CSV(trip)
+	if err != nil {
+		log.Printf("❌ CSV export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export trip"})
+	}
+
+	return sendAttachment(c, "text/csv; charset=utf-8", fmt.Sprintf("trip-%d.csv", trip.Trip.ID), body)
+}
+
+// ImportLocationsCSVHandler multipart "file" alanındaki CSV ile trip'in tüm
+// lokasyonlarını değiştirir. İsteğe bağlı "mapping" form alanı alan adından
+// sütun başlığına JSON eşlemedir ({"name": "Mekan"}). Herhangi bir satır
+// hatalıysa hiçbir şey yazılmaz ve hatalar satır satır 422 ile döner.
+// ?dry_run=true ile kaydetmeden ayrıştırılmış lokasyonlar döner.
+func (h *TripHandler) ImportLocationsCSVHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read file"})
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read file"})
+	}
+
+	opts := importer.CSVOptions{MaxDay: trip.Trip.TotalDays}
+	if raw := c.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "mapping must be a JSON object of field to column name"})
+		}
+	}
+
+	log.Printf("📥 CSV import: trip=%d %s (%d byte)", trip.Trip.ID, fileHeader.Filename, len(data))
+
+	locations, rowErrors, err := importer.ParseCSV(data, opts)
+	if err != nil {
+		log.Printf("❌ CSV parse hatası: %v", err)
+		var headerErr *importer.CSVHeaderError
+		if errors.As(err, &headerErr) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "invalid CSV header",
+				"details": headerErr.Problems,
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "failed to parse file",
+			"details": err.Error(),
+		})
+	}
+	if len(rowErrors) > 0 {
+		log.Printf("❌ CSV import: %d hatalı satır", len(rowErrors))
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":  "CSV contains invalid rows, nothing was imported",
+			"errors": rowErrors,
+		})
+	}
+
+	if c.QueryBool("dry_run") {
+		log.Printf("✅ Dry run: %d lokasyon ayrıştırıldı", len(locations))
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"dry_run":   true,
+			"trip_id":   trip.Trip.ID,
+			"replaced":  len(trip.Locations),
+			"locations": locations,
+		})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	removed, err := tripService.ReplaceTripLocations(context.Background(), int32(trip.Trip.ID), locations)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "trip not found"})
+		}
+		log.Printf("❌ CSV import kayıt hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to import locations"})
+	}
+
+	log.Printf("✅ CSV import: trip=%d, %d lokasyon yazıldı, %d silindi", trip.Trip.ID, len(locations), removed)
+
+	updated, err := tripService.GetTripByID(context.Background(), int32(trip.Trip.ID))
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"dry_run":   false,
+		"trip_id":   trip.Trip.ID,
+		"replaced":  removed,
+		"locations": updated.Locations,
+	})
+}
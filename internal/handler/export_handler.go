@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"trip-plan-service/internal/export"
+	"trip-plan-service/internal/models"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidTripID = errors.New("invalid trip id")
+
+// loadTrip :id parametresindeki trip'i lokasyonlarıyla birlikte yükler.
+func (h *TripHandler) loadTrip(c *fiber.Ctx) (*models.TripWithLocations, error) {
+	tripID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, errInvalidTripID
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	return tripService.GetTripByID(context.Background(), int32(tripID))
+}
+
+// tripLoadErrorResponse loadTrip hatasını uygun HTTP yanıtına çevirir.
+func tripLoadErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errInvalidTripID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid trip id"})
+	case errors.Is(err, sql.ErrNoRows):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "trip not found"})
+	}
+	log.Printf("❌ Get trip by ID hatası: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get trip"})
+}
+
+// sendAttachment içeriği indirilebilir dosya olarak gönderir.
+func sendAttachment(c *fiber.Ctx, contentType, filename string, body []byte) error {
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+func (h *TripHandler) ExportGPXHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	log.Printf("🗺️ GPX export: %d", trip.Trip.ID)
+
+	body, err := export.GPX(trip)
+	if err != nil {
+		log.Printf("❌ GPX export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export trip"})
+	}
+
+	return sendAttachment(c, "application/gpx+xml", fmt.Sprintf("trip-%d.gpx", trip.Trip.ID), body)
+}
+
+// GeoJSONHandler harita arayüzü için trip'i GeoJSON olarak döner; dosya
+// indirmesi değil doğrudan yanıt gövdesidir.
+func (h *TripHandler) GeoJSONHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	log.Printf("🗺️ GeoJSON: %d", trip.Trip.ID)
+
+	body, err := export.GeoJSON(trip)
+	if err != nil {
+		log.Printf("❌ GeoJSON export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export trip"})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/geo+json")
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// ExportBundleHandler trip'in tüm dışa aktarma biçimlerini çevrimdışı kullanım
+// için tek ZIP dosyasında gönderir.
+func (h *TripHandler) ExportBundleHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	log.Printf("📦 Bundle export: %d", trip.Trip.ID)
+
+	body, err := export.Bundle(trip)
+	if err != nil {
+		log.Printf("❌ Bundle export hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to export trip"})
+	}
+
+	return sendAttachment(c, "application/zip", fmt.Sprintf("trip-%d.zip", trip.Trip.ID), body)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/importer"
+	"trip-plan-service/internal/models"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportTripHandler multipart "file" alanındaki GPX/KML dosyasından trip
+// oluşturur. Form alanları (user_id, name, description, start_date, end_date,
+// start_position, end_position) dosyadan okunan değerleri ezer.
+// ?dry_run=true ile kaydetmeden ayrıştırılmış trip döner.
+func (h *TripHandler) ImportTripHandler(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read file"})
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read file"})
+	}
+
+	log.Printf("📥 Import: %s (%d byte)", fileHeader.Filename, len(data))
+
+	trip, err := importer.Parse(fileHeader.Filename, data)
+	if err != nil {
+		log.Printf("❌ Import parse hatası: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "failed to parse file",
+			"details": err.Error(),
+		})
+	}
+
+	applyImportForm(c, trip, fileHeader.Filename)
+
+	// /save ile aynı kontroller; dosyadan ya da formdan gelen başlangıç ve
+	// bitiş metinleri de koordinata çevrilir
+	if err := checkPositions(&trip.Trip); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.resolvePositions(context.Background(), &trip.Trip, map[string]*geo.Point{})
+
+	dryRun := c.QueryBool("dry_run")
+	if dryRun {
+		log.Printf("✅ Dry run: %d lokasyon ayrıştırıldı", len(trip.Locations))
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"dry_run": true, "trip": trip})
+	}
+
+	if problem := validateImportedTrip(trip); problem != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": problem})
+	}
+
+	// /save ile aynı kayıt yolu
+	tripService := service.NewTripService(&trip.Trip, h.DB, trip.Locations)
+	if err := tripService.SaveTripWLocations(context.Background()); err != nil {
+		log.Printf("❌ Import kayıt hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save trip"})
+	}
+
+	log.Printf("✅ Import edilen trip kaydedildi: %d", trip.Trip.ID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"dry_run": false, "trip": trip})
+}
+
+func applyImportForm(c *fiber.Ctx, trip *models.TripWithLocations, filename string) {
+	override := func(field *string, key string) {
+		if value := strings.TrimSpace(c.FormValue(key)); value != "" {
+			*field = value
+		}
+	}
+
+	override(&trip.Trip.UserID, "user_id")
+	override(&trip.Trip.Name, "name")
+	override(&trip.Trip.Description, "description")
+	override(&trip.Trip.StartDate, "start_date")
+	override(&trip.Trip.EndDate, "end_date")
+	override(&trip.Trip.StartPosition, "start_position")
+	override(&trip.Trip.EndPosition, "end_position")
+
+	if trip.Trip.Name == "" {
+		trip.Trip.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if trip.Trip.StartPosition == "" {
+		trip.Trip.StartPosition = trip.Locations[0].Name
+	}
+	if trip.Trip.EndPosition == "" {
+		trip.Trip.EndPosition = trip.Locations[len(trip.Locations)-1].Name
+	}
+
+	if start, err := time.Parse("2006-01-02", trip.Trip.StartDate); err == nil {
+		for i := range trip.Locations {
+			if trip.Locations[i].Day > 0 {
+				trip.Locations[i].Date = start.AddDate(0, 0, trip.Locations[i].Day-1).Format("2006-01-02")
+			}
+		}
+	}
+}
+
+func validateImportedTrip(trip *models.TripWithLocations) string {
+	if trip.Trip.UserID == "" {
+		return "user_id is required"
+	}
+
+	start, err := time.Parse("2006-01-02", trip.Trip.StartDate)
+	if err != nil {
+		return "start_date is required (YYYY-MM-DD)"
+	}
+	end, err := time.Parse("2006-01-02", trip.Trip.EndDate)
+	if err != nil {
+		return "end_date is required (YYYY-MM-DD)"
+	}
+	if end.Before(start) {
+		return "end_date must not be before start_date"
+	}
+
+	totalDays := int(end.Sub(start).Hours()/24) + 1
+	for _, loc := range trip.Locations {
+		if loc.Day > totalDays {
+			return fmt.Sprintf("file has stops on day %d but the trip only spans %d days", loc.Day, totalDays)
+		}
+	}
+	return ""
+}
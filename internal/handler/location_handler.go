@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+const (
+	defaultLocationPageSize = 20
+	maxLocationPageSize     = 100
+)
+
+type mergeLocationsRequest struct {
+	CanonicalID  int   `json:"canonical_id"`
+	DuplicateIDs []int `json:"duplicate_ids"`
+}
+
+// ListLocationsHandler paylaşılan lokasyon kataloğunda ada göre arama yapar.
+// ?q= ile isim filtresi, ?limit= ve ?offset= ile sayfalama yapılır.
+func (h *TripHandler) ListLocationsHandler(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultLocationPageSize)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > maxLocationPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit must be between 1 and 100"})
+	}
+	if offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "offset must not be negative"})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	page, err := tripService.ListLocationCatalogue(context.Background(), strings.TrimSpace(c.Query("q")), limit, offset)
+	if err != nil {
+		log.Printf("❌ Lokasyon kataloğu hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list locations"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// MergeLocationsHandler kopya lokasyonları kanonik lokasyonla birleştirir:
+// trip bağlantıları kanonik satıra taşınır ve kopyalar silinir.
+func (h *TripHandler) MergeLocationsHandler(c *fiber.Ctx) error {
+	var req mergeLocationsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	// Tekrarlanan ID'leri at
+	seen := map[int]bool{}
+	var duplicates []int
+	for _, id := range req.DuplicateIDs {
+		if id == req.CanonicalID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "canonical_id must not be in duplicate_ids"})
+		}
+		if !seen[id] {
+			seen[id] = true
+			duplicates = append(duplicates, id)
+		}
+	}
+	if req.CanonicalID < 1 || len(duplicates) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "canonical_id and duplicate_ids are required"})
+	}
+
+	log.Printf("🔀 Lokasyon birleştirme: %v -> %d", duplicates, req.CanonicalID)
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	report, err := tripService.MergeLocations(context.Background(), req.CanonicalID, duplicates)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownLocation) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "one or more locations do not exist, nothing was merged"})
+		}
+		log.Printf("❌ Lokasyon birleştirme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to merge locations"})
+	}
+
+	log.Printf("✅ Lokasyonlar birleştirildi: %+v", report)
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// AddToCatalogueHandler lokasyonu kalıcı katalog kaydı yapar; hiçbir trip'te
+// kullanılmasa da sahipsiz lokasyon temizliğinde silinmez.
+func (h *TripHandler) AddToCatalogueHandler(c *fiber.Ctx) error {
+	return h.setLocationInCatalogue(c, true)
+}
+
+// RemoveFromCatalogueHandler katalog kaydını kaldırır; lokasyon artık hiçbir
+// trip'te kullanılmıyorsa bir sonraki temizlikte silinir.
+func (h *TripHandler) RemoveFromCatalogueHandler(c *fiber.Ctx) error {
+	return h.setLocationInCatalogue(c, false)
+}
+
+func (h *TripHandler) setLocationInCatalogue(c *fiber.Ctx, inCatalogue bool) error {
+	locationID, err := strconv.Atoi(c.Params("id"))
+	if err != nil || locationID < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid location id"})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	if err := tripService.SetLocationInCatalogue(context.Background(), locationID, inCatalogue); err != nil {
+		if errors.Is(err, service.ErrUnknownLocation) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "location not found"})
+		}
+		log.Printf("❌ Katalog güncelleme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update location"})
+	}
+
+	log.Printf("📚 Lokasyon %d: in_catalogue=%v", locationID, inCatalogue)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"id": locationID, "in_catalogue": inCatalogue})
+}
+
+// DebugVarsHandler expvar metriklerini (ör. location_gc) JSON olarak döner.
+func (h *TripHandler) DebugVarsHandler(c *fiber.Ctx) error {
+	return adaptor.HTTPHandler(expvar.Handler())(c)
+}
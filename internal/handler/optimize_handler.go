@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+
+	"trip-plan-service/internal/models"
+	"trip-plan-service/internal/optimizer"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OptimizeTripHandler trip'in duraklarını toplam kuş uçuşu mesafeyi
+// azaltacak şekilde yeniden sıralar. ?per_day=true ile duraklar sadece kendi
+// günleri içinde yer değiştirir; bu olmadan duraklar birden fazla güne
+// dağılmışsa 422 döner. Varsayılan olarak sadece önizleme döner;
+// ?apply=true ile yeni pozisyonlar trip_locations'a yazılır.
+func (h *TripHandler) OptimizeTripHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	mode := c.Query("mode")
+	before, err := h.travelProfiles().Compute(trip.Locations, mode)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	perDay := c.QueryBool("per_day")
+	result, err := optimizer.Optimize(trip.Locations, optimizer.Options{PerDay: perDay})
+	if err != nil {
+		if errors.Is(err, optimizer.ErrMixedDays) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("❌ Rota optimizasyonu hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to optimise route"})
+	}
+
+	reordered := make([]models.Location, len(result.Order))
+	order := make([]int, len(result.Order))
+	changed := false
+	for i, index := range result.Order {
+		reordered[i] = trip.Locations[index]
+		order[i] = trip.Locations[index].ID
+		changed = changed || index != i
+	}
+
+	after, err := h.travelProfiles().Compute(reordered, mode)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	log.Printf("🧭 Rota optimizasyonu: trip=%d, %.1f km -> %.1f km", trip.Trip.ID, result.BeforeKm, result.AfterKm)
+
+	apply := c.QueryBool("apply")
+	if apply && changed {
+		tripService := service.NewTripService(nil, h.DB, nil)
+		if err := tripService.ReorderTripLocations(context.Background(), int32(trip.Trip.ID), order); err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "trip not found"})
+			case errors.Is(err, service.ErrLocationOrderMismatch):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "trip locations changed during optimisation, try again"})
+			}
+			log.Printf("❌ Optimizasyon kayıt hatası: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to apply new order"})
+		}
+		log.Printf("✅ Yeni sıra kaydedildi: trip=%d", trip.Trip.ID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"trip_id":   trip.Trip.ID,
+		"per_day":   perDay,
+		"applied":   apply && changed,
+		"changed":   changed,
+		"before":    before,
+		"after":     after,
+		"saved_km":  math.Round((result.BeforeKm-result.AfterKm)*10) / 10,
+		"order":     order,
+		"locations": reordered,
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"trip-plan-service/internal/dbtest"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Sıra zaten en kısa ise apply=true olsa da hiçbir şey yazılmaz ve yanıt
+// applied=false dönmeli.
+func TestOptimizeDoesNotApplyUnchangedOrder(t *testing.T) {
+	created := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	fake := &dbtest.DB{Rows: map[string]dbtest.RowsFunc{
+		"GetTripByID": func([]driver.Value) [][]driver.Value {
+			return [][]driver.Value{{int64(1), "user-1", "Ege turu", "", created, created, "İzmir", "Bodrum", created, created,
+				nil, nil, nil, nil}}
+		},
+		"GetTripLocations": func([]driver.Value) [][]driver.Value {
+			return [][]driver.Value{
+				{int64(10), "Efes", nil, nil, nil, "37.939500", "27.341700", created, "TR", "İzmir", int64(1), int64(1), false, nil},
+				{int64(12), "Bodrum Kalesi", nil, nil, nil, "37.031700", "27.428600", created, "TR", "Muğla", int64(2), int64(1), false, nil},
+			}
+		},
+		"GetTripWaypoints": dbtest.NoRows,
+	}}
+	app := fiber.New()
+	app.Post("/trip/:id/optimize", NewTripHandler(fake.Open(), nil, nil).OptimizeTripHandler)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/trip/1/optimize?apply=true", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var body struct {
+		Applied bool `json:"applied"`
+		Changed bool `json:"changed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Applied || body.Changed {
+		t.Errorf("applied = %t, changed = %t, want both false", body.Applied, body.Changed)
+	}
+	if n := fake.Called("ReorderTripLocations"); n != 0 {
+		t.Errorf("ReorderTripLocations called %d times", n)
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/geocode"
+	"trip-plan-service/internal/models"
+)
+
+// stubGeocoder her sorguyu sabit bir listeden çözer ve kaç kez sorulduğunu sayar.
+type stubGeocoder struct {
+	points map[string]geo.Point
+	calls  int
+}
+
+func (s *stubGeocoder) Geocode(ctx context.Context, query string) (geocode.Result, error) {
+	s.calls++
+	point, ok := s.points[query]
+	if !ok {
+		return geocode.Result{}, geocode.ErrNotFound
+	}
+	return geocode.Result{Point: point, Name: query}, nil
+}
+
+var (
+	izmir   = geo.Point{Latitude: 38.4237, Longitude: 27.1428}
+	bodrum  = geo.Point{Latitude: 37.0344, Longitude: 27.4305}
+	antalya = geo.Point{Latitude: 36.8969, Longitude: 30.7133}
+)
+
+func TestResolvePositions(t *testing.T) {
+	tests := []struct {
+		name      string
+		trip      models.Trip
+		wantStart *geo.Point
+		wantQuery string
+		wantCalls int
+	}{
+		{
+			name:      "resolves_missing_point",
+			trip:      models.Trip{StartPosition: " İzmir "},
+			wantStart: &izmir,
+			wantQuery: "İzmir",
+			wantCalls: 1,
+		},
+		{
+			name:      "keeps_point_from_same_text",
+			trip:      models.Trip{StartPosition: "İzmir", StartPoint: &bodrum, StartPointQuery: "İzmir"},
+			wantStart: &bodrum,
+			wantQuery: "İzmir",
+		},
+		{
+			name:      "keeps_point_given_by_client",
+			trip:      models.Trip{StartPosition: "İzmir", StartPoint: &bodrum},
+			wantStart: &bodrum,
+		},
+		{
+			// Önizlemeden sonra start_position değiştirildi
+			name:      "re_resolves_edited_text",
+			trip:      models.Trip{StartPosition: "Antalya", StartPoint: &izmir, StartPointQuery: "İzmir"},
+			wantStart: &antalya,
+			wantQuery: "Antalya",
+			wantCalls: 1,
+		},
+		{
+			name:      "drops_point_when_edited_text_is_unknown",
+			trip:      models.Trip{StartPosition: "Atlantis", StartPoint: &izmir, StartPointQuery: "İzmir"},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geocoder := &stubGeocoder{points: map[string]geo.Point{"İzmir": izmir, "Antalya": antalya}}
+			h := &TripHandler{Geocoder: geocoder}
+			trip := tt.trip
+
+			h.resolvePositions(context.Background(), &trip, map[string]*geo.Point{})
+
+			if (trip.StartPoint == nil) != (tt.wantStart == nil) || (trip.StartPoint != nil && *trip.StartPoint != *tt.wantStart) {
+				t.Errorf("StartPoint = %v, want %v", trip.StartPoint, tt.wantStart)
+			}
+			if trip.StartPointQuery != tt.wantQuery {
+				t.Errorf("StartPointQuery = %q, want %q", trip.StartPointQuery, tt.wantQuery)
+			}
+			if geocoder.calls != tt.wantCalls {
+				t.Errorf("geocoder called %d times, want %d", geocoder.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// Geocoder yoksa da eski metnin koordinatı kaydedilmemeli.
+func TestResolvePositionsDropsStalePointWithoutGeocoder(t *testing.T) {
+	trip := models.Trip{
+		EndPosition: "Antalya", EndPoint: &izmir, EndPointQuery: "İzmir",
+		Waypoints: []models.Waypoint{{Name: "Fethiye", Point: &bodrum, PointQuery: "Bodrum"}},
+	}
+	(&TripHandler{}).resolvePositions(context.Background(), &trip, map[string]*geo.Point{})
+
+	if trip.EndPoint != nil || trip.EndPointQuery != "" {
+		t.Errorf("end point = %v (%q), want none", trip.EndPoint, trip.EndPointQuery)
+	}
+	if trip.Waypoints[0].Point != nil || trip.Waypoints[0].PointQuery != "" {
+		t.Errorf("waypoint point = %v (%q), want none", trip.Waypoints[0].Point, trip.Waypoints[0].PointQuery)
+	}
+}
+
+func TestCheckPositionsRejectsOutOfRangePoints(t *testing.T) {
+	tests := map[string]models.Trip{
+		"start_latitude":    {StartPoint: &geo.Point{Latitude: 1000, Longitude: 27}},
+		"end_longitude":     {EndPoint: &geo.Point{Latitude: 38, Longitude: -181}},
+		"waypoint_latitude": {Waypoints: []models.Waypoint{{Name: "Çeşme", Point: &geo.Point{Latitude: -90.5}}}},
+		"waypoint_no_name":  {Waypoints: []models.Waypoint{{Name: "  "}}},
+	}
+	for name, trip := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := checkPositions(&trip); err == nil {
+				t.Error("checkPositions succeeded, want error")
+			}
+		})
+	}
+
+	edges := models.Trip{StartPoint: &geo.Point{Latitude: 90, Longitude: 180}, EndPoint: &geo.Point{Latitude: -90, Longitude: -180}}
+	if err := checkPositions(&edges); err != nil {
+		t.Errorf("edge coordinates: %v", err)
+	}
+}
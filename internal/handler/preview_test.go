@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/models"
+
+	"github.com/Semhumc/grpc-proto/proto"
+	"github.com/gofiber/fiber/v2"
+)
+
+// rewritingPlanner açıklamayı kendi cümleleriyle yeniden yazan bir AI gibi
+// davranır; description boşsa istekteki açıklamayı aynen döner.
+type rewritingPlanner struct {
+	description string
+}
+
+func (p rewritingPlanner) GenerateTripPlan(_ context.Context, req *proto.PromptRequest) (*proto.TripOptionsResponse, error) {
+	description := p.description
+	if description == "" {
+		description = req.Description
+	}
+	return &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{
+		Theme: "Tarih",
+		Trip: &proto.Trip{
+			UserId:        req.UserId,
+			Name:          req.Name,
+			Description:   description,
+			StartPosition: req.StartPosition,
+			EndPosition:   req.EndPosition,
+			StartDate:     req.StartDate,
+			EndDate:       req.EndDate,
+			TotalDays:     2,
+		},
+		DailyPlan: []*proto.DailyPlan{
+			{Day: 1, Date: "2026-06-01", Location: &proto.Location{Name: "Çeşme Limanı", Latitude: 38.3236, Longitude: 26.3029}},
+			{Day: 2, Date: "2026-06-02", Location: &proto.Location{Name: "Bodrum Kalesi", Latitude: 37.0317, Longitude: 27.4286}},
+		},
+	}}}, nil
+}
+
+// Önizlemedeki açıklama, AI ne döndürürse döndürsün kullanıcınınki olmalı;
+// ara nokta talimatı kaydedilecek trip'e sızmamalı.
+func TestPreviewRestoresUserDescription(t *testing.T) {
+	for name, description := range map[string]string{
+		"echoed":    "",
+		"rewritten": "Ege kıyısında iki gün. Çeşme Limanı'na mutlaka uğranmalı.",
+	} {
+		t.Run(name, func(t *testing.T) {
+			app := fiber.New()
+			h := NewTripHandler(nil, rewritingPlanner{description: description}, nil)
+			app.Post("/trip", h.NewCreateTripHandler)
+
+			body := `{"user_id": "user-1", "name": "Ege turu", "description": "Yaz tatili",
+				"start_position": "İzmir", "end_position": "Bodrum",
+				"start_date": "2026-06-01", "end_date": "2026-06-02",
+				"waypoints": [{"name": "Çeşme Limanı", "point": {"latitude": 38.3236, "longitude": 26.3029}}]}`
+			req := httptest.NewRequest("POST", "/trip", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var result models.TripOptionsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != 200 || len(result.TripOptions) == 0 {
+				t.Fatalf("status %d, %d options", resp.StatusCode, len(result.TripOptions))
+			}
+			if got := result.TripOptions[0].Trip.Description; got != "Yaz tatili" {
+				t.Errorf("description = %q, want the user's description", got)
+			}
+		})
+	}
+}
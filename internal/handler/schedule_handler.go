@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"trip-plan-service/internal/optimizer"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type pinRequest struct {
+	Day int `json:"day"`
+}
+
+// AssignDaysHandler trip'in lokasyonlarını coğrafi kümelere ayırarak
+// start_date ile end_date arasındaki günlere dengeli dağıtır ve her günü
+// kendi içinde sıralar. Sabitlenmiş lokasyonların günü değişmez; günleri
+// ara noktaların sırasıyla çelişiyorsa 422 döner.
+// ?dry_run=true ile kaydetmeden önerilen plan döner.
+func (h *TripHandler) AssignDaysHandler(c *fiber.Ctx) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	locations, err := optimizer.AssignDays(trip.Locations, trip.Trip.TotalDays)
+	if err != nil {
+		var pinned *optimizer.PinnedDayError
+		var order *optimizer.WaypointOrderError
+		if errors.As(err, &pinned) || errors.As(err, &order) || errors.Is(err, optimizer.ErrNoDays) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("❌ Gün ataması hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to assign days"})
+	}
+
+	log.Printf("📅 Gün ataması: trip=%d, %d lokasyon %d güne dağıtıldı", trip.Trip.ID, len(locations), trip.Trip.TotalDays)
+
+	dryRun := c.QueryBool("dry_run")
+	if dryRun {
+		if start, err := time.Parse("2006-01-02", trip.Trip.StartDate); err == nil {
+			for i := range locations {
+				locations[i].Date = start.AddDate(0, 0, locations[i].Day-1).Format("2006-01-02")
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"dry_run":   true,
+			"trip_id":   trip.Trip.ID,
+			"days":      trip.Trip.TotalDays,
+			"locations": locations,
+		})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	if err := tripService.ScheduleTripLocations(context.Background(), int32(trip.Trip.ID), locations); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "trip not found"})
+		case errors.Is(err, service.ErrLocationOrderMismatch):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "trip locations changed during assignment, try again"})
+		}
+		log.Printf("❌ Gün ataması kayıt hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save day assignment"})
+	}
+
+	log.Printf("✅ Gün ataması kaydedildi: trip=%d", trip.Trip.ID)
+
+	updated, err := tripService.GetTripByID(context.Background(), int32(trip.Trip.ID))
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"dry_run":   false,
+		"trip_id":   trip.Trip.ID,
+		"days":      trip.Trip.TotalDays,
+		"locations": updated.Locations,
+	})
+}
+
+// PinLocationHandler lokasyonu body'deki güne sabitler; otomatik gün
+// ataması bu lokasyonu o günden almaz.
+func (h *TripHandler) PinLocationHandler(c *fiber.Ctx) error {
+	var req pinRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	return h.setLocationPin(c, req.Day, true)
+}
+
+// UnpinLocationHandler sabitlemeyi kaldırır; lokasyonun günü değişmez.
+func (h *TripHandler) UnpinLocationHandler(c *fiber.Ctx) error {
+	return h.setLocationPin(c, 0, false)
+}
+
+func (h *TripHandler) setLocationPin(c *fiber.Ctx, day int, pin bool) error {
+	trip, err := h.loadTrip(c)
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+
+	locationID, err := strconv.Atoi(c.Params("location_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid location id"})
+	}
+
+	if pin && (day < 1 || day > trip.Trip.TotalDays) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "day must be between 1 and " + strconv.Itoa(trip.Trip.TotalDays),
+		})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	if err := tripService.SetLocationPin(context.Background(), int32(trip.Trip.ID), int32(locationID), day); err != nil {
+		if errors.Is(err, service.ErrLocationNotInTrip) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "location not found in trip"})
+		}
+		log.Printf("❌ Sabitleme hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update location"})
+	}
+
+	log.Printf("📌 Lokasyon %d: trip=%d, pinned=%v, day=%d", locationID, trip.Trip.ID, pin, day)
+
+	updated, err := tripService.GetTripByID(context.Background(), int32(trip.Trip.ID))
+	if err != nil {
+		return tripLoadErrorResponse(c, err)
+	}
+	for _, loc := range updated.Locations {
+		if loc.ID == locationID {
+			return c.Status(fiber.StatusOK).JSON(loc)
+		}
+	}
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "location not found in trip"})
+}
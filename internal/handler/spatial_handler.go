@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultNearbyRadiusKm = 5.0
+	maxNearbyRadiusKm     = 100.0
+)
+
+// NearbyLocationsHandler bir noktanın (?lat=&lon=) ya da bir lokasyonun
+// (?location_id=) çevresindeki lokasyonları yakından uzağa listeler.
+// ?radius_km= (varsayılan 5, en fazla 100) ve ?limit= desteklenir.
+func (h *TripHandler) NearbyLocationsHandler(c *fiber.Ctx) error {
+	radiusKm, err := queryFloat(c, "radius_km", defaultNearbyRadiusKm)
+	if err != nil || radiusKm <= 0 || radiusKm > maxNearbyRadiusKm {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "radius_km must be between 0 and 100"})
+	}
+	limit := c.QueryInt("limit", defaultLocationPageSize)
+	if limit < 1 || limit > maxLocationPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit must be between 1 and 100"})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+
+	var result *service.NearbyResult
+	if raw := c.Query("location_id"); raw != "" {
+		locationID, err := strconv.Atoi(raw)
+		if err != nil || locationID < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid location_id"})
+		}
+		result, err = tripService.NearbyLocationsOf(context.Background(), locationID, radiusKm, limit)
+		switch {
+		case errors.Is(err, service.ErrUnknownLocation):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "location not found"})
+		case errors.Is(err, service.ErrLocationWithoutCoordinates):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+	} else {
+		lat, latErr := queryFloat(c, "lat", math.NaN())
+		lon, lonErr := queryFloat(c, "lon", math.NaN())
+		if latErr != nil || lonErr != nil || math.IsNaN(lat) || math.IsNaN(lon) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "lat and lon or location_id are required"})
+		}
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "lat must be between -90 and 90, lon between -180 and 180"})
+		}
+		result, err = tripService.NearbyLocations(context.Background(), geo.Point{Latitude: lat, Longitude: lon}, radiusKm, 0, limit)
+	}
+	if err != nil {
+		log.Printf("❌ Yakınlık araması hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to search nearby locations"})
+	}
+
+	log.Printf("📍 Yakınlık araması: %.5f,%.5f r=%gkm, %d sonuç (%s)",
+		result.Center.Latitude, result.Center.Longitude, radiusKm, len(result.Locations), result.Backend)
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// TripsInAreaHandler kullanıcının en az bir durağı verilen alanda kalan
+// trip'lerini listeler. ?bbox=min_lon,min_lat,max_lon,max_lat (GeoJSON
+// sırası); min_lon > max_lon ise alan 180. meridyeni geçer.
+func (h *TripHandler) TripsInAreaHandler(c *fiber.Ctx) error {
+	userID := c.Query("user_id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "user_id is required"})
+	}
+
+	box, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tripService := service.NewTripService(nil, h.DB, nil)
+	trips, err := tripService.TripsInArea(context.Background(), userID, box)
+	if err != nil {
+		log.Printf("❌ Alan araması hatası: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to search trips"})
+	}
+
+	log.Printf("🗺️ Alan araması: user=%s, %d trip bulundu", userID, len(trips))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"bbox": box, "trips": trips})
+}
+
+// queryFloat parametre yoksa def döner; varsa ve sayı değilse hata verir.
+func queryFloat(c *fiber.Ctx, key string, def float64) (float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return value, nil
+}
+
+func parseBBox(raw string) (geo.BBox, error) {
+	invalid := errors.New("bbox must be min_lon,min_lat,max_lon,max_lat")
+
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return geo.BBox{}, invalid
+	}
+	var values [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return geo.BBox{}, invalid
+		}
+		values[i] = value
+	}
+
+	box := geo.BBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}
+	if box.MinLatitude < -90 || box.MaxLatitude > 90 || box.MinLatitude > box.MaxLatitude {
+		return geo.BBox{}, errors.New("bbox latitudes must be between -90 and 90 with min_lat <= max_lat")
+	}
+	if box.MinLongitude < -180 || box.MinLongitude > 180 || box.MaxLongitude < -180 || box.MaxLongitude > 180 {
+		return geo.BBox{}, errors.New("bbox longitudes must be between -180 and 180")
+	}
+	return box, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"testing"
+
+	"trip-plan-service/internal/geo"
+)
+
+func TestParseBBox(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    geo.BBox
+		wantErr string
+	}{
+		{name: "geçerli kutu", raw: "26.5,36.8,28.1,38.6",
+			want: geo.BBox{MinLatitude: 36.8, MinLongitude: 26.5, MaxLatitude: 38.6, MaxLongitude: 28.1}},
+		{name: "boşluklar kırpılır", raw: " 26.5, 36.8 ,28.1 , 38.6",
+			want: geo.BBox{MinLatitude: 36.8, MinLongitude: 26.5, MaxLatitude: 38.6, MaxLongitude: 28.1}},
+		{name: "180. meridyeni geçen kutu", raw: "179,-18,-179,-16",
+			want: geo.BBox{MinLatitude: -18, MinLongitude: 179, MaxLatitude: -16, MaxLongitude: -179}},
+		{name: "eksik değer", raw: "26.5,36.8,28.1", wantErr: "bbox must be min_lon,min_lat,max_lon,max_lat"},
+		{name: "boş", raw: "", wantErr: "bbox must be min_lon,min_lat,max_lon,max_lat"},
+		{name: "sayı değil", raw: "26.5,kuzey,28.1,38.6", wantErr: "bbox must be min_lon,min_lat,max_lon,max_lat"},
+		{name: "NaN", raw: "26.5,NaN,28.1,38.6", wantErr: "bbox must be min_lon,min_lat,max_lon,max_lat"},
+		{name: "sonsuz", raw: "26.5,36.8,Inf,38.6", wantErr: "bbox must be min_lon,min_lat,max_lon,max_lat"},
+		{name: "enlem aralık dışında", raw: "26.5,-91,28.1,38.6",
+			wantErr: "bbox latitudes must be between -90 and 90 with min_lat <= max_lat"},
+		{name: "ters enlemler", raw: "26.5,38.6,28.1,36.8",
+			wantErr: "bbox latitudes must be between -90 and 90 with min_lat <= max_lat"},
+		{name: "boylam aralık dışında", raw: "26.5,36.8,181,38.6", wantErr: "bbox longitudes must be between -180 and 180"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBBox(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseBBox = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
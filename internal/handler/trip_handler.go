@@ -3,12 +3,22 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
+	"trip-plan-service/internal/analysis"
 	"trip-plan-service/internal/client"
+	"trip-plan-service/internal/distance"
+	"trip-plan-service/internal/export"
+	"trip-plan-service/internal/fallback"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/geocode"
 	"trip-plan-service/internal/models"
 	"trip-plan-service/internal/service"
+	"trip-plan-service/internal/validator"
 
 	"github.com/Semhumc/grpc-proto/proto"
 	"github.com/gofiber/fiber/v2"
@@ -16,13 +26,27 @@ import (
 
 type TripHandler struct {
 	DB       *sql.DB
-	AIClient *client.AIClient
+	AIClient client.Planner
+	// Fallback AI servisi hata verdiğinde kullanılır, nil ise devre dışıdır.
+	Fallback client.Planner
+	// PublicBaseURL takvim feed linklerinde kullanılır, boşsa isteğin adresi alınır.
+	PublicBaseURL string
+	// Travel mesafe/süre tahmininde kullanılan hızlardır; boşsa
+	// distance.DefaultProfiles kullanılır.
+	Travel distance.Profiles
+	// Geocoder başlangıç/bitiş metinlerini koordinata çevirir, nil ise
+	// koordinatlar boş kalır.
+	Geocoder geocode.Geocoder
 }
 
-func NewTripHandler(db *sql.DB, aiClient *client.AIClient) *TripHandler {
+// maxWaypoints bir trip'e eklenebilecek en fazla ara nokta sayısıdır.
+const maxWaypoints = 10
+
+func NewTripHandler(db *sql.DB, aiClient client.Planner, fallback client.Planner) *TripHandler {
 	return &TripHandler{
 		DB:       db,
 		AIClient: aiClient,
+		Fallback: fallback,
 	}
 }
 
@@ -32,6 +56,29 @@ type TripHandlerInterface interface {
 	GetUserTripsHandler(c *fiber.Ctx) error
 	DeleteTripHandler(c *fiber.Ctx) error
 	GetTripByIDHandler(c *fiber.Ctx) error
+	ExportGPXHandler(c *fiber.Ctx) error
+	ImportTripHandler(c *fiber.Ctx) error
+	ExportICSHandler(c *fiber.Ctx) error
+	GeoJSONHandler(c *fiber.Ctx) error
+	ExportBundleHandler(c *fiber.Ctx) error
+	ExportCSVHandler(c *fiber.Ctx) error
+	ImportLocationsCSVHandler(c *fiber.Ctx) error
+	OptimizeTripHandler(c *fiber.Ctx) error
+	AssignDaysHandler(c *fiber.Ctx) error
+	PinLocationHandler(c *fiber.Ctx) error
+	UnpinLocationHandler(c *fiber.Ctx) error
+	ExportAccountHandler(c *fiber.Ctx) error
+	DeleteAccountHandler(c *fiber.Ctx) error
+	CreateCalendarFeedHandler(c *fiber.Ctx) error
+	CalendarFeedHandler(c *fiber.Ctx) error
+	AnalyzeTripHandler(c *fiber.Ctx) error
+	ListLocationsHandler(c *fiber.Ctx) error
+	MergeLocationsHandler(c *fiber.Ctx) error
+	AddToCatalogueHandler(c *fiber.Ctx) error
+	RemoveFromCatalogueHandler(c *fiber.Ctx) error
+	DebugVarsHandler(c *fiber.Ctx) error
+	NearbyLocationsHandler(c *fiber.Ctx) error
+	TripsInAreaHandler(c *fiber.Ctx) error
 }
 
 func (h *TripHandler) NewCreateTripHandler(c *fiber.Ctx) error {
@@ -44,6 +91,26 @@ func (h *TripHandler) NewCreateTripHandler(c *fiber.Ctx) error {
 
 	log.Printf("📨 Received trip data: %+v", trip)
 
+	// Geçersiz ulaşım türü için AI servisini boşuna çağırma
+	mode := c.Query("mode")
+	if _, _, err := h.travelProfiles().Speed(mode); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := checkPositions(&trip); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Ara noktalar plandan önce çözülür; validator plana eksik kalanları bu
+	// koordinatlarla ekler. Seçenekler genelde aynı başlangıç/bitişi
+	// paylaştığı için her metin istek boyunca bir kez sorulur.
+	resolved := map[string]*geo.Point{}
+	h.resolvePositions(context.Background(), &trip, resolved)
+
+	waypointNames := make([]string, len(trip.Waypoints))
+	for i, waypoint := range trip.Waypoints {
+		waypointNames[i] = waypoint.Name
+	}
+
 	// gRPC request oluştur
 	grpcReq := client.CreatePromptRequest(
 		trip.UserID,
@@ -53,88 +120,164 @@ func (h *TripHandler) NewCreateTripHandler(c *fiber.Ctx) error {
 		trip.EndPosition,
 		trip.StartDate,
 		trip.EndDate,
+		waypointNames...,
 	)
 
 	log.Printf("📤 gRPC request gönderiliyor: %+v", grpcReq)
 
 	// AI servisini çağır
-	response, err := h.AIClient.GenerateTripPlan(context.Background(), grpcReq)
+	response, reports, err := h.generateValidPlan(context.Background(), h.AIClient, grpcReq, trip.Waypoints)
+	isFallback := false
 	if err != nil {
 		log.Printf("❌ gRPC Error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "failed to generate trip plan",
-			"details": err.Error(),
-		})
+
+		if h.Fallback == nil {
+			return planErrorResponse(c, err)
+		}
+
+		// AI servisi yoksa kullanıcıyı boş bırakmak yerine temel plan üret
+		log.Printf("🛟 Yedek planlayıcı kullanılıyor")
+		response, reports, err = h.generateValidPlan(context.Background(), h.Fallback, grpcReq, trip.Waypoints)
+		if errors.Is(err, fallback.ErrInvalidDates) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid start_date or end_date"})
+		}
+		if err != nil {
+			log.Printf("❌ Yedek planlayıcı hatası: %v", err)
+			return planErrorResponse(c, err)
+		}
+		isFallback = true
 	}
 
 	log.Printf("📥 gRPC Response alındı - Daily plans count: %d", len(response.TripOptions))
 
 	// gRPC response'u frontend için uygun formata çevir
-	tripResponse := convertTripOptionsToModel(response)
+	tripResponse := convertTripOptionsToModel(response, reports)
+	// UI yedek planı ayrıca etiketleyebilsin
+	tripResponse.Fallback = isFallback
+
+	for i := range tripResponse.TripOptions {
+		// Ara nokta talimatı sadece AI içindir, kaydedilecek açıklamaya
+		// taşınmaz; AI açıklamayı değiştirse de kullanıcınınki kaydedilir
+		tripResponse.TripOptions[i].Trip.Description = trip.Description
+		tripResponse.TripOptions[i].Trip.Waypoints = trip.Waypoints
+		h.resolvePositions(context.Background(), &tripResponse.TripOptions[i].Trip, resolved)
+		if err := h.attachDistances(&tripResponse.TripOptions[i].TripWithLocations, mode); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		// Kullanıcı kaydetmeden önce zayıf seçenekleri görebilsin; analiz
+		// edilemeyen seçenek analizsiz döner
+		result, err := analysis.DefaultLimits.Analyze(&tripResponse.TripOptions[i].TripWithLocations)
+		if err != nil {
+			log.Printf("⚠️ Plan analizi yapılamadı: %v", err)
+		}
+		tripResponse.TripOptions[i].Analysis = result
+	}
 
 	log.Printf("✅ Response hazırlandı: %+v", tripResponse)
 	return c.Status(fiber.StatusOK).JSON(tripResponse)
 }
 
-// gRPC response'u frontend modelına çevir
-func convertTripOptionsToModel(grpcResp *proto.TripOptionsResponse) map[string]interface{} {
+// generateValidPlan planı üretir ve AI çıktısını istekle ve ara noktalarla
+// karşılaştırarak onarır.
+func (h *TripHandler) generateValidPlan(ctx context.Context, planner client.Planner, req *proto.PromptRequest, waypoints []models.Waypoint) (*proto.TripOptionsResponse, []validator.OptionReport, error) {
+	response, err := planner.GenerateTripPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reports, err := validator.ValidateTripOptions(req, response, waypoints)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, reports, nil
+}
+
+func planErrorResponse(c *fiber.Ctx, err error) error {
+	var hopeless *validator.HopelessResponseError
+	if errors.As(err, &hopeless) {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error":   "AI service returned an unusable trip plan",
+			"details": hopeless.Reasons,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "failed to generate trip plan",
+		"details": err.Error(),
+	})
+}
+
+// gRPC response'u frontend modelına çevir. Her seçenek /save'in beklediği
+// TripWithLocations biçimindedir; reports validator çıktısıyla aynı sıradadır.
+func convertTripOptionsToModel(grpcResp *proto.TripOptionsResponse, reports []validator.OptionReport) models.TripOptionsResponse {
+	result := models.TripOptionsResponse{TripOptions: []models.TripOption{}}
 	if grpcResp == nil {
-		return map[string]interface{}{"error": "Empty response from AI service"}
+		return result
 	}
 
-	var tripOptions []map[string]interface{}
+	for i, option := range grpcResp.TripOptions {
+		tripOption := models.TripOption{
+			Theme:       option.Theme,
+			Description: option.Description,
+			TripWithLocations: models.TripWithLocations{
+				Locations: []models.Location{},
+			},
+			Warnings: []string{},
+		}
 
-	for _, option := range grpcResp.TripOptions {
 		// Trip data
-		var tripData map[string]interface{}
 		if option.Trip != nil {
-			tripData = map[string]interface{}{
-				"user_id":        option.Trip.UserId,
-				"name":           option.Trip.Name,
-				"description":    option.Trip.Description,
-				"start_position": option.Trip.StartPosition,
-				"end_position":   option.Trip.EndPosition,
-				"start_date":     option.Trip.StartDate,
-				"end_date":       option.Trip.EndDate,
-				"total_days":     option.Trip.TotalDays,
+			tripOption.Trip = models.Trip{
+				UserID:        option.Trip.UserId,
+				Name:          option.Trip.Name,
+				Description:   option.Trip.Description,
+				StartPosition: option.Trip.StartPosition,
+				EndPosition:   option.Trip.EndPosition,
+				StartDate:     option.Trip.StartDate,
+				EndDate:       option.Trip.EndDate,
+				TotalDays:     int(option.Trip.TotalDays),
 			}
 		}
 
-		// Daily plans
-		var locations []map[string]interface{}
-		for _, dailyPlan := range option.DailyPlan {
-			location := map[string]interface{}{
-				"day":  dailyPlan.Day,
-				"date": dailyPlan.Date,
-			}
+		var waypoints map[int]int
+		if i < len(reports) {
+			waypoints = reports[i].Waypoints
+		}
 
-			if dailyPlan.Location != nil {
-				location["name"] = dailyPlan.Location.Name
-				location["address"] = dailyPlan.Location.Address
-				location["site_url"] = dailyPlan.Location.SiteUrl
-				location["latitude"] = dailyPlan.Location.Latitude
-				location["longitude"] = dailyPlan.Location.Longitude
-				location["notes"] = dailyPlan.Location.Notes
+		// Daily plans, konumu olmayan günler kaydedilecek bir şey içermez
+		for j, dailyPlan := range option.DailyPlan {
+			if dailyPlan.Location == nil {
+				continue
 			}
 
-			locations = append(locations, location)
+			tripOption.Locations = append(tripOption.Locations, models.Location{
+				Name:      dailyPlan.Location.Name,
+				Address:   optionalString(dailyPlan.Location.Address),
+				SiteURL:   optionalString(dailyPlan.Location.SiteUrl),
+				Latitude:  float64(dailyPlan.Location.Latitude),
+				Longitude: float64(dailyPlan.Location.Longitude),
+				Notes:     optionalString(dailyPlan.Location.Notes),
+				Day:       int(dailyPlan.Day),
+				Date:      dailyPlan.Date,
+				Waypoint:  waypoints[j],
+			})
 		}
 
-		// Option oluştur
-		tripOption := map[string]interface{}{
-			"theme":       option.Theme,
-			"description": option.Description,
-			"trip":        tripData,
-			"daily_plan":  locations,
+		if i < len(reports) && reports[i].Warnings != nil {
+			tripOption.Warnings = reports[i].Warnings
 		}
 
-		tripOptions = append(tripOptions, tripOption)
+		result.TripOptions = append(result.TripOptions, tripOption)
 	}
 
-	return map[string]interface{}{
-		"trip_options":  tripOptions,
-		"total_options": len(tripOptions),
+	result.TotalOptions = len(result.TripOptions)
+	return result
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
 	}
+	return &value
 }
 
 func (h *TripHandler) SaveTripHandler(c *fiber.Ctx) error {
@@ -147,6 +290,20 @@ func (h *TripHandler) SaveTripHandler(c *fiber.Ctx) error {
 
 	log.Printf("💾 Saving trip: %s with %d locations", trip.Trip.Name, len(trip.Locations))
 
+	if err := checkPositions(&trip.Trip); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	for _, loc := range trip.Locations {
+		if loc.Waypoint < 0 || loc.Waypoint > len(trip.Trip.Waypoints) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("location %q refers to unknown waypoint %d", loc.Name, loc.Waypoint),
+			})
+		}
+	}
+
+	// Önizlemeden gelen koordinatlar korunur, eksikler burada çözülür
+	h.resolvePositions(context.Background(), &trip.Trip, map[string]*geo.Point{})
+
 	tripService := service.NewTripService(&trip.Trip, h.DB, trip.Locations)
 
 	err := tripService.SaveTripWLocations(context.Background())
@@ -165,10 +322,16 @@ func (h *TripHandler) GetUserTripsHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "user_id is required"})
 	}
 
+	// countries=TR,GR verilirse bu ülkelerden en az birine uğrayan trip'ler döner
+	countries, err := parseCountryCodes(c.Query("countries"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	log.Printf("📖 Getting trips for user: %s", userID)
 
 	tripService := service.NewTripService(nil, h.DB, nil)
-	trips, err := tripService.GetUserTrips(context.Background(), userID)
+	trips, err := tripService.GetUserTrips(context.Background(), userID, countries...)
 	if err != nil {
 		log.Printf("❌ Get user trips hatası: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get trips"})
@@ -178,6 +341,23 @@ func (h *TripHandler) GetUserTripsHandler(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"trips": trips})
 }
 
+// parseCountryCodes virgülle ayrılmış ISO 3166-1 alpha-2 kodlarını büyük
+// harfe çevirerek döner.
+func parseCountryCodes(raw string) ([]string, error) {
+	var codes []string
+	for _, part := range strings.Split(raw, ",") {
+		code := strings.ToUpper(strings.TrimSpace(part))
+		if code == "" {
+			continue
+		}
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, fmt.Errorf("invalid country code: %q", part)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
 func (h *TripHandler) DeleteTripHandler(c *fiber.Ctx) error {
 	tripIDStr := c.Params("id")
 	tripID, err := strconv.Atoi(tripIDStr)
@@ -199,21 +379,158 @@ func (h *TripHandler) DeleteTripHandler(c *fiber.Ctx) error {
 }
 
 func (h *TripHandler) GetTripByIDHandler(c *fiber.Ctx) error {
-	tripIDStr := c.Params("id")
-	tripID, err := strconv.Atoi(tripIDStr)
+	log.Printf("📖 Getting trip by ID: %s", c.Params("id"))
+
+	trip, err := h.loadTrip(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid trip id"})
+		return tripLoadErrorResponse(c, err)
 	}
 
-	log.Printf("📖 Getting trip by ID: %d", tripID)
+	log.Printf("✅ Trip bulundu: %s", trip.Trip.Name)
 
-	tripService := service.NewTripService(nil, h.DB, nil)
-	trip, err := tripService.GetTripByID(context.Background(), int32(tripID))
-	if err != nil {
-		log.Printf("❌ Get trip by ID hatası: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get trip"})
+	if err := h.attachDistances(trip, c.Query("mode")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	log.Printf("✅ Trip bulundu: %s", trip.Trip.Name)
+	// ?format= Accept header'ından önceliklidir
+	switch tripResponseFormat(c) {
+	case "html":
+		body, err := export.ItineraryHTML(trip)
+		if err != nil {
+			log.Printf("❌ HTML render hatası: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to render trip"})
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Status(fiber.StatusOK).Send(body)
+	case "markdown":
+		body, err := export.ItineraryMarkdown(trip)
+		if err != nil {
+			log.Printf("❌ Markdown render hatası: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to render trip"})
+		}
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+		return c.Status(fiber.StatusOK).Send(body)
+	case "":
+		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{"error": "format must be one of json, html, markdown"})
+	}
 	return c.Status(fiber.StatusOK).JSON(trip)
 }
+
+func (h *TripHandler) travelProfiles() distance.Profiles {
+	if len(h.Travel.Speeds) == 0 {
+		return distance.DefaultProfiles
+	}
+	return h.Travel
+}
+
+// attachDistances trip'e ardışık duraklar arası mesafe ve süre özetini ekler.
+// mode boşsa varsayılan ulaşım türü kullanılır.
+func (h *TripHandler) attachDistances(trip *models.TripWithLocations, mode string) error {
+	distances, err := h.travelProfiles().Compute(trip.Locations, mode)
+	if err != nil {
+		return err
+	}
+	trip.Distances = distances
+	return nil
+}
+
+// resolvePositions trip'in başlangıç, bitiş ve ara nokta metinlerini
+// koordinata çevirir. Koordinatı zaten olan alanlara, koordinat başka bir
+// metinden çözülmemişse dokunulmaz; metni değişmiş alanların koordinatı
+// atılıp yeniden çözülür. Çözülemeyen noktalar boş kalır ve geocoder hatası
+// isteği düşürmez. resolved aynı metnin aynı istekte tekrar sorulmasını önler.
+func (h *TripHandler) resolvePositions(ctx context.Context, trip *models.Trip, resolved map[string]*geo.Point) {
+	resolve := func(text string) *geo.Point {
+		if text == "" || h.Geocoder == nil {
+			return nil
+		}
+		if point, ok := resolved[text]; ok {
+			return point
+		}
+
+		var point *geo.Point
+		result, err := h.Geocoder.Geocode(ctx, text)
+		switch {
+		case err == nil:
+			point = &result.Point
+		case !errors.Is(err, geocode.ErrNotFound):
+			log.Printf("⚠️ %q için koordinat bulunamadı: %v", text, err)
+		}
+		resolved[text] = point
+		return point
+	}
+
+	position := func(point **geo.Point, query *string, text string) {
+		text = strings.TrimSpace(text)
+		if *point != nil && (*query == "" || *query == text) {
+			return
+		}
+		*point, *query = resolve(text), ""
+		if *point != nil {
+			*query = text
+		}
+	}
+
+	position(&trip.StartPoint, &trip.StartPointQuery, trip.StartPosition)
+	position(&trip.EndPoint, &trip.EndPointQuery, trip.EndPosition)
+	for i := range trip.Waypoints {
+		position(&trip.Waypoints[i].Point, &trip.Waypoints[i].PointQuery, trip.Waypoints[i].Name)
+	}
+}
+
+// checkPositions ara nokta adlarını kırpar; sayısı maxWaypoints'i aşan, adı
+// boş ya da başlangıç, bitiş veya ara nokta koordinatı geçersiz trip'ler
+// için hata döner.
+func checkPositions(trip *models.Trip) error {
+	if !validPoint(trip.StartPoint) {
+		return errors.New("start_point has out of range coordinates")
+	}
+	if !validPoint(trip.EndPoint) {
+		return errors.New("end_point has out of range coordinates")
+	}
+	if len(trip.Waypoints) > maxWaypoints {
+		return fmt.Errorf("at most %d waypoints are allowed", maxWaypoints)
+	}
+	for i := range trip.Waypoints {
+		trip.Waypoints[i].Name = strings.TrimSpace(trip.Waypoints[i].Name)
+		if trip.Waypoints[i].Name == "" {
+			return fmt.Errorf("waypoint %d has no name", i+1)
+		}
+		if !validPoint(trip.Waypoints[i].Point) {
+			return fmt.Errorf("waypoint %d has out of range coordinates", i+1)
+		}
+	}
+	return nil
+}
+
+// validPoint boş ya da geçerli aralıktaki koordinatlar için true döner.
+func validPoint(p *geo.Point) bool {
+	return p == nil || (p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180)
+}
+
+// tripResponseFormat istenen çıktı biçimini döner: "json", "html" ya da
+// "markdown". Desteklenmeyen bir biçim istendiyse boş döner.
+func tripResponseFormat(c *fiber.Ctx) string {
+	if format := c.Query("format"); format != "" {
+		switch strings.ToLower(format) {
+		case "json":
+			return "json"
+		case "html":
+			return "html"
+		case "markdown", "md":
+			return "markdown"
+		}
+		return ""
+	}
+
+	// Accept yoksa ya da */* ise ilk teklif (JSON) seçilir
+	switch c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML, "text/markdown") {
+	case fiber.MIMEApplicationJSON:
+		return "json"
+	case fiber.MIMETextHTML:
+		return "html"
+	case "text/markdown":
+		return "markdown"
+	}
+	return ""
+}
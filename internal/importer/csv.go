@@ -0,0 +1,319 @@
+// internal/importer/csv.go for trip-plan-service
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// CSVColumns CSV'den okunabilen alanlardır; export.CSVHeader ile aynıdır.
+var CSVColumns = []string{"position", "day", "name", "address", "latitude", "longitude", "site_url", "notes"}
+
+// CSVFormulaPrefixes tablo programlarının hücreyi formül olarak yorumladığı
+// ilk karakterlerdir. Export bu karakterlerle ya da ' ile başlayan metinlerin
+// başına ' ekler; ParseCSV bu öneki kaldırır.
+const CSVFormulaPrefixes = "=+-@\t\r"
+
+// csvAliases tablolarda sık görülen başlıkları alanlara eşler. Başlıklar
+// geo.NormalizeName ile sadeleştirilip boşluklar "_" yapılarak karşılaştırılır.
+var csvAliases = map[string]string{
+	"position": "position", "pos": "position", "order": "position", "sira": "position", "sira_no": "position", "no": "position",
+	"day": "day", "gun": "day",
+	"name": "name", "ad": "name", "adi": "name", "isim": "name", "title": "name", "location": "name", "lokasyon": "name", "durak": "name", "yer": "name", "mekan": "name",
+	"address": "address", "adres": "address",
+	"latitude": "latitude", "lat": "latitude", "enlem": "latitude",
+	"longitude": "longitude", "lon": "longitude", "lng": "longitude", "long": "longitude", "boylam": "longitude",
+	"site_url": "site_url", "url": "site_url", "website": "site_url", "web": "site_url", "link": "site_url", "site": "site_url",
+	"notes": "notes", "note": "notes", "not": "notes", "notlar": "notes", "description": "notes", "aciklama": "notes",
+}
+
+// ErrNoCSVRows CSV'de başlık dışında satır olmadığında döner.
+var ErrNoCSVRows = errors.New("importer: CSV contains no location rows")
+
+// CSVHeaderError başlık satırı alanlara eşlenemediğinde döner.
+type CSVHeaderError struct {
+	Problems []string
+}
+
+func (e *CSVHeaderError) Error() string {
+	return "importer: invalid CSV header: " + strings.Join(e.Problems, "; ")
+}
+
+// RowError tek bir CSV satırındaki sorunu anlatır. Row dosyadaki satır
+// numarasıdır (başlık 1. satır).
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// CSVOptions CSV ayrıştırmasını yönlendirir.
+type CSVOptions struct {
+	// Mapping alan adından ("name", "latitude"...) dosyadaki başlığa eşleme
+	// verir ve otomatik eşlemenin önüne geçer.
+	Mapping map[string]string
+	// MaxDay sıfırdan büyükse daha büyük gün numaraları satır hatası olur.
+	MaxDay int
+}
+
+// ParseCSV CSV içeriğini lokasyonlara çevirir. Ayraç (virgül, noktalı virgül
+// ya da sekme) başlık satırından tahmin edilir, başlıklar bilinen adlarla
+// (Türkçe karşılıkları dahil) eşlenir. position sütunu varsa satırlar ona
+// göre dizilir.
+//
+// Satır hataları toplanarak döner; herhangi bir satır hatalıysa çağıran
+// hiçbir lokasyonu kaydetmemelidir.
+func ParseCSV(data []byte, opts CSVOptions) ([]models.Location, []RowError, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = csvDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, ErrNoCSVRows
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("importer: failed to read CSV header: %v", err)
+	}
+
+	columns, err := mapCSVHeader(header, opts.Mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		locations []models.Location
+		positions []int
+		rowErrors []RowError
+	)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, RowError{Row: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("importer: failed to read CSV: %v", err)
+		}
+		if blankRecord(record) {
+			continue
+		}
+		// Tırnak içinde satır sonu olabileceği için satır numarası okuyucudan alınır
+		row, _ := reader.FieldPos(0)
+
+		location, position, problems := parseCSVRecord(record, columns, row, opts.MaxDay)
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, problems...)
+			continue
+		}
+		locations = append(locations, location)
+		positions = append(positions, position)
+	}
+
+	if len(locations) == 0 && len(rowErrors) == 0 {
+		return nil, nil, ErrNoCSVRows
+	}
+
+	if _, ok := columns["position"]; ok {
+		sortStable(locations, positions)
+	}
+	return locations, rowErrors, nil
+}
+
+func csvDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	best, bestCount := ',', bytes.Count(firstLine, []byte(","))
+	for _, candidate := range []rune{';', '\t'} {
+		if count := bytes.Count(firstLine, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// mapCSVHeader alan adından sütun indeksine eşleme üretir.
+func mapCSVHeader(header []string, mapping map[string]string) (map[string]int, error) {
+	var problems []string
+	columns := map[string]int{}
+
+	normalized := make([]string, len(header))
+	for i, name := range header {
+		normalized[i] = headerKey(name)
+	}
+
+	for field := range mapping {
+		if !isCSVColumn(field) {
+			problems = append(problems, fmt.Sprintf("mapping refers to unknown field %q", field))
+		}
+	}
+	for _, field := range CSVColumns {
+		name, ok := mapping[field]
+		if !ok {
+			continue
+		}
+		found := false
+		for i, key := range normalized {
+			if key == headerKey(name) {
+				columns[field] = i
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("mapped column %q for %s is not in the header", name, field))
+		}
+	}
+
+	for i, key := range normalized {
+		field, ok := csvAliases[key]
+		if !ok {
+			continue
+		}
+		if _, taken := columns[field]; taken {
+			continue
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		problems = append(problems, "no column for name")
+	}
+	_, hasLat := columns["latitude"]
+	_, hasLon := columns["longitude"]
+	if hasLat != hasLon {
+		problems = append(problems, "latitude and longitude columns must be given together")
+	}
+
+	if len(problems) > 0 {
+		return nil, &CSVHeaderError{Problems: problems}
+	}
+	return columns, nil
+}
+
+func parseCSVRecord(record []string, columns map[string]int, row, maxDay int) (models.Location, int, []RowError) {
+	var problems []RowError
+	fail := func(column, format string, args ...interface{}) {
+		problems = append(problems, RowError{Row: row, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	// Export'un formül koruması için eklediği ' öneki geri alınır
+	text := func(field string) string {
+		value := cell(field)
+		if len(value) > 1 && value[0] == '\'' && (value[1] == '\'' || strings.ContainsRune(CSVFormulaPrefixes, rune(value[1]))) {
+			return value[1:]
+		}
+		return value
+	}
+
+	location := models.Location{
+		Name:    text("name"),
+		Address: optional(text("address")),
+		Notes:   optional(text("notes")),
+		SiteURL: optional(text("site_url")),
+	}
+	if location.Name == "" {
+		fail("name", "name is required")
+	}
+
+	if raw := cell("day"); raw != "" {
+		day, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || day < 1:
+			fail("day", "day must be a positive integer, got %q", raw)
+		case maxDay > 0 && day > maxDay:
+			fail("day", "day %d is after the last day of the trip (%d)", day, maxDay)
+		}
+		location.Day = day
+	}
+
+	position := math.MaxInt32
+	if raw := cell("position"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			fail("position", "position must be a positive integer, got %q", raw)
+		}
+		position = value
+	}
+
+	rawLat, rawLon := cell("latitude"), cell("longitude")
+	if rawLat != "" || rawLon != "" {
+		lat, latErr := parseCoordinate(rawLat)
+		lon, lonErr := parseCoordinate(rawLon)
+		switch {
+		case latErr != nil:
+			fail("latitude", "invalid latitude %q", rawLat)
+		case lonErr != nil:
+			fail("longitude", "invalid longitude %q", rawLon)
+		case validCoordinates(lat, lon) != nil:
+			fail("latitude", "coordinates %s,%s are out of range", rawLat, rawLon)
+		default:
+			location.Latitude, location.Longitude = lat, lon
+		}
+	}
+
+	if location.SiteURL != nil {
+		u, err := url.Parse(*location.SiteURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("site_url", "invalid URL %q", *location.SiteURL)
+		}
+	}
+
+	return location, position, problems
+}
+
+// parseCoordinate ondalık ayracı virgül olan değerleri de kabul eder ("41,0082").
+func parseCoordinate(raw string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(raw, ",", ".", 1), 64)
+}
+
+func headerKey(name string) string {
+	return strings.ReplaceAll(geo.NormalizeName(name), " ", "_")
+}
+
+func isCSVColumn(field string) bool {
+	for _, column := range CSVColumns {
+		if column == field {
+			return true
+		}
+	}
+	return false
+}
+
+func blankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
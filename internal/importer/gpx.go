@@ -0,0 +1,190 @@
+// internal/importer/gpx.go for trip-plan-service
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// dayMatchKm bir waypoint'in bir track segmentine ait sayılması için
+// segmentteki en yakın noktaya olan azami mesafedir.
+const dayMatchKm = 1.0
+
+// GPX 1.0 ve 1.1 ortak alanları; namespace'ten bağımsız eşleşir.
+type gpxFile struct {
+	Name     string `xml:"name"` // GPX 1.0
+	Metadata struct {
+		Name string `xml:"name"`
+		Desc string `xml:"desc"`
+	} `xml:"metadata"`
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []struct {
+		Name   string     `xml:"name"`
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat   float64 `xml:"lat,attr"`
+	Lon   float64 `xml:"lon,attr"`
+	Name  string  `xml:"name"`
+	Desc  string  `xml:"desc"`
+	Cmt   string  `xml:"cmt"`
+	URL   string  `xml:"url"` // GPX 1.0
+	Links []struct {
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+}
+
+func (p gpxPoint) point() geo.Point {
+	return geo.Point{Latitude: p.Lat, Longitude: p.Lon}
+}
+
+func (p gpxPoint) location() models.Location {
+	notes := p.Desc
+	if notes == "" {
+		notes = p.Cmt
+	}
+	url := p.URL
+	if len(p.Links) > 0 {
+		url = p.Links[0].Href
+	}
+	return models.Location{
+		Name:      strings.TrimSpace(p.Name),
+		Latitude:  p.Lat,
+		Longitude: p.Lon,
+		Notes:     optional(notes),
+		SiteURL:   optional(url),
+	}
+}
+
+// parseGPX waypoint'leri lokasyona çevirir. Sıra rota varsa rotadan, gün
+// bilgisi birden fazla track segmenti ya da "Gün N" adlı track'lerden çıkarılır.
+// Waypoint yoksa rota noktaları, o da yoksa track'lerin isimli noktaları
+// (ya da her segmentin başı ve sonu) kullanılır.
+func parseGPX(data []byte) (*models.TripWithLocations, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("importer: invalid GPX: %v", err)
+	}
+
+	trip := &models.TripWithLocations{}
+	trip.Trip.Name = file.Metadata.Name
+	if trip.Trip.Name == "" {
+		trip.Trip.Name = file.Name
+	}
+	trip.Trip.Description = file.Metadata.Desc
+
+	var route []geo.Point
+	for _, rte := range file.Routes {
+		for _, p := range rte.Points {
+			route = append(route, p.point())
+		}
+	}
+
+	points := file.Waypoints
+	if len(points) == 0 {
+		for _, rte := range file.Routes {
+			points = append(points, rte.Points...)
+		}
+		route = nil
+	}
+	if len(points) == 0 {
+		points = trackStops(file)
+	}
+
+	for i, p := range points {
+		if err := validCoordinates(p.Lat, p.Lon); err != nil {
+			return nil, err
+		}
+		loc := p.location()
+		if loc.Name == "" {
+			loc.Name = fmt.Sprintf("Nokta %d", i+1)
+		}
+		trip.Locations = append(trip.Locations, loc)
+	}
+
+	orderAlong(trip.Locations, route)
+	assignTrackDays(trip.Locations, file)
+
+	return trip, nil
+}
+
+func trackStops(file gpxFile) []gpxPoint {
+	var named, ends []gpxPoint
+	for _, trk := range file.Tracks {
+		for _, seg := range trk.Segments {
+			for _, p := range seg.Points {
+				if strings.TrimSpace(p.Name) != "" {
+					named = append(named, p)
+				}
+			}
+			if n := len(seg.Points); n > 0 {
+				ends = append(ends, seg.Points[0])
+				if n > 1 {
+					ends = append(ends, seg.Points[n-1])
+				}
+			}
+		}
+	}
+	if len(named) > 0 {
+		return named
+	}
+	return ends
+}
+
+// assignTrackDays her lokasyonu en yakın track segmentinin gününe atar.
+func assignTrackDays(locations []models.Location, file gpxFile) {
+	type daySegment struct {
+		day    int
+		points []geo.Point
+	}
+
+	var segments []daySegment
+	for _, trk := range file.Tracks {
+		trackDay := dayFromName(trk.Name)
+		for _, seg := range trk.Segments {
+			s := daySegment{day: trackDay}
+			for _, p := range seg.Points {
+				s.points = append(s.points, p.point())
+			}
+			segments = append(segments, s)
+		}
+	}
+
+	// Tek segment gün bilgisi taşımaz
+	if len(segments) < 2 {
+		hasNamedDay := len(segments) == 1 && segments[0].day > 0
+		if !hasNamedDay {
+			return
+		}
+	}
+	for i := range segments {
+		if segments[i].day == 0 {
+			segments[i].day = i + 1
+		}
+	}
+
+	for i := range locations {
+		point := geo.Point{Latitude: locations[i].Latitude, Longitude: locations[i].Longitude}
+		bestDay, bestDistance := 0, dayMatchKm
+		for _, s := range segments {
+			for _, p := range s.points {
+				if d := geo.HaversineKm(point, p); d <= bestDistance {
+					bestDay, bestDistance = s.day, d
+				}
+			}
+		}
+		locations[i].Day = bestDay
+	}
+}
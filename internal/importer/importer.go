@@ -0,0 +1,146 @@
+// internal/importer/importer.go for trip-plan-service
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// ErrUnsupportedFormat dosya GPX ya da KML olarak tanınmadığında döner.
+var ErrUnsupportedFormat = errors.New("importer: unsupported file format, expected GPX or KML")
+
+// ErrNoLocations dosyada koordinatlı hiç nokta bulunamadığında döner.
+var ErrNoLocations = errors.New("importer: file contains no waypoints or placemarks")
+
+// dayPattern "Gün 2", "Day 3" gibi klasör/segment adlarından gün numarasını okur.
+var dayPattern = regexp.MustCompile(`(?i)\b(?:day|gun|gün)\s*(\d+)\b`)
+
+// Parse GPX veya KML içeriğini trip'e çevirir. Format önce dosya uzantısından,
+// uzantı tanınmazsa kök XML elemanından belirlenir. Dönen trip'te sadece
+// dosyadan okunabilen alanlar (ad, açıklama) doludur.
+func Parse(filename string, data []byte) (*models.TripWithLocations, error) {
+	var (
+		trip *models.TripWithLocations
+		err  error
+	)
+
+	switch format(filename, data) {
+	case "gpx":
+		trip, err = parseGPX(data)
+	case "kml":
+		trip, err = parseKML(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(trip.Locations) == 0 {
+		return nil, ErrNoLocations
+	}
+
+	// Gün bilgisi varsa önce güne göre sırala, gün içinde çıkarılan sıra korunur
+	keys := make([]int, len(trip.Locations))
+	for i, loc := range trip.Locations {
+		keys[i] = loc.Day
+		if loc.Day == 0 {
+			keys[i] = math.MaxInt32
+		}
+	}
+	sortStable(trip.Locations, keys)
+
+	return trip, nil
+}
+
+func format(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gpx":
+		return "gpx"
+	case ".kml":
+		return "kml"
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return strings.ToLower(start.Name.Local)
+		}
+	}
+}
+
+// dayFromName ad içinde gün numarası varsa döner, yoksa 0.
+func dayFromName(name string) int {
+	match := dayPattern.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	day, _ := strconv.Atoi(match[1])
+	return day
+}
+
+// orderAlong lokasyonları bir çizgi (rota/LineString) üzerindeki en yakın
+// noktanın sırasına göre dizer; çizgi yoksa dosyadaki sıra korunur.
+func orderAlong(locations []models.Location, line []geo.Point) {
+	if len(line) < 2 {
+		return
+	}
+
+	index := make([]int, len(locations))
+	for i, loc := range locations {
+		point := geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude}
+		best, bestDistance := 0, -1.0
+		for j, linePoint := range line {
+			if d := geo.HaversineKm(point, linePoint); bestDistance < 0 || d < bestDistance {
+				best, bestDistance = j, d
+			}
+		}
+		index[i] = best
+	}
+
+	sortStable(locations, index)
+}
+
+// sortStable lokasyonları anahtarlara göre, eşitlerde dosya sırasını koruyarak dizer.
+func sortStable(locations []models.Location, keys []int) {
+	order := make([]int, len(locations))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+
+	sorted := make([]models.Location, len(locations))
+	for i, j := range order {
+		sorted[i] = locations[j]
+	}
+	copy(locations, sorted)
+}
+
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func validCoordinates(lat, lon float64) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return fmt.Errorf("importer: coordinates out of range: %f,%f", lat, lon)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+const validKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Ege turu</name>
+    <Folder>
+      <name>Gün 2</name>
+      <Placemark><name>Bodrum Kalesi</name><Point><coordinates>27.4286,37.0317</coordinates></Point></Placemark>
+    </Folder>
+    <Folder>
+      <name>Gün 1</name>
+      <Placemark><name>Efes</name><Point><coordinates>27.3417,37.9395</coordinates></Point></Placemark>
+      <Placemark><name>Meryem Ana Evi</name><Point><coordinates>27.3340,37.9116</coordinates></Point></Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+func TestParseKML(t *testing.T) {
+	trip, err := Parse("trip.kml", []byte(validKML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, loc := range trip.Locations {
+		got = append(got, loc.Name)
+	}
+	want := "Efes,Meryem Ana Evi,Bodrum Kalesi"
+	if strings.Join(got, ",") != want {
+		t.Errorf("locations = %v, want %s", got, want)
+	}
+}
+
+// Bozuk bir dosya eksik bir trip olarak değil, hata olarak dönmeli.
+func TestParseKMLRejectsMalformedFile(t *testing.T) {
+	tests := map[string]string{
+		"truncated":        validKML[:strings.Index(validKML, "<Folder>\n      <name>Gün 1")],
+		"mismatched_tag":   strings.Replace(validKML, "</Folder>", "</Folderx>", 1),
+		"invalid_entity":   strings.Replace(validKML, "Ege turu", "Ege &turu", 1),
+		"garbage_at_start": strings.Replace(validKML, "<Document>", "<Document><<", 1),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			trip, err := Parse("trip.kml", []byte(data))
+			if err == nil {
+				t.Fatalf("Parse succeeded with %d locations, want error", len(trip.Locations))
+			}
+		})
+	}
+}
+
+func TestSortStableKeepsFileOrderForEqualKeys(t *testing.T) {
+	locations := []models.Location{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+	sortStable(locations, []int{2, 1, 2, 1, 0})
+
+	var got []string
+	for _, loc := range locations {
+		got = append(got, loc.Name)
+	}
+	if strings.Join(got, "") != "ebdac" {
+		t.Errorf("order = %v, want e b d a c", got)
+	}
+}
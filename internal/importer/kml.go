@@ -0,0 +1,166 @@
+// internal/importer/kml.go for trip-plan-service
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	Point       *struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+	LineString *struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"LineString"`
+}
+
+// parseKML tüm Placemark'ları (iç içe Document/Folder'lar dahil) dolaşır.
+// Point'ler lokasyon olur; LineString varsa sıra ona göre belirlenir.
+// "Gün N" adlı klasörlerdeki noktalar o güne atanır.
+func parseKML(data []byte) (*models.TripWithLocations, error) {
+	trip := &models.TripWithLocations{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		line    []geo.Point
+		stack   []string // açık elemanlar
+		folders []string // açık Document/Folder adları, en içteki sonda
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: invalid KML: %v", err)
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+
+			switch {
+			case el.Name.Local == "name" && (parent == "Document" || parent == "Folder"):
+				var name string
+				if err := decoder.DecodeElement(&name, &el); err != nil {
+					return nil, fmt.Errorf("importer: invalid KML: %v", err)
+				}
+				folders[len(folders)-1] = strings.TrimSpace(name)
+				if len(folders) == 1 && trip.Trip.Name == "" {
+					trip.Trip.Name = strings.TrimSpace(name)
+				}
+
+			case el.Name.Local == "Placemark":
+				var placemark kmlPlacemark
+				if err := decoder.DecodeElement(&placemark, &el); err != nil {
+					return nil, fmt.Errorf("importer: invalid KML: %v", err)
+				}
+				location, points, err := placemarkLocation(placemark, len(trip.Locations)+1)
+				if err != nil {
+					return nil, err
+				}
+				line = append(line, points...)
+				if location != nil {
+					location.Day = folderDay(folders)
+					trip.Locations = append(trip.Locations, *location)
+				}
+
+			default:
+				stack = append(stack, el.Name.Local)
+				if el.Name.Local == "Document" || el.Name.Local == "Folder" {
+					folders = append(folders, "")
+				}
+			}
+
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if el.Name.Local == "Document" || el.Name.Local == "Folder" {
+				folders = folders[:len(folders)-1]
+			}
+		}
+	}
+
+	orderAlong(trip.Locations, line)
+	return trip, nil
+}
+
+// placemarkLocation Point içeren Placemark'ı lokasyona çevirir ve varsa
+// LineString noktalarını döner.
+func placemarkLocation(placemark kmlPlacemark, index int) (*models.Location, []geo.Point, error) {
+	var line []geo.Point
+	if placemark.LineString != nil {
+		points, err := parseCoordinates(placemark.LineString.Coordinates)
+		if err != nil {
+			return nil, nil, err
+		}
+		line = points
+	}
+	if placemark.Point == nil {
+		return nil, line, nil
+	}
+
+	points, err := parseCoordinates(placemark.Point.Coordinates)
+	if err != nil || len(points) == 0 {
+		return nil, line, err
+	}
+
+	name := strings.TrimSpace(placemark.Name)
+	if name == "" {
+		name = fmt.Sprintf("Nokta %d", index)
+	}
+	return &models.Location{
+		Name:      name,
+		Latitude:  points[0].Latitude,
+		Longitude: points[0].Longitude,
+		Notes:     optional(placemark.Description),
+	}, line, nil
+}
+
+func folderDay(folders []string) int {
+	for i := len(folders) - 1; i >= 0; i-- {
+		if day := dayFromName(folders[i]); day > 0 {
+			return day
+		}
+	}
+	return 0
+}
+
+// parseCoordinates KML'in "boylam,enlem[,yükseklik]" listesini okur.
+func parseCoordinates(raw string) ([]geo.Point, error) {
+	var points []geo.Point
+	for _, tuple := range strings.Fields(raw) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("importer: invalid KML coordinates %q", tuple)
+		}
+		lon, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("importer: invalid KML longitude %q", parts[0])
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("importer: invalid KML latitude %q", parts[1])
+		}
+		if err := validCoordinates(lat, lon); err != nil {
+			return nil, err
+		}
+		points = append(points, geo.Point{Latitude: lat, Longitude: lon})
+	}
+	return points, nil
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"trip-plan-service/internal/geo"
+)
 
 type Trip struct {
 	ID            int       `json:"id"`
@@ -11,8 +15,30 @@ type Trip struct {
 	Description   string    `json:"description,omitempty"`
 	StartDate     string    `json:"start_date"`
 	EndDate       string    `json:"end_date"`
+	TotalDays     int       `json:"total_days,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	// StartPoint ve EndPoint pozisyon metinlerinin geocoder ile bulunan
+	// koordinatlarıdır; çözülemeyen noktalar için boş kalır.
+	StartPoint *geo.Point `json:"start_point,omitempty"`
+	EndPoint   *geo.Point `json:"end_point,omitempty"`
+	// StartPointQuery ve EndPointQuery koordinatın çözüldüğü metindir. Metin
+	// önizlemeden sonra değiştirilirse koordinat atılıp yeniden çözülür;
+	// boşsa koordinat istemcinin kendi verdiği kabul edilir.
+	StartPointQuery string `json:"start_point_query,omitempty"`
+	EndPointQuery   string `json:"end_point_query,omitempty"`
+	// Waypoints rotanın başlangıç ile bitiş arasında verilen sırayla
+	// uğraması gereken ara noktalarıdır.
+	Waypoints []Waypoint `json:"waypoints,omitempty"`
+}
+
+// Waypoint trip'in mutlaka uğranması gereken bir ara noktasıdır (feribot
+// limanı, akraba evi vb.). Point verilmezse Name geocoder ile çözülür.
+type Waypoint struct {
+	Name  string     `json:"name"`
+	Point *geo.Point `json:"point,omitempty"`
+	// PointQuery Point'in çözüldüğü addır; bkz. Trip.StartPointQuery.
+	PointQuery string `json:"point_query,omitempty"`
 }
 
 type Location struct {
@@ -23,7 +49,17 @@ type Location struct {
 	Latitude  float64   `json:"latitude"`  // enlem
 	Longitude float64   `json:"longitude"` // boylam
 	Notes     *string   `json:"notes,omitempty"`
+	Day       int       `json:"day,omitempty"`    // planlandığı gün (1'den başlar)
+	Date      string    `json:"date,omitempty"`   // Day'e karşılık gelen tarih
+	Pinned    bool      `json:"pinned,omitempty"` // Day'e sabitlendi, otomatik gün ataması değiştirmez
 	CreatedAt time.Time `json:"created_at"`
+	// CountryCode ve AdminRegion kayıt sırasında koordinattan bulunur;
+	// istekte gönderilen değerler dikkate alınmaz.
+	CountryCode string `json:"country_code,omitempty"` // ISO 3166-1 alpha-2
+	AdminRegion string `json:"admin_region,omitempty"` // il/eyalet
+	// Waypoint durağın karşıladığı ara noktanın Trip.Waypoints içindeki
+	// sırasıdır (1'den başlar); ara nokta değilse 0.
+	Waypoint int `json:"waypoint,omitempty"`
 }
 
 type TripLocation struct {
@@ -35,17 +71,92 @@ type TripLocation struct {
 type TripWithLocations struct {
 	Trip      Trip       `json:"trip"`
 	Locations []Location `json:"locations"`
+	// Distances sadece yanıtlarda doldurulur, kayıt sırasında dikkate alınmaz.
+	Distances *TripDistances `json:"distances,omitempty"`
+	// Countries lokasyonların ülke kodlarıdır (ilk geçiş sırasıyla); sadece
+	// kayıtlı trip yanıtlarında doldurulur.
+	Countries []string `json:"countries,omitempty"`
+}
+
+// TripDistances ardışık duraklar arasındaki kuş uçuşu mesafeleri ve seçilen
+// ulaşım türüne göre tahmini süreleri özetler.
+type TripDistances struct {
+	Mode                 string        `json:"mode"`
+	SpeedKmh             float64       `json:"speed_kmh"`
+	TotalDistanceKm      float64       `json:"total_distance_km"`
+	TotalDurationMinutes int           `json:"total_duration_minutes"`
+	Days                 []DayDistance `json:"days"`
+	Legs                 []Leg         `json:"legs"`
+}
+
+// Leg pozisyon sırasıyla iki koordinatlı durak arasındaki yoldur. Gün,
+// varılan durağın günüdür.
+type Leg struct {
+	FromPosition    int     `json:"from_position"`
+	ToPosition      int     `json:"to_position"`
+	From            string  `json:"from"`
+	To              string  `json:"to"`
+	Day             int     `json:"day,omitempty"`
+	DistanceKm      float64 `json:"distance_km"`
+	DurationMinutes int     `json:"duration_minutes"`
+}
+
+type DayDistance struct {
+	Day             int     `json:"day"`
+	DistanceKm      float64 `json:"distance_km"`
+	DurationMinutes int     `json:"duration_minutes"`
 }
 
-// Yeni model ekle:
+// TripOption /preview yanıtındaki bir plan seçeneğidir. TripWithLocations'ı
+// gömdüğü için "trip" ve "locations" alanları olduğu gibi /save'e gönderilebilir.
 type TripOption struct {
-	Theme       string     `json:"theme"`
-	Description string     `json:"description"`
-	Trip        Trip       `json:"trip"`
-	DailyPlan   []Location `json:"daily_plan"`
+	Theme       string `json:"theme"`
+	Description string `json:"description"`
+	TripWithLocations
+	Warnings []string      `json:"warnings"`
+	Analysis *PlanAnalysis `json:"analysis,omitempty"`
+}
+
+// PlanAnalysis bir planın kalitesini 0-100 arası puanlar ve sorunlu günleri
+// listeler.
+type PlanAnalysis struct {
+	Score    int           `json:"score"`
+	Scores   PlanScores    `json:"scores"`
+	Days     []DayAnalysis `json:"days"`
+	Warnings []PlanWarning `json:"warnings"`
+}
+
+// PlanScores kriter bazında 0-100 arası puanlardır; Score bunların
+// ağırlıklı ortalamasıdır.
+type PlanScores struct {
+	DailyDistance int `json:"daily_distance"`
+	Balance       int `json:"balance"`
+	Backtracking  int `json:"backtracking"`
+	Duplicates    int `json:"duplicates"`
+	EmptyDays     int `json:"empty_days"`
+}
+
+type DayAnalysis struct {
+	Day             int      `json:"day"`
+	Date            string   `json:"date,omitempty"`
+	Stops           int      `json:"stops"`
+	DistanceKm      float64  `json:"distance_km"`
+	DurationMinutes int      `json:"duration_minutes"`
+	Warnings        []string `json:"warnings"`
+}
+
+// PlanWarning Code makine tarafından okunabilir sorun türüdür
+// (long_drive, overloaded_day, backtracking, duplicate_location, empty_day).
+// Day sıfırsa uyarı tüm trip içindir.
+type PlanWarning struct {
+	Code    string `json:"code"`
+	Day     int    `json:"day,omitempty"`
+	Message string `json:"message"`
 }
 
 type TripOptionsResponse struct {
 	TripOptions  []TripOption `json:"trip_options"`
 	TotalOptions int          `json:"total_options"`
+	// Fallback plan AI servisi yerine yedek planlayıcıdan geldiyse true olur.
+	Fallback bool `json:"fallback"`
 }
@@ -0,0 +1,135 @@
+// internal/openapi/middleware.go for trip-plan-service
+package openapi
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Options hangi yönde doğrulama yapılacağını belirler. Yanıt doğrulaması
+// maliyetlidir ve test ortamında spec ile handler'lar arasındaki sapmayı
+// yakalamak içindir.
+type Options struct {
+	ValidateRequests  bool
+	ValidateResponses bool
+}
+
+// Middleware istekleri ve (açıksa) yanıtları OpenAPI dokümanına göre doğrular.
+// Geçersiz istekler 400, dokümana uymayan yanıtlar 500 ile sonuçlanır.
+func (s *Spec) Middleware(opts Options) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		r, params := s.find(c.Method(), c.Path())
+
+		if r == nil {
+			if err := c.Next(); err != nil {
+				return err
+			}
+			if opts.ValidateResponses && c.Response().StatusCode() != fiber.StatusNotFound {
+				return driftResponse(c, []string{c.Method() + " " + c.Path() + " is not documented in openapi.json"})
+			}
+			return nil
+		}
+
+		if opts.ValidateRequests {
+			if problems := s.validateRequest(c, r, params); len(problems) > 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "request does not match API specification",
+					"details": problems,
+				})
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if opts.ValidateResponses {
+			if problems := s.validateResponse(c, r); len(problems) > 0 {
+				return driftResponse(c, problems)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *Spec) validateRequest(c *fiber.Ctx, r *route, pathParams map[string]string) []string {
+	var problems []string
+
+	for _, param := range r.operation.Parameters {
+		var raw string
+		switch param.In {
+		case "path":
+			raw = pathParams[param.Name]
+		case "query":
+			raw = c.Query(param.Name)
+		case "header":
+			raw = c.Get(param.Name)
+		default:
+			continue
+		}
+
+		if raw == "" {
+			if param.Required {
+				problems = append(problems, param.In+"."+param.Name+": is required")
+			}
+			continue
+		}
+		problems = append(problems, s.validateRaw(param.Schema, raw, param.In+"."+param.Name)...)
+	}
+
+	body := r.operation.RequestBody
+	if body == nil {
+		return problems
+	}
+
+	if len(c.Body()) == 0 {
+		if body.Required {
+			problems = append(problems, "body: is required")
+		}
+		return problems
+	}
+
+	// Sadece JSON gövdeler şemaya göre doğrulanır; multipart yüklemeler
+	// handler'da kontrol edilir.
+	media, ok := body.Content[fiber.MIMEApplicationJSON]
+	if ok && strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		problems = append(problems, s.validateJSON(media.Schema, c.Body(), "body")...)
+	}
+	return problems
+}
+
+func (s *Spec) validateResponse(c *fiber.Ctx, r *route) []string {
+	status := c.Response().StatusCode()
+
+	resp, ok := r.operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = r.operation.Responses["default"]
+	}
+	if !ok {
+		return []string{"status " + strconv.Itoa(status) + " is not documented for " + r.method + " " + r.path}
+	}
+
+	contentType := string(c.Response().Header.ContentType())
+	if !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return nil
+	}
+
+	media, ok := resp.Content[fiber.MIMEApplicationJSON]
+	if !ok {
+		return []string{"JSON response is not documented for status " + strconv.Itoa(status) + " of " + r.method + " " + r.path}
+	}
+	return s.validateJSON(media.Schema, c.Response().Body(), "response")
+}
+
+func driftResponse(c *fiber.Ctx, problems []string) error {
+	log.Printf("❌ OpenAPI sapması: %s %s: %v", c.Method(), c.Path(), problems)
+
+	c.Response().ResetBody()
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "response does not match API specification",
+		"details": problems,
+	})
+}
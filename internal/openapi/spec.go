@@ -0,0 +1,223 @@
+// internal/openapi/spec.go for trip-plan-service
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// Spec servis tarafından sunulan ve doğrulamada kullanılan OpenAPI dokümanıdır.
+type Spec struct {
+	raw        []byte
+	components components
+	routes     []route
+}
+
+type document struct {
+	Paths      map[string]pathItem `json:"paths"`
+	Components components          `json:"components"`
+}
+
+type components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+	Responses  map[string]*Response  `json:"responses"`
+}
+
+type pathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Post       *Operation   `json:"post"`
+	Put        *Operation   `json:"put"`
+	Patch      *Operation   `json:"patch"`
+	Delete     *Operation   `json:"delete"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema doğrulayıcının desteklediği JSON Schema alt kümesidir.
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Nullable   bool               `json:"nullable"`
+	Required   []string           `json:"required"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+	Enum       []interface{}      `json:"enum"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+}
+
+type route struct {
+	method    string
+	path      string
+	segments  []string
+	operation *Operation
+}
+
+// Load gömülü OpenAPI dokümanını okur ve $ref'leri çözer.
+func Load() (*Spec, error) {
+	var doc document
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: failed to parse spec: %v", err)
+	}
+
+	spec := &Spec{raw: specJSON, components: doc.Components}
+
+	for path, item := range doc.Paths {
+		for method, op := range map[string]*Operation{
+			"GET": item.Get, "POST": item.Post, "PUT": item.Put, "PATCH": item.Patch, "DELETE": item.Delete,
+		} {
+			if op == nil {
+				continue
+			}
+
+			params := append(append([]*Parameter{}, item.Parameters...), op.Parameters...)
+			for i, param := range params {
+				resolved, err := spec.resolveParameter(param)
+				if err != nil {
+					return nil, fmt.Errorf("openapi: %s %s: %v", method, path, err)
+				}
+				params[i] = resolved
+			}
+			op.Parameters = params
+
+			for status, resp := range op.Responses {
+				resolved, err := spec.resolveResponse(resp)
+				if err != nil {
+					return nil, fmt.Errorf("openapi: %s %s: %v", method, path, err)
+				}
+				op.Responses[status] = resolved
+			}
+
+			spec.routes = append(spec.routes, route{
+				method:    method,
+				path:      path,
+				segments:  strings.Split(strings.Trim(path, "/"), "/"),
+				operation: op,
+			})
+		}
+	}
+
+	// Sabit segmentler parametrelerden önce eşleşsin (/trip/list, /trip/{id}'den önce)
+	sort.SliceStable(spec.routes, func(i, j int) bool {
+		return literalCount(spec.routes[i].segments) > literalCount(spec.routes[j].segments)
+	})
+
+	return spec, nil
+}
+
+// JSON dokümanın ham halini döner.
+func (s *Spec) JSON() []byte {
+	return s.raw
+}
+
+// DocsHTML dokümanı okunabilir şekilde gösteren tek dosyalık sayfadır.
+func DocsHTML() []byte {
+	return docsHTML
+}
+
+// find istek için tanımlı işlemi ve path parametrelerini bulur.
+func (s *Spec) find(method, path string) (*route, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := range s.routes {
+		r := &s.routes[i]
+		if r.method != method || len(r.segments) != len(segments) {
+			continue
+		}
+
+		params := map[string]string{}
+		matched := true
+		for j, segment := range r.segments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				params[strings.Trim(segment, "{}")] = segments[j]
+				continue
+			}
+			if segment != segments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r, params
+		}
+	}
+	return nil, nil
+}
+
+func (s *Spec) resolveParameter(param *Parameter) (*Parameter, error) {
+	if param.Ref == "" {
+		return param, nil
+	}
+	resolved, ok := s.components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s", param.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) resolveResponse(resp *Response) (*Response, error) {
+	if resp.Ref == "" {
+		return resp, nil
+	}
+	resolved, ok := s.components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	if !ok {
+		return nil, fmt.Errorf("unknown response %s", resp.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) resolveSchema(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func literalCount(segments []string) int {
+	count := 0
+	for _, segment := range segments {
+		if !strings.HasPrefix(segment, "{") {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,165 @@
+// internal/openapi/validate.go for trip-plan-service
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// validateValue JSON'dan çözülmüş bir değeri şemaya göre kontrol eder ve
+// bulunan sorunları "alan.yolu: açıklama" biçiminde döner.
+func (s *Spec) validateValue(schema *Schema, value interface{}, path string) []string {
+	schema = s.resolveSchema(schema)
+	if schema == nil {
+		return nil
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: must not be null", path)}
+	}
+
+	var problems []string
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return problems
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if field, ok := object[name]; ok {
+				problems = append(problems, s.validateValue(schema.Properties[name], field, path+"."+name)...)
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return problems
+		}
+		for i, item := range items {
+			problems = append(problems, s.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return problems
+		}
+		if !validFormat(schema.Format, str) {
+			fail("%q is not a valid %s", str, schema.Format)
+		}
+
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			fail("must be a %s", schema.Type)
+			return problems
+		}
+		if schema.Type == "integer" && number != math.Trunc(number) {
+			fail("must be an integer")
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			fail("must be >= %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			fail("must be <= %v", *schema.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		fail("must be one of %v", schema.Enum)
+	}
+
+	return problems
+}
+
+// validateRaw path/query parametreleri gibi metin değerleri şemaya göre
+// çevirip doğrular.
+func (s *Spec) validateRaw(schema *Schema, raw string, path string) []string {
+	schema = s.resolveSchema(schema)
+	if schema == nil {
+		return nil
+	}
+
+	var value interface{} = raw
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %q is not an integer", path, raw)}
+		}
+		value = float64(n)
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %q is not a number", path, raw)}
+		}
+		value = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %q is not a boolean", path, raw)}
+		}
+		value = b
+	}
+	return s.validateValue(schema, value, path)
+}
+
+func (s *Spec) validateJSON(schema *Schema, body []byte, path string) []string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("%s: invalid JSON: %v", path, err)}
+	}
+	return s.validateValue(schema, value, path)
+}
+
+func validFormat(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,437 @@
+// internal/optimizer/days.go for trip-plan-service
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// maxClusterRounds dengeli kümelemenin en fazla kaç tur yeniden atama
+// yapacağıdır.
+const maxClusterRounds = 20
+
+// ErrNoDays trip'in gün sayısı hesaplanamadığında döner.
+var ErrNoDays = errors.New("optimizer: trip has no days to assign locations to")
+
+// PinnedDayError sabitlenmiş bir lokasyonun günü trip'in dışında kaldığında döner.
+type PinnedDayError struct {
+	Location string
+	Day      int
+	Days     int
+}
+
+func (e *PinnedDayError) Error() string {
+	return fmt.Sprintf("optimizer: %q is pinned to day %d but the trip has %d days", e.Location, e.Day, e.Days)
+}
+
+// WaypointOrderError sabitlenmiş günler ara noktaların verilen sırayla
+// ziyaret edilmesine izin vermediğinde döner: Location, kendisinden önce
+// gelmesi gereken Previous'tan önceki bir güne düşmüştür.
+type WaypointOrderError struct {
+	Location         string
+	Waypoint         int
+	Previous         string
+	PreviousWaypoint int
+}
+
+func (e *WaypointOrderError) Error() string {
+	return fmt.Sprintf("optimizer: pinned days put waypoint %d %q before waypoint %d %q",
+		e.Waypoint, e.Location, e.PreviousWaypoint, e.Previous)
+}
+
+// AssignDays lokasyonları coğrafi yakınlığa göre days güne dengeli olarak
+// dağıtır ve her günü kendi içinde sıralar. Sabitlenmiş (Pinned) lokasyonlar
+// kendi günlerinde kalır. Bir güne en fazla ceil(n/days) durak düşer;
+// sabitlenenler bu sınırı aşıyorsa o güne başka durak eklenmez.
+//
+// Dönen liste gün sırasıyladır ve her elemanın Day alanı doludur; Date alanı
+// güncellenmez. Koordinatı olmayan duraklar en az durağı olan güne eklenip
+// günün sonuna konur. Ara nokta durakları son olarak ara nokta sırasına
+// dizilir; sabitlenmiş günler buna izin vermiyorsa WaypointOrderError döner.
+func AssignDays(locations []models.Location, days int) ([]models.Location, error) {
+	if days < 1 {
+		return nil, ErrNoDays
+	}
+
+	c := &clustering{
+		locations: locations,
+		days:      days,
+		capacity:  (len(locations) + days - 1) / days,
+		assigned:  make([]int, len(locations)),
+		pinned:    make([]int, days+1),
+		counts:    make([]int, days+1),
+		centroids: make([]*geo.Point, days+1),
+	}
+
+	var withoutCoordinates []int
+	for i, loc := range locations {
+		switch {
+		case loc.Pinned && loc.Day > 0:
+			if loc.Day > days {
+				return nil, &PinnedDayError{Location: loc.Name, Day: loc.Day, Days: days}
+			}
+			c.assigned[i] = loc.Day
+			c.pinned[loc.Day]++
+			c.counts[loc.Day]++
+		case hasCoordinates(loc):
+			c.free = append(c.free, i)
+		default:
+			withoutCoordinates = append(withoutCoordinates, i)
+		}
+	}
+
+	c.updateCentroids()
+	c.seed()
+	for round := 0; round < maxClusterRounds; round++ {
+		if !c.assignFree() {
+			break
+		}
+		c.updateCentroids()
+	}
+	c.relabel()
+
+	for _, i := range withoutCoordinates {
+		day := 1
+		for d := 2; d <= days; d++ {
+			if c.counts[d] < c.counts[day] {
+				day = d
+			}
+		}
+		c.assigned[i] = day
+		c.counts[day]++
+	}
+
+	result := c.ordered()
+	if err := keepWaypointOrder(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// clustering gün indeksleri 1'den başlar; 0. eleman kullanılmaz.
+type clustering struct {
+	locations []models.Location
+	days      int
+	capacity  int
+	// assigned lokasyon indeksinden güne; 0 henüz atanmamış demektir.
+	assigned  []int
+	free      []int
+	pinned    []int
+	counts    []int
+	centroids []*geo.Point
+}
+
+func (c *clustering) point(i int) geo.Point {
+	return geo.Point{Latitude: c.locations[i].Latitude, Longitude: c.locations[i].Longitude}
+}
+
+// seed merkezi olmayan ve boş yeri olan günlere, mevcut merkezlerden en uzak
+// serbest noktayı merkez olarak verir.
+func (c *clustering) seed() {
+	used := map[int]bool{}
+	for d := 1; d <= c.days; d++ {
+		if c.centroids[d] != nil || c.pinned[d] >= c.capacity {
+			continue
+		}
+
+		reference := c.existingCentroids()
+		if len(reference) == 0 {
+			// Hiç merkez yoksa tüm noktaların ortasından en uzaktaki ile başla
+			var points []geo.Point
+			for _, i := range c.free {
+				points = append(points, c.point(i))
+			}
+			if center, ok := geo.Centroid(points); ok {
+				reference = []geo.Point{center}
+			}
+		}
+
+		best, bestDist := -1, -1.0
+		for _, i := range c.free {
+			if used[i] {
+				continue
+			}
+			nearest := math.Inf(1)
+			for _, p := range reference {
+				nearest = math.Min(nearest, geo.HaversineKm(p, c.point(i)))
+			}
+			if nearest > bestDist {
+				best, bestDist = i, nearest
+			}
+		}
+		if best < 0 {
+			return
+		}
+		used[best] = true
+		p := c.point(best)
+		c.centroids[d] = &p
+	}
+}
+
+func (c *clustering) existingCentroids() []geo.Point {
+	var points []geo.Point
+	for d := 1; d <= c.days; d++ {
+		if c.centroids[d] != nil {
+			points = append(points, *c.centroids[d])
+		}
+	}
+	return points
+}
+
+// assignFree serbest noktaları, kapasiteyi aşmadan en yakın merkezlere
+// açgözlü olarak dağıtır. Atama değiştiyse true döner.
+func (c *clustering) assignFree() bool {
+	type candidate struct {
+		index, day int
+		dist       float64
+	}
+	var candidates []candidate
+	for _, i := range c.free {
+		for d := 1; d <= c.days; d++ {
+			if c.centroids[d] != nil {
+				candidates = append(candidates, candidate{i, d, geo.HaversineKm(*c.centroids[d], c.point(i))})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].dist < candidates[b].dist })
+
+	previous := make(map[int]int, len(c.free))
+	for _, i := range c.free {
+		previous[i] = c.assigned[i]
+		c.assigned[i] = 0
+	}
+	copy(c.counts, c.pinned)
+
+	for _, cand := range candidates {
+		if c.assigned[cand.index] != 0 || c.counts[cand.day] >= c.capacity {
+			continue
+		}
+		c.assigned[cand.index] = cand.day
+		c.counts[cand.day]++
+	}
+
+	changed := false
+	for _, i := range c.free {
+		changed = changed || previous[i] != c.assigned[i]
+	}
+	return changed
+}
+
+func (c *clustering) updateCentroids() {
+	members := make([][]geo.Point, c.days+1)
+	for i, day := range c.assigned {
+		if day > 0 && hasCoordinates(c.locations[i]) {
+			members[day] = append(members[day], c.point(i))
+		}
+	}
+	for d := 1; d <= c.days; d++ {
+		if center, ok := geo.Centroid(members[d]); ok {
+			c.centroids[d] = &center
+		}
+	}
+}
+
+// relabel sabitlenmiş durağı olmayan günlerin kümelerini, bir önceki günün
+// merkezine en yakın küme ertesi gün olacak şekilde yeniden numaralar.
+func (c *clustering) relabel() {
+	var movable []int
+	for d := 1; d <= c.days; d++ {
+		if c.pinned[d] == 0 {
+			movable = append(movable, d)
+		}
+	}
+	if len(movable) < 2 {
+		return
+	}
+
+	all := c.existingCentroids()
+	center, _ := geo.Centroid(all)
+
+	mapping := map[int]int{}
+	remaining := append([]int(nil), movable...)
+	var previous *geo.Point
+	for d := 1; d <= c.days; d++ {
+		if c.pinned[d] > 0 {
+			if c.centroids[d] != nil {
+				previous = c.centroids[d]
+			}
+			continue
+		}
+
+		pick := -1
+		for j, cluster := range remaining {
+			if c.centroids[cluster] == nil {
+				continue
+			}
+			if pick < 0 || closerStart(*c.centroids[cluster], *c.centroids[remaining[pick]], previous, center) {
+				pick = j
+			}
+		}
+		if pick < 0 {
+			pick = 0
+		}
+		mapping[remaining[pick]] = d
+		if c.centroids[remaining[pick]] != nil {
+			previous = c.centroids[remaining[pick]]
+		}
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+
+	centroids := append([]*geo.Point(nil), c.centroids...)
+	counts := append([]int(nil), c.counts...)
+	for from, to := range mapping {
+		c.centroids[to] = centroids[from]
+		c.counts[to] = counts[from]
+	}
+	for _, i := range c.free {
+		if to, ok := mapping[c.assigned[i]]; ok {
+			c.assigned[i] = to
+		}
+	}
+}
+
+// closerStart a'nın bir sonraki gün olarak b'den daha uygun olup olmadığını
+// söyler: önceki gün varsa ona yakın olan, yoksa ortadan uzak olan (rotanın
+// bir ucu) seçilir.
+func closerStart(a, b geo.Point, previous *geo.Point, center geo.Point) bool {
+	if previous != nil {
+		return geo.HaversineKm(*previous, a) < geo.HaversineKm(*previous, b)
+	}
+	return geo.HaversineKm(center, a) > geo.HaversineKm(center, b)
+}
+
+// ordered lokasyonları gün sırasıyla, her günü bir önceki günün son
+// durağından devam eden kısa bir yol olacak şekilde dizer.
+func (c *clustering) ordered() []models.Location {
+	byDay := make([][]int, c.days+1)
+	for i, day := range c.assigned {
+		byDay[day] = append(byDay[day], i)
+	}
+
+	result := make([]models.Location, 0, len(c.locations))
+	var previous *geo.Point
+	for d := 1; d <= c.days; d++ {
+		var withCoordinates, without []int
+		for _, i := range byDay[d] {
+			if hasCoordinates(c.locations[i]) {
+				withCoordinates = append(withCoordinates, i)
+			} else {
+				without = append(without, i)
+			}
+		}
+
+		var next *geo.Point
+		for n := d + 1; n <= c.days && next == nil; n++ {
+			next = c.centroids[n]
+		}
+
+		path := c.openPath(withCoordinates, previous, next)
+		if len(path) > 0 {
+			last := c.point(path[len(path)-1])
+			previous = &last
+		}
+
+		for _, i := range append(path, without...) {
+			loc := c.locations[i]
+			loc.Day = d
+			result = append(result, loc)
+		}
+	}
+	return result
+}
+
+// openPath günün duraklarını uçları serbest bir yol olarak sıralar. Yol,
+// önceki günün son durağına en yakın noktadan başlar; ilk günde ise ertesi
+// günün merkezinden en uzak noktadan.
+func (c *clustering) openPath(indexes []int, previous, next *geo.Point) []int {
+	if len(indexes) < 2 {
+		return indexes
+	}
+
+	points := make([]geo.Point, len(indexes))
+	for j, i := range indexes {
+		points[j] = c.point(i)
+	}
+
+	start := 0
+	for j := range points {
+		switch {
+		case previous != nil:
+			if geo.HaversineKm(*previous, points[j]) < geo.HaversineKm(*previous, points[start]) {
+				start = j
+			}
+		case next != nil:
+			if geo.HaversineKm(*next, points[j]) > geo.HaversineKm(*next, points[start]) {
+				start = j
+			}
+		}
+	}
+
+	dist := distances(points)
+	route := twoOpt(nearestNeighbour(dist, start, -1), dist, true)
+
+	result := make([]int, len(route))
+	for j, k := range route {
+		result[j] = indexes[k]
+	}
+	return result
+}
+
+// keepWaypointOrder ara nokta duraklarını kendi pozisyonları arasında yer
+// değiştirerek ara nokta sırasına dizer; her pozisyonun günü korunur.
+// Sabitlenmiş duraklar yalnızca kendi günlerindeki sabitlenmiş ara nokta
+// pozisyonları arasında yer değiştirir. Sıra yine de bozuksa (sabitlenmiş
+// günler ara noktalarla çelişiyorsa) WaypointOrderError döner.
+func keepWaypointOrder(locations []models.Location) error {
+	sortWaypoints(locations, func(loc models.Location) bool { return !loc.Pinned })
+	days := map[int]bool{}
+	for _, loc := range locations {
+		if loc.Pinned && loc.Waypoint > 0 && !days[loc.Day] {
+			days[loc.Day] = true
+			day := loc.Day
+			sortWaypoints(locations, func(loc models.Location) bool { return loc.Pinned && loc.Day == day })
+		}
+	}
+
+	previous := -1
+	for i, loc := range locations {
+		if loc.Waypoint == 0 {
+			continue
+		}
+		if previous >= 0 && loc.Waypoint < locations[previous].Waypoint {
+			return &WaypointOrderError{
+				Location:         loc.Name,
+				Waypoint:         loc.Waypoint,
+				Previous:         locations[previous].Name,
+				PreviousWaypoint: locations[previous].Waypoint,
+			}
+		}
+		previous = i
+	}
+	return nil
+}
+
+// sortWaypoints match'e uyan ara nokta duraklarını kendi pozisyonları
+// arasında ara nokta sırasına dizer; her pozisyonun günü korunur.
+func sortWaypoints(locations []models.Location, match func(models.Location) bool) {
+	var slots []int
+	var moved []models.Location
+	for i, loc := range locations {
+		if loc.Waypoint > 0 && match(loc) {
+			slots = append(slots, i)
+			moved = append(moved, loc)
+		}
+	}
+	sort.SliceStable(moved, func(a, b int) bool { return moved[a].Waypoint < moved[b].Waypoint })
+
+	for j, i := range slots {
+		day := locations[i].Day
+		locations[i] = moved[j]
+		locations[i].Day = day
+	}
+}
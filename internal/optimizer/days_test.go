@@ -0,0 +1,57 @@
+package optimizer
+
+import (
+	"errors"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+func waypointStop(name string, waypoint, day int, pinned bool, lat, lon float64) models.Location {
+	return models.Location{Name: name, Waypoint: waypoint, Day: day, Pinned: pinned, Latitude: lat, Longitude: lon}
+}
+
+// Aynı güne sabitlenmiş ara noktalar gün içinde verilen sıraya dizilmeli.
+func TestAssignDaysOrdersPinnedWaypointsWithinDay(t *testing.T) {
+	locations := []models.Location{
+		waypointStop("Bodrum Limanı", 2, 1, true, 37.03, 27.43),
+		waypointStop("Çeşme Limanı", 1, 1, true, 38.32, 26.30),
+		stop("Efes", 0, 37.94, 27.34),
+		stop("Pamukkale", 0, 37.92, 29.12),
+	}
+
+	result, err := AssignDays(locations, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []int
+	for _, loc := range result {
+		if loc.Waypoint > 0 {
+			order = append(order, loc.Waypoint)
+			if loc.Day != 1 {
+				t.Errorf("%q moved to day %d, want pinned day 1", loc.Name, loc.Day)
+			}
+		}
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("waypoint order = %v, want [1 2]", order)
+	}
+}
+
+// Ara noktaların sırasına aykırı sabitlenmiş günler sessizce kabul edilmemeli.
+func TestAssignDaysRejectsPinnedWaypointsOutOfOrder(t *testing.T) {
+	locations := []models.Location{
+		waypointStop("Çeşme Limanı", 1, 2, true, 38.32, 26.30),
+		waypointStop("Bodrum Limanı", 2, 1, true, 37.03, 27.43),
+		stop("Efes", 0, 37.94, 27.34),
+	}
+
+	_, err := AssignDays(locations, 2)
+	var order *WaypointOrderError
+	if !errors.As(err, &order) {
+		t.Fatalf("err = %v, want WaypointOrderError", err)
+	}
+	if order.Waypoint != 1 || order.PreviousWaypoint != 2 {
+		t.Errorf("err = %+v, want waypoint 1 reported after waypoint 2", order)
+	}
+}
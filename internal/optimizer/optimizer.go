@@ -0,0 +1,254 @@
+// internal/optimizer/optimizer.go for trip-plan-service
+package optimizer
+
+import (
+	"errors"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// maxPasses 2-opt'un iyileşme aradığı tur sayısının üst sınırıdır; pratikte
+// birkaç turda durur, sınır sadece kötü durumlara karşıdır.
+const maxPasses = 50
+
+// ErrMixedDays tüm trip tek rota olarak sıralanmak istendiğinde duraklar
+// farklı günlere dağılmışsa döner; duraklar gün değiştirmeden yer
+// değiştiremeyeceği için gün modu kullanılmalıdır.
+var ErrMixedDays = errors.New("optimizer: stops are spread over several days, optimise per day instead")
+
+// Options optimizasyonun kapsamını belirler.
+type Options struct {
+	// PerDay true ise duraklar sadece kendi günleri içinde yer değiştirir ve
+	// her günün ilk ve son durağı sabit kalır. false ise tüm trip tek rota
+	// olarak ele alınır; sadece ilk ve son durak sabittir ve tüm duraklar
+	// aynı günde (ya da hiçbiri bir günde değil) olmalıdır.
+	PerDay bool
+}
+
+// Result yeni sırayı ve önceki/sonraki toplam mesafeyi içerir. Order[i],
+// yeni sıradaki i. durağın orijinal listedeki indeksidir.
+type Result struct {
+	Order    []int
+	BeforeKm float64
+	AfterKm  float64
+}
+
+// Optimize durakları kuş uçuşu toplam mesafeyi azaltacak şekilde yeniden
+// sıralar: önce en yakın komşu ile bir rota kurar, sonra 2-opt ile
+// kesişen kenarları açar. Koordinatı olmayan duraklar ve ara nokta durakları
+// yerinde kalır; diğer duraklar ara noktaları geçemez, böylece ara noktaların
+// sırası korunur. Bir grupta ya da tüm rotada bulunan sıra mevcut sıradan
+// kısa değilse mevcut sıra korunur.
+func Optimize(locations []models.Location, opts Options) (Result, error) {
+	if !opts.PerDay {
+		for _, loc := range locations {
+			if loc.Day != locations[0].Day {
+				return Result{}, ErrMixedDays
+			}
+		}
+	}
+
+	order := identity(len(locations))
+
+	for _, group := range splitAtWaypoints(locations, groups(locations, opts.PerDay)) {
+		// Koordinatı olmayanlar kendi slotlarında kalır, diğerleri kalan
+		// slotları yeni sırayla doldurur.
+		var slots []int
+		for _, i := range group {
+			if hasCoordinates(locations[i]) {
+				slots = append(slots, i)
+			}
+		}
+
+		points := make([]geo.Point, len(slots))
+		for j, i := range slots {
+			points[j] = geo.Point{Latitude: locations[i].Latitude, Longitude: locations[i].Longitude}
+		}
+
+		for j, k := range solve(points) {
+			order[slots[j]] = slots[k]
+		}
+	}
+
+	result := Result{
+		Order:    order,
+		BeforeKm: pathKm(locations, identity(len(locations))),
+		AfterKm:  pathKm(locations, order),
+	}
+	if result.AfterKm >= result.BeforeKm-1e-9 {
+		result.Order = identity(len(locations))
+		result.AfterKm = result.BeforeKm
+	}
+	return result, nil
+}
+
+// groups optimizasyonda birlikte ele alınacak durak indekslerini döner.
+// Gün modunda aynı günde ardışık duraklar bir gruptur; aynı günün (ya da gün
+// atanmamış durakların) araya başka günler girmiş parçaları ayrı gruplardır,
+// çünkü birlikte sıralanırlarsa aradaki duraklar hesaba katılmaz.
+func groups(locations []models.Location, perDay bool) [][]int {
+	if !perDay {
+		return [][]int{identity(len(locations))}
+	}
+
+	var result [][]int
+	for i, loc := range locations {
+		if i == 0 || loc.Day != locations[i-1].Day {
+			result = append(result, nil)
+		}
+		result[len(result)-1] = append(result[len(result)-1], i)
+	}
+	return result
+}
+
+// splitAtWaypoints grupları ara nokta duraklarından böler. Ara nokta hem
+// önceki parçanın son hem sonrakinin ilk durağıdır; solve uçları sabit
+// tuttuğu için yerinde kalır.
+func splitAtWaypoints(locations []models.Location, groups [][]int) [][]int {
+	var result [][]int
+	for _, group := range groups {
+		var current []int
+		for _, i := range group {
+			current = append(current, i)
+			if locations[i].Waypoint > 0 && len(current) > 1 {
+				result = append(result, current)
+				current = []int{i}
+			}
+		}
+		result = append(result, current)
+	}
+	return result
+}
+
+// solve ilk ve son noktası sabit bir yol için ziyaret sırasını döner.
+func solve(points []geo.Point) []int {
+	n := len(points)
+	current := identity(n)
+	if n <= 3 {
+		return current
+	}
+
+	dist := distances(points)
+	candidate := twoOpt(nearestNeighbour(dist, 0, n-1), dist, false)
+	if length(candidate, dist) < length(current, dist)-1e-9 {
+		return candidate
+	}
+	return current
+}
+
+func distances(points []geo.Point) [][]float64 {
+	dist := make([][]float64, len(points))
+	for i := range dist {
+		dist[i] = make([]float64, len(points))
+		for j := range dist[i] {
+			dist[i][j] = geo.HaversineKm(points[i], points[j])
+		}
+	}
+	return dist
+}
+
+// nearestNeighbour start'tan başlayıp her adımda en yakın ziyaret edilmemiş
+// noktaya gider. end negatif değilse yol o noktada biter.
+func nearestNeighbour(dist [][]float64, start, end int) []int {
+	n := len(dist)
+	visited := make([]bool, n)
+	route := []int{start}
+	visited[start] = true
+	remaining := n - 1
+	if end >= 0 {
+		visited[end] = true
+		remaining--
+	}
+
+	for ; remaining > 0; remaining-- {
+		last := route[len(route)-1]
+		next := -1
+		for j := 0; j < n; j++ {
+			if !visited[j] && (next < 0 || dist[last][j] < dist[last][next]) {
+				next = j
+			}
+		}
+		visited[next] = true
+		route = append(route, next)
+	}
+	if end >= 0 {
+		route = append(route, end)
+	}
+	return route
+}
+
+// twoOpt ilk noktayı (openEnd false ise son noktayı da) sabit tutarak, iki
+// kenarı değiştirmek yolu kısalttıkça aradaki bölümü ters çevirir.
+func twoOpt(route []int, dist [][]float64, openEnd bool) []int {
+	n := len(route)
+	last := n - 1
+	if openEnd {
+		last = n
+	}
+	for pass := 0; pass < maxPasses; pass++ {
+		improved := false
+		for i := 1; i < last-1; i++ {
+			for k := i + 1; k < last; k++ {
+				a, b, c := route[i-1], route[i], route[k]
+				before, after := dist[a][b], dist[a][c]
+				if k+1 < n {
+					d := route[k+1]
+					before += dist[c][d]
+					after += dist[b][d]
+				}
+				if after < before-1e-9 {
+					reverse(route[i : k+1])
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return route
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func length(route []int, dist [][]float64) float64 {
+	total := 0.0
+	for i := 1; i < len(route); i++ {
+		total += dist[route[i-1]][route[i]]
+	}
+	return total
+}
+
+// pathKm verilen sıradaki koordinatlı duraklar arasındaki toplam mesafedir.
+func pathKm(locations []models.Location, order []int) float64 {
+	total := 0.0
+	var previous *geo.Point
+	for _, i := range order {
+		if !hasCoordinates(locations[i]) {
+			continue
+		}
+		point := geo.Point{Latitude: locations[i].Latitude, Longitude: locations[i].Longitude}
+		if previous != nil {
+			total += geo.HaversineKm(*previous, point)
+		}
+		previous = &point
+	}
+	return total
+}
+
+func identity(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func hasCoordinates(loc models.Location) bool {
+	return loc.Latitude != 0 || loc.Longitude != 0
+}
@@ -0,0 +1,91 @@
+package optimizer
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"trip-plan-service/internal/models"
+)
+
+func stop(name string, day int, lat, lon float64) models.Location {
+	return models.Location{Name: name, Day: day, Latitude: lat, Longitude: lon}
+}
+
+// Gün atanmamış duraklar araya giren bir günle ayrılmışsa birlikte
+// sıralanmamalı; aksi halde aradaki gün hesaba katılmadan yer değiştirirler.
+func TestOptimizePerDayKeepsSeparatedRunsApart(t *testing.T) {
+	locations := []models.Location{
+		stop("İzmir", 0, 38.42, 27.14),
+		stop("Manisa", 0, 38.61, 27.43),
+		stop("Kuşadası", 0, 37.86, 27.26),
+		stop("Efes", 1, 37.94, 27.34),
+		stop("Bodrum", 1, 37.03, 27.43),
+		stop("Bergama", 0, 39.12, 27.18),
+		stop("Selçuk", 0, 37.95, 27.37),
+		stop("Ayvalık", 0, 39.32, 26.69),
+		stop("Datça", 0, 36.73, 27.69),
+	}
+
+	result, err := Optimize(locations, Options{PerDay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AfterKm > result.BeforeKm {
+		t.Errorf("AfterKm %.1f > BeforeKm %.1f", result.AfterKm, result.BeforeKm)
+	}
+	for i, index := range result.Order {
+		if (i < 3) != (index < 3) || (i > 4) != (index > 4) {
+			t.Errorf("stop %q moved to position %d across another day", locations[index].Name, i+1)
+		}
+	}
+}
+
+// Rastgele trip'lerde yeni rota hiçbir zaman eskisinden uzun olmamalı ve
+// gün modunda her durak kendi gününde kalmalı.
+func TestOptimizeNeverLengthensRoute(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	for trial := 0; trial < 200; trial++ {
+		locations := make([]models.Location, 3+random.Intn(12))
+		for i := range locations {
+			locations[i] = stop("", random.Intn(3), 36+random.Float64()*4, 26+random.Float64()*6)
+			if random.Intn(8) == 0 {
+				locations[i].Latitude, locations[i].Longitude = 0, 0
+			}
+		}
+
+		result, err := Optimize(locations, Options{PerDay: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.AfterKm > result.BeforeKm {
+			t.Fatalf("trial %d: AfterKm %.3f > BeforeKm %.3f", trial, result.AfterKm, result.BeforeKm)
+		}
+		if got := pathKm(locations, result.Order); got != result.AfterKm {
+			t.Fatalf("trial %d: AfterKm %.3f does not match order length %.3f", trial, result.AfterKm, got)
+		}
+		for i, index := range result.Order {
+			if locations[index].Day != locations[i].Day {
+				t.Fatalf("trial %d: day %d stop moved to a day %d position", trial, locations[index].Day, locations[i].Day)
+			}
+		}
+	}
+}
+
+func TestOptimizeWholeTripRejectsMixedDays(t *testing.T) {
+	locations := []models.Location{
+		stop("Efes", 1, 37.94, 27.34),
+		stop("Bodrum", 2, 37.03, 27.43),
+		stop("Kuşadası", 1, 37.86, 27.26),
+	}
+	if _, err := Optimize(locations, Options{}); !errors.Is(err, ErrMixedDays) {
+		t.Errorf("err = %v, want ErrMixedDays", err)
+	}
+
+	for i := range locations {
+		locations[i].Day = 0
+	}
+	if _, err := Optimize(locations, Options{}); err != nil {
+		t.Errorf("stops without days: err = %v", err)
+	}
+}
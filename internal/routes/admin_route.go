@@ -0,0 +1,27 @@
+// internal/routes/admin_route.go - Yönetim uçları (admin token ile korunur)
+
+package routes
+
+import (
+	"trip-plan-service/internal/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AdminRoutes(router fiber.Router, h handler.TripHandlerInterface, adminToken string) {
+	admin := router.Group("/api/v1/admin", handler.AdminOnly(adminToken))
+
+	// GDPR: kullanıcı verisinin dışa aktarılması ve silinmesi
+	admin.Get("/users/:user_id/export", h.ExportAccountHandler)
+	admin.Delete("/users/:user_id", h.DeleteAccountHandler)
+
+	// Lokasyon kataloğundaki kopyaları birleştir
+	admin.Post("/locations/merge", h.MergeLocationsHandler)
+
+	// Katalog kayıtları sahipsiz lokasyon temizliğinde silinmez
+	admin.Put("/locations/:id/catalogue", h.AddToCatalogueHandler)
+	admin.Delete("/locations/:id/catalogue", h.RemoveFromCatalogueHandler)
+
+	// expvar metrikleri (location_gc, ai_backends vb.)
+	admin.Get("/debug/vars", h.DebugVarsHandler)
+}
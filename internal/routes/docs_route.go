@@ -0,0 +1,21 @@
+// internal/routes/docs_route.go - OpenAPI dokümanı ve dokümantasyon sayfası
+
+package routes
+
+import (
+	"trip-plan-service/internal/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func DocsRoutes(router fiber.Router, spec *openapi.Spec) {
+	router.Get("/openapi.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(spec.JSON())
+	})
+
+	router.Get("/docs", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(openapi.DocsHTML())
+	})
+}
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"trip-plan-service/internal/dbtest"
+)
+
+// missingTripID ile istenen trip bulunamaz.
+const missingTripID = 404
+
+// openFakeDB handler'ların ürettiği yanıtları veritabanı olmadan OpenAPI
+// dokümanına karşı denemek için fakeRows'u dönen bir veritabanı açar.
+func openFakeDB() *sql.DB {
+	return (&dbtest.DB{Rows: fakeRows}).Open()
+}
+
+var (
+	fakeCreatedAt = time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	fakeStartDate = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	fakeEndDate   = time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC)
+)
+
+// Trip sorgularının ortak ilk on kolonu
+func fakeTripColumns() []driver.Value {
+	return []driver.Value{int64(1), "user-1", "Ege turu", "Yaz tatili", fakeStartDate, fakeEndDate, "İzmir", "Bodrum", fakeCreatedAt, fakeCreatedAt}
+}
+
+func fakeTripRow() []driver.Value {
+	return append(fakeTripColumns(), "38.423700", "27.142800", "37.034400", "27.430500")
+}
+
+var fakeRows = map[string]dbtest.RowsFunc{
+	"HasPostGIS": func([]driver.Value) [][]driver.Value { return [][]driver.Value{{false}} },
+
+	"CreateTrip": func([]driver.Value) [][]driver.Value { return [][]driver.Value{fakeTripColumns()} },
+	"GetTripByID": func(args []driver.Value) [][]driver.Value {
+		if args[0] == int64(missingTripID) {
+			return nil
+		}
+		return [][]driver.Value{fakeTripRow()}
+	},
+	"ListTripsByUserID":             func([]driver.Value) [][]driver.Value { return [][]driver.Value{fakeTripRow()} },
+	"ListTripsByUserIDAndCountries": func([]driver.Value) [][]driver.Value { return [][]driver.Value{fakeTripRow()} },
+	"ListUpcomingTripsByUserID":     func([]driver.Value) [][]driver.Value { return [][]driver.Value{fakeTripColumns()} },
+	"ListUserTripsInBox": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{append(fakeTripColumns(), int64(2))}
+	},
+
+	"GetTripLocations": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{
+			{int64(10), "Efes Antik Kenti", "Selçuk, İzmir", "https://muze.gov.tr/efes", "Sabah erken gidin", "37.939500", "27.341700", fakeCreatedAt, "TR", "İzmir", int64(1), int64(1), true, nil},
+			{int64(11), "Kuşadası Limanı", nil, nil, nil, "37.857900", "27.261000", fakeCreatedAt, "TR", "Aydın", int64(2), int64(1), false, int64(1)},
+			{int64(12), "Bodrum Kalesi", nil, nil, nil, "37.031700", "27.428600", fakeCreatedAt, "TR", "Muğla", int64(3), int64(2), false, nil},
+		}
+	},
+	"GetTripWaypoints": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{{int64(1), "Kuşadası", "37.857900", "27.261000"}}
+	},
+
+	"CreateLocation": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{{int64(20), "Yeni Lokasyon", nil, nil, "37.000000", "27.000000", fakeCreatedAt, "TR", nil}}
+	},
+	"GetLocationByID": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{{int64(10), "Efes Antik Kenti", "Selçuk, İzmir", "https://muze.gov.tr/efes", "37.939500", "27.341700", fakeCreatedAt}}
+	},
+	"LockLocationByID": func(args []driver.Value) [][]driver.Value { return [][]driver.Value{{args[0]}} },
+	"ListLocations": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{
+			{int64(12), "Bodrum Kalesi", nil, "37.031700", "27.428600", fakeCreatedAt, true, "TR", "Muğla", int64(0)},
+			{int64(10), "Efes Antik Kenti", "https://muze.gov.tr/efes", "37.939500", "27.341700", fakeCreatedAt, false, "TR", "İzmir", int64(3)},
+		}
+	},
+	"ListLocationsInBox": func([]driver.Value) [][]driver.Value {
+		return [][]driver.Value{
+			{int64(10), "Efes Antik Kenti", "Selçuk, İzmir", "https://muze.gov.tr/efes", "37.939500", "27.341700", fakeCreatedAt},
+			{int64(13), "Meryem Ana Evi", nil, nil, "37.911600", "27.334000", fakeCreatedAt},
+		}
+	},
+	"ListLocationIDsByUserID": func([]driver.Value) [][]driver.Value { return [][]driver.Value{{int64(10)}, {int64(11)}} },
+
+	"CountLocations":         dbtest.Count(2),
+	"CountTripsByUserID":     dbtest.Count(0),
+	"CountOrphanedLocations": dbtest.Count(0),
+
+	// Token yalnızca token ile bulunur; kullanıcı için her seferinde yenisi
+	// oluşturulur ve silme doğrulaması token kalmadığını görür.
+	"GetCalendarTokenByUserID": dbtest.NoRows,
+	"GetCalendarTokenByToken": func(args []driver.Value) [][]driver.Value {
+		return [][]driver.Value{{"user-1", args[0], fakeCreatedAt}}
+	},
+	"UpsertCalendarToken": func(args []driver.Value) [][]driver.Value {
+		return [][]driver.Value{{args[0], args[1], fakeCreatedAt}}
+	},
+}
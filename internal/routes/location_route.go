@@ -0,0 +1,16 @@
+// internal/routes/location_route.go - Paylaşılan lokasyon kataloğu
+
+package routes
+
+import (
+	"trip-plan-service/internal/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func LocationRoutes(router fiber.Router, handler handler.TripHandlerInterface) {
+	api := router.Group("/api/v1/locations")
+
+	api.Get("/", handler.ListLocationsHandler)         // ?q=&limit=&offset=
+	api.Get("/nearby", handler.NearbyLocationsHandler) // ?lat=&lon= ya da ?location_id=, &radius_km=&limit=
+}
@@ -0,0 +1,277 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/geocode"
+	"trip-plan-service/internal/handler"
+	"trip-plan-service/internal/openapi"
+
+	"github.com/Semhumc/grpc-proto/proto"
+	"github.com/gofiber/fiber/v2"
+)
+
+const testAdminToken = "test-admin-token"
+
+// fakePlanner AI servisinin yerine istekteki trip için iki günlük sabit bir
+// plan döner.
+type fakePlanner struct{}
+
+func (fakePlanner) GenerateTripPlan(_ context.Context, req *proto.PromptRequest) (*proto.TripOptionsResponse, error) {
+	return &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{
+		Theme:       "Tarih",
+		Description: "Antik kentler",
+		Trip: &proto.Trip{
+			UserId:        req.UserId,
+			Name:          req.Name,
+			Description:   req.Description,
+			StartPosition: req.StartPosition,
+			EndPosition:   req.EndPosition,
+			StartDate:     req.StartDate,
+			EndDate:       req.EndDate,
+			TotalDays:     2,
+		},
+		DailyPlan: []*proto.DailyPlan{
+			{Day: 1, Date: "2026-06-01", Location: &proto.Location{Name: "Efes Antik Kenti", Address: "Selçuk, İzmir", Latitude: 37.9395, Longitude: 27.3417, Notes: "Sabah erken gidin"}},
+			{Day: 1, Date: "2026-06-01", Location: &proto.Location{Name: "Kuşadası Limanı", Latitude: 37.8579, Longitude: 27.261}},
+			{Day: 2, Date: "2026-06-02", Location: &proto.Location{Name: "Bodrum Kalesi", Latitude: 37.0317, Longitude: 27.4286}},
+		},
+	}}}, nil
+}
+
+func newTestApp(t *testing.T) (*fiber.App, *openapi.Spec) {
+	t.Helper()
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	boundaries, err := geocode.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(spec.Middleware(openapi.Options{ValidateRequests: true, ValidateResponses: true}))
+
+	h := handler.NewTripHandler(openFakeDB(), fakePlanner{}, nil)
+	h.Geocoder = geocode.WithFallback(nil, geocode.NewGazetteer(boundaries))
+
+	TripRoutes(app, h)
+	LocationRoutes(app, h)
+	AdminRoutes(app, h, testAdminToken)
+	DocsRoutes(app, spec)
+	return app, spec
+}
+
+type routeCase struct {
+	// operation dokümandaki "METHOD /yol/{param}" anahtarıdır
+	operation string
+	method    string
+	target    string
+	json      string
+	files     map[string]string
+	form      map[string]string
+	accept    string
+	admin     bool
+	status    int
+}
+
+// TestDocumentedRoutesMatchSpec dokümandaki her operasyonu istek ve yanıt
+// doğrulaması açıkken çalıştırır. Handler'lar ile openapi.json arasındaki
+// her sapma middleware'de 500'e dönüşür ve test başarısız olur.
+func TestDocumentedRoutesMatchSpec(t *testing.T) {
+	app, spec := newTestApp(t)
+
+	gpx := `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="37.9395" lon="27.3417"><name>Efes Antik Kenti</name><desc>Sabah erken gidin</desc></wpt>
+  <wpt lat="37.0317" lon="27.4286"><name>Bodrum Kalesi</name></wpt>
+</gpx>`
+	csv := "position,day,name,address,latitude,longitude,site_url,notes\n" +
+		"1,1,Efes Antik Kenti,\"Selçuk, İzmir\",37.9395,27.3417,https://muze.gov.tr/efes,Sabah erken gidin\n" +
+		"2,2,Bodrum Kalesi,,37.0317,27.4286,,\n"
+	trip := `{"user_id":"user-1","name":"Ege turu","description":"Yaz tatili","start_position":"İzmir","end_position":"Bodrum",` +
+		`"start_date":"2026-06-01","end_date":"2026-06-02","waypoints":[{"name":"Kuşadası","point":{"latitude":37.8579,"longitude":27.261}}]}`
+
+	cases := []routeCase{
+		{operation: "POST /api/v1/trip/preview", method: "POST", target: "/api/v1/trip/preview", json: trip, status: 200},
+		{operation: "POST /api/v1/trip/preview", method: "POST", target: "/api/v1/trip/preview?mode=rocket", json: trip, status: 400},
+		{operation: "POST /api/v1/trip/save", method: "POST", target: "/api/v1/trip/save", status: 200, json: `{"trip":` + trip + `,"locations":[` +
+			`{"name":"Efes Antik Kenti","latitude":37.9395,"longitude":27.3417,"notes":"Sabah erken gidin","day":1},` +
+			`{"name":"Kuşadası Limanı","latitude":37.8579,"longitude":27.261,"day":1,"waypoint":1}]}`},
+		{operation: "GET /api/v1/trip/list", method: "GET", target: "/api/v1/trip/list?user_id=user-1", status: 200},
+		{operation: "GET /api/v1/trip/list", method: "GET", target: "/api/v1/trip/list?user_id=user-1&countries=TR,GR", status: 200},
+		{operation: "GET /api/v1/trip/in-area", method: "GET", target: "/api/v1/trip/in-area?user_id=user-1&bbox=26,36,29,39", status: 200},
+		{operation: "GET /api/v1/trip/{id}", method: "GET", target: "/api/v1/trip/1", status: 200},
+		{operation: "GET /api/v1/trip/{id}", method: "GET", target: "/api/v1/trip/1?format=html", status: 200},
+		{operation: "GET /api/v1/trip/{id}", method: "GET", target: "/api/v1/trip/1?format=markdown", status: 200},
+		{operation: "GET /api/v1/trip/{id}", method: "GET", target: "/api/v1/trip/1", accept: "application/pdf", status: 406},
+		{operation: "GET /api/v1/trip/{id}", method: "GET", target: "/api/v1/trip/404", status: 404},
+		{operation: "DELETE /api/v1/trip/{id}", method: "DELETE", target: "/api/v1/trip/1", status: 200},
+		{operation: "GET /api/v1/trip/{id}/export.gpx", method: "GET", target: "/api/v1/trip/1/export.gpx", status: 200},
+		{operation: "GET /api/v1/trip/{id}/export.gpx", method: "GET", target: "/api/v1/trip/404/export.gpx", status: 404},
+		{operation: "POST /api/v1/trip/import", method: "POST", target: "/api/v1/trip/import?dry_run=true", files: map[string]string{"trip.gpx": gpx}, status: 200},
+		{operation: "POST /api/v1/trip/import", method: "POST", target: "/api/v1/trip/import", files: map[string]string{"trip.gpx": gpx},
+			form: map[string]string{"user_id": "user-1", "start_date": "2026-06-01", "end_date": "2026-06-02"}, status: 200},
+		{operation: "POST /api/v1/trip/import", method: "POST", target: "/api/v1/trip/import", files: map[string]string{"trip.gpx": gpx}, status: 400},
+		{operation: "GET /api/v1/trip/{id}/export.ics", method: "GET", target: "/api/v1/trip/1/export.ics", status: 200},
+		{operation: "POST /api/v1/trip/calendar/feed", method: "POST", target: "/api/v1/trip/calendar/feed?user_id=user-1", status: 200},
+		{operation: "GET /api/v1/trip/calendar/{token}/feed.ics", method: "GET", target: "/api/v1/trip/calendar/abc123/feed.ics", status: 200},
+		{operation: "GET /api/v1/trip/{id}/geojson", method: "GET", target: "/api/v1/trip/1/geojson", status: 200},
+		{operation: "GET /api/v1/trip/{id}/export.zip", method: "GET", target: "/api/v1/trip/1/export.zip", status: 200},
+		{operation: "GET /api/v1/trip/{id}/export.csv", method: "GET", target: "/api/v1/trip/1/export.csv", status: 200},
+		{operation: "GET /api/v1/trip/{id}/analysis", method: "GET", target: "/api/v1/trip/1/analysis", status: 200},
+		{operation: "POST /api/v1/trip/{id}/locations/import", method: "POST", target: "/api/v1/trip/1/locations/import?dry_run=true", files: map[string]string{"locations.csv": csv}, status: 200},
+		{operation: "POST /api/v1/trip/{id}/locations/import", method: "POST", target: "/api/v1/trip/1/locations/import", files: map[string]string{"locations.csv": csv}, status: 200},
+		{operation: "POST /api/v1/trip/{id}/locations/import", method: "POST", target: "/api/v1/trip/1/locations/import",
+			files: map[string]string{"locations.csv": "name,latitude,longitude\nEfes,95,27\n"}, status: 422},
+		{operation: "POST /api/v1/trip/{id}/optimize", method: "POST", target: "/api/v1/trip/1/optimize", status: 422},
+		{operation: "POST /api/v1/trip/{id}/optimize", method: "POST", target: "/api/v1/trip/1/optimize?per_day=true&apply=true", status: 200},
+		{operation: "POST /api/v1/trip/{id}/assign-days", method: "POST", target: "/api/v1/trip/1/assign-days?dry_run=true", status: 200},
+		{operation: "POST /api/v1/trip/{id}/assign-days", method: "POST", target: "/api/v1/trip/1/assign-days", status: 200},
+		{operation: "PUT /api/v1/trip/{id}/locations/{location_id}/pin", method: "PUT", target: "/api/v1/trip/1/locations/10/pin", json: `{"day":2}`, status: 200},
+		{operation: "PUT /api/v1/trip/{id}/locations/{location_id}/pin", method: "PUT", target: "/api/v1/trip/1/locations/10/pin", json: `{"day":9}`, status: 400},
+		{operation: "DELETE /api/v1/trip/{id}/locations/{location_id}/pin", method: "DELETE", target: "/api/v1/trip/1/locations/10/pin", status: 200},
+		{operation: "GET /api/v1/locations", method: "GET", target: "/api/v1/locations?q=efes&limit=10", status: 200},
+		{operation: "GET /api/v1/locations/nearby", method: "GET", target: "/api/v1/locations/nearby?lat=37.94&lon=27.34&radius_km=5", status: 200},
+		{operation: "GET /api/v1/locations/nearby", method: "GET", target: "/api/v1/locations/nearby?location_id=10", status: 200},
+		{operation: "GET /api/v1/admin/users/{user_id}/export", method: "GET", target: "/api/v1/admin/users/user-1/export", admin: true, status: 200},
+		{operation: "GET /api/v1/admin/users/{user_id}/export", method: "GET", target: "/api/v1/admin/users/user-1/export", status: 401},
+		{operation: "DELETE /api/v1/admin/users/{user_id}", method: "DELETE", target: "/api/v1/admin/users/user-1", admin: true, status: 200},
+		{operation: "POST /api/v1/admin/locations/merge", method: "POST", target: "/api/v1/admin/locations/merge", admin: true,
+			json: `{"canonical_id":10,"duplicate_ids":[13]}`, status: 200},
+		{operation: "PUT /api/v1/admin/locations/{id}/catalogue", method: "PUT", target: "/api/v1/admin/locations/10/catalogue", admin: true, status: 200},
+		{operation: "DELETE /api/v1/admin/locations/{id}/catalogue", method: "DELETE", target: "/api/v1/admin/locations/10/catalogue", admin: true, status: 200},
+		{operation: "GET /api/v1/admin/debug/vars", method: "GET", target: "/api/v1/admin/debug/vars", admin: true, status: 200},
+		{operation: "GET /openapi.json", method: "GET", target: "/openapi.json", status: 200},
+		{operation: "GET /docs", method: "GET", target: "/docs", status: 200},
+	}
+
+	covered := map[string]bool{}
+	for _, tc := range cases {
+		covered[tc.operation] = true
+
+		t.Run(tc.method+" "+tc.target, func(t *testing.T) {
+			resp, err := app.Test(newRouteRequest(t, tc), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tc.status, body)
+			}
+		})
+	}
+
+	for _, operation := range documentedOperations(t, spec) {
+		if !covered[operation] {
+			t.Errorf("%s is documented but not exercised by this test", operation)
+		}
+	}
+}
+
+// TestUndocumentedRouteIsDrift dokümana eklenmemiş bir route'un yanıt
+// doğrulamasında yakalandığını kontrol eder.
+func TestUndocumentedRouteIsDrift(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.Get("/api/v1/trip/:id/undocumented", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"ok": true})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/trip/1/undocumented", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != fiber.StatusInternalServerError || !strings.Contains(string(body), "not documented in openapi.json") {
+		t.Errorf("status = %d, body = %s; want drift error", resp.StatusCode, body)
+	}
+}
+
+func newRouteRequest(t *testing.T, tc routeCase) *http.Request {
+	t.Helper()
+
+	var (
+		body        io.Reader
+		contentType string
+	)
+	switch {
+	case tc.files != nil:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for name, content := range tc.files {
+			part, err := writer.CreateFormFile("file", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := part.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for key, value := range tc.form {
+			if err := writer.WriteField(key, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		body, contentType = &buf, writer.FormDataContentType()
+	case tc.json != "":
+		body, contentType = strings.NewReader(tc.json), fiber.MIMEApplicationJSON
+	}
+
+	req := httptest.NewRequest(tc.method, tc.target, body)
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+	if tc.accept != "" {
+		req.Header.Set(fiber.HeaderAccept, tc.accept)
+	}
+	if tc.admin {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testAdminToken)
+	}
+	return req
+}
+
+// documentedOperations openapi.json'daki tüm operasyonları "METHOD /yol"
+// biçiminde döner.
+func documentedOperations(t *testing.T, spec *openapi.Spec) []string {
+	t.Helper()
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec.JSON(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var operations []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			switch method {
+			case "get", "post", "put", "patch", "delete":
+				operations = append(operations, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
@@ -12,11 +12,38 @@ func TripRoutes(router fiber.Router, handler handler.TripHandlerInterface) {
 	api := router.Group("/api/v1/trip")
 
 	// Mevcut endpoint'ler
-	api.Post("/preview", handler.NewCreateTripHandler) 
+	api.Post("/preview", handler.NewCreateTripHandler)
 	api.Post("/save", handler.SaveTripHandler)
-	
+	api.Post("/import", handler.ImportTripHandler) // GPX/KML dosyasından trip oluştur
+
 	// YENİ endpoint'ler
-	api.Get("/list", handler.GetUserTripsHandler)        // Kullanıcı triplerini listele
-	api.Get("/:id", handler.GetTripByIDHandler)          // ID'ye göre trip getir
-	api.Delete("/:id", handler.DeleteTripHandler)        // Trip sil
-}
\ No newline at end of file
+	api.Get("/list", handler.GetUserTripsHandler)   // Kullanıcı triplerini listele
+	api.Get("/in-area", handler.TripsInAreaHandler) // Durağı ?bbox= alanında kalan tripler
+	api.Get("/:id", handler.GetTripByIDHandler)     // ID'ye göre trip getir
+	api.Delete("/:id", handler.DeleteTripHandler)   // Trip sil
+
+	// Dışa aktarma
+	api.Get("/:id/export.gpx", handler.ExportGPXHandler)    // GPX 1.1
+	api.Get("/:id/export.ics", handler.ExportICSHandler)    // iCalendar
+	api.Get("/:id/geojson", handler.GeoJSONHandler)         // Harita arayüzü için GeoJSON
+	api.Get("/:id/export.zip", handler.ExportBundleHandler) // Çevrimdışı paket (JSON, GPX, ICS, GeoJSON, HTML)
+	api.Get("/:id/export.csv", handler.ExportCSVHandler)    // Lokasyon listesi
+
+	// Plan kalitesi (günlük yol, denge, geri dönüş, tekrar, boş gün)
+	api.Get("/:id/analysis", handler.AnalyzeTripHandler)
+
+	// Lokasyonları CSV ile toplu değiştir
+	api.Post("/:id/locations/import", handler.ImportLocationsCSVHandler)
+
+	// Durak sırasını en kısa rotaya göre düzenle (önizleme ya da ?apply=true)
+	api.Post("/:id/optimize", handler.OptimizeTripHandler)
+
+	// Lokasyonları günlere dağıt; sabitlenenler yerinde kalır
+	api.Post("/:id/assign-days", handler.AssignDaysHandler)
+	api.Put("/:id/locations/:location_id/pin", handler.PinLocationHandler)
+	api.Delete("/:id/locations/:location_id/pin", handler.UnpinLocationHandler)
+
+	// Takvim aboneliği (webcal)
+	api.Post("/calendar/feed", handler.CreateCalendarFeedHandler)     // Kullanıcının feed linkini oluştur/yenile
+	api.Get("/calendar/:token/feed.ics", handler.CalendarFeedHandler) // Yaklaşan tripler
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"trip-plan-service/internal/models"
+)
+
+// AccountExport bir kullanıcıya ait, veritabanında tutulan tüm kayıtlardır.
+// Plan önizlemeleri (/preview) ve audit kayıtları veritabanında
+// saklanmadığı için arşivde yer almaz.
+type AccountExport struct {
+	UserID       string                     `json:"user_id"`
+	ExportedAt   time.Time                  `json:"exported_at"`
+	Trips        []models.TripWithLocations `json:"trips"`
+	CalendarFeed *CalendarFeedExport        `json:"calendar_feed"`
+}
+
+type CalendarFeedExport struct {
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountDeletionReport silinen kayıt sayılarını ve silme sonrası yapılan
+// kontrolün sonucunu içerir.
+type AccountDeletionReport struct {
+	UserID  string         `json:"user_id"`
+	Deleted DeletionCounts `json:"deleted"`
+	// SharedLocations başka kullanıcıların trip'lerinde kullanıldığı, o an
+	// bir trip'e bağlandığı ya da katalogda olduğu için silinmeyen lokasyon
+	// sayısıdır.
+	SharedLocations int                  `json:"shared_locations"`
+	Verification    DeletionVerification `json:"verification"`
+}
+
+type DeletionCounts struct {
+	Trips              int64 `json:"trips"`
+	TripLocations      int64 `json:"trip_locations"`
+	Locations          int64 `json:"locations"`
+	CalendarFeedTokens int64 `json:"calendar_feed_tokens"`
+}
+
+// DeletionVerification silme transaction'ı commit edilmeden önce kullanıcıya
+// ait bir şey kalıp kalmadığını kontrol eder.
+type DeletionVerification struct {
+	Verified               bool  `json:"verified"`
+	RemainingTrips         int64 `json:"remaining_trips"`
+	RemainingOrphans       int64 `json:"remaining_orphaned_locations"`
+	RemainingCalendarToken bool  `json:"remaining_calendar_feed_token"`
+}
+
+// ExportAccount kullanıcının tüm trip'lerini, lokasyonlarını ve takvim
+// feed token'ını döner.
+func (s *TripService) ExportAccount(ctx context.Context, userID string) (*AccountExport, error) {
+	trips, err := s.GetUserTrips(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if trips == nil {
+		trips = []models.TripWithLocations{}
+	}
+
+	result := &AccountExport{
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		Trips:      trips,
+	}
+
+	token, err := s.Queries.GetCalendarTokenByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		result.CalendarFeed = &CalendarFeedExport{Token: token.Token, CreatedAt: token.CreatedAt.Time}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteAccount kullanıcının trip'lerini, trip-lokasyon bağlantılarını,
+// böylece sahipsiz kalan lokasyonları ve takvim token'ını tek transaction
+// içinde siler. Commit'ten önce hiçbir kayıt kalmadığı doğrulanır; kalırsa
+// transaction geri alınır ve hata döner.
+func (s *TripService) DeleteAccount(ctx context.Context, userID string) (*AccountDeletionReport, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+	report := &AccountDeletionReport{UserID: userID}
+
+	locationIDs, err := qtx.ListLocationIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Deleted.TripLocations, err = qtx.DeleteTripLocationsByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if report.Deleted.Trips, err = qtx.DeleteTripsByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if report.Deleted.Locations, err = qtx.DeleteOrphanedLocations(ctx, locationIDs); err != nil {
+		return nil, err
+	}
+	if report.Deleted.CalendarFeedTokens, err = qtx.DeleteCalendarTokenByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	report.SharedLocations = len(locationIDs) - int(report.Deleted.Locations)
+
+	verification := &report.Verification
+	if verification.RemainingTrips, err = qtx.CountTripsByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if verification.RemainingOrphans, err = qtx.CountOrphanedLocations(ctx, locationIDs); err != nil {
+		return nil, err
+	}
+	_, err = qtx.GetCalendarTokenByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		verification.RemainingCalendarToken = true
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	verification.Verified = verification.RemainingTrips == 0 &&
+		verification.RemainingOrphans == 0 &&
+		!verification.RemainingCalendarToken
+	if !verification.Verified {
+		return report, fmt.Errorf("account deletion for %s could not be verified, rolled back: %+v", userID, *verification)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
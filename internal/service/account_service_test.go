@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"trip-plan-service/internal/dbtest"
+)
+
+var testCreatedAt = time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+
+// accountDB user-1'in 10, 11 ve 12 numaralı lokasyonları kullanan
+// trip'lerini tutan sahte veritabanıdır. 12 başka bir kullanıcının
+// trip'inde de kullanıldığı için DeleteOrphanedLocations onu silmez.
+func accountDB() *dbtest.DB {
+	return &dbtest.DB{
+		Rows: map[string]dbtest.RowsFunc{
+			"ListLocationIDsByUserID": func([]driver.Value) [][]driver.Value {
+				return [][]driver.Value{{int64(10)}, {int64(11)}, {int64(12)}}
+			},
+			"CountTripsByUserID":       dbtest.Count(0),
+			"CountOrphanedLocations":   dbtest.Count(0),
+			"GetCalendarTokenByUserID": dbtest.NoRows,
+		},
+		Affected: map[string]func([]driver.Value) int64{
+			"DeleteTripLocationsByUserID": func([]driver.Value) int64 { return 4 },
+			"DeleteTripsByUserID":         func([]driver.Value) int64 { return 2 },
+			"DeleteOrphanedLocations":     func([]driver.Value) int64 { return 2 },
+			"DeleteCalendarTokenByUserID": func([]driver.Value) int64 { return 1 },
+		},
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	fake := accountDB()
+	s := NewTripService(nil, fake.Open(), nil)
+
+	report, err := s.DeleteAccount(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := DeletionCounts{Trips: 2, TripLocations: 4, Locations: 2, CalendarFeedTokens: 1}
+	if report.Deleted != want {
+		t.Errorf("Deleted = %+v, want %+v", report.Deleted, want)
+	}
+	// Paylaşılan lokasyon silinmez, raporda ayrıca sayılır
+	if report.SharedLocations != 1 {
+		t.Errorf("SharedLocations = %d, want 1", report.SharedLocations)
+	}
+	if !report.Verification.Verified {
+		t.Errorf("Verification = %+v, want verified", report.Verification)
+	}
+	if fake.Commits() != 1 {
+		t.Errorf("commits = %d, want 1", fake.Commits())
+	}
+
+	// Bağlantılar trip'lerden, sahipsiz lokasyonlar bağlantılardan sonra
+	// silinmeli; yalnızca kullanıcının lokasyonları aday olmalı.
+	var order []string
+	for _, call := range fake.Calls() {
+		switch call.Name {
+		case "DeleteTripLocationsByUserID", "DeleteTripsByUserID", "DeleteOrphanedLocations":
+			order = append(order, call.Name)
+		}
+		if call.Name == "DeleteOrphanedLocations" && call.Args[0] != "{10,11,12}" {
+			t.Errorf("DeleteOrphanedLocations args = %v, want the user's locations", call.Args)
+		}
+	}
+	if len(order) != 3 || order[0] != "DeleteTripLocationsByUserID" || order[1] != "DeleteTripsByUserID" || order[2] != "DeleteOrphanedLocations" {
+		t.Errorf("delete order = %v", order)
+	}
+}
+
+func TestDeleteAccountRollsBackWhenVerificationFails(t *testing.T) {
+	tests := map[string]func(*dbtest.DB){
+		"remaining_trip": func(fake *dbtest.DB) {
+			fake.Rows["CountTripsByUserID"] = dbtest.Count(1)
+		},
+		"remaining_orphan": func(fake *dbtest.DB) {
+			fake.Rows["CountOrphanedLocations"] = dbtest.Count(1)
+		},
+		"remaining_calendar_token": func(fake *dbtest.DB) {
+			fake.Rows["GetCalendarTokenByUserID"] = func(args []driver.Value) [][]driver.Value {
+				return [][]driver.Value{{args[0], "token", testCreatedAt}}
+			}
+		},
+	}
+
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := accountDB()
+			setup(fake)
+			s := NewTripService(nil, fake.Open(), nil)
+
+			report, err := s.DeleteAccount(context.Background(), "user-1")
+			if err == nil {
+				t.Fatal("DeleteAccount succeeded, want error")
+			}
+			if report == nil || report.Verification.Verified {
+				t.Fatalf("report = %+v, want an unverified report", report)
+			}
+			if fake.Commits() != 0 || fake.Rollbacks() != 1 {
+				t.Errorf("commits = %d, rollbacks = %d, want a rollback only", fake.Commits(), fake.Rollbacks())
+			}
+		})
+	}
+}
+
+func TestExportAccount(t *testing.T) {
+	tripRow := []driver.Value{int64(1), "user-1", "Ege turu", "Yaz tatili",
+		time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC),
+		"İzmir", "Bodrum", testCreatedAt, testCreatedAt, nil, nil, nil, nil}
+	fake := &dbtest.DB{Rows: map[string]dbtest.RowsFunc{
+		"ListTripsByUserID": func([]driver.Value) [][]driver.Value { return [][]driver.Value{tripRow} },
+		"GetTripLocations": func([]driver.Value) [][]driver.Value {
+			return [][]driver.Value{{int64(10), "Efes Antik Kenti", nil, nil, "Sabah erken gidin", "37.939500", "27.341700",
+				testCreatedAt, "TR", "İzmir", int64(1), int64(1), false, nil}}
+		},
+		"GetTripWaypoints": dbtest.NoRows,
+		"GetCalendarTokenByUserID": func(args []driver.Value) [][]driver.Value {
+			return [][]driver.Value{{args[0], "token-1", testCreatedAt}}
+		},
+	}}
+
+	archive, err := NewTripService(nil, fake.Open(), nil).ExportAccount(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Trips) != 1 || len(archive.Trips[0].Locations) != 1 {
+		t.Fatalf("archive = %+v, want one trip with one location", archive)
+	}
+	if notes := archive.Trips[0].Locations[0].Notes; notes == nil || *notes != "Sabah erken gidin" {
+		t.Errorf("notes = %v, want the trip's note", notes)
+	}
+	if archive.CalendarFeed == nil || archive.CalendarFeed.Token != "token-1" {
+		t.Errorf("CalendarFeed = %+v", archive.CalendarFeed)
+	}
+
+	// Trip'i ve token'ı olmayan kullanıcı için boş ama geçerli arşiv
+	fake.Rows["ListTripsByUserID"] = dbtest.NoRows
+	fake.Rows["GetCalendarTokenByUserID"] = dbtest.NoRows
+	archive, err = NewTripService(nil, fake.Open(), nil).ExportAccount(context.Background(), "user-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Trips == nil || len(archive.Trips) != 0 || archive.CalendarFeed != nil {
+		t.Errorf("archive = %+v, want no trips and no calendar feed", archive)
+	}
+}
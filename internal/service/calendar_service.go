@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/models"
+)
+
+// GetUpcomingTrips kullanıcının bitiş tarihi geçmemiş trip'lerini başlangıç
+// tarihine göre sıralı döner.
+func (s *TripService) GetUpcomingTrips(ctx context.Context, userID string) ([]models.TripWithLocations, error) {
+	trips, err := s.Queries.ListUpcomingTripsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.TripWithLocations
+	for _, trip := range trips {
+		locationsDB, err := s.Queries.GetTripLocations(ctx, trip.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, models.TripWithLocations{
+			Trip: models.Trip{
+				ID:            int(trip.ID),
+				UserID:        trip.UserID,
+				Name:          trip.Name,
+				Description:   trip.Description.String,
+				StartDate:     trip.StartDate.Format("2006-01-02"),
+				EndDate:       trip.EndDate.Format("2006-01-02"),
+				TotalDays:     totalDays(trip.StartDate, trip.EndDate),
+				CreatedAt:     trip.CreatedAt.Time,
+				UpdatedAt:     trip.UpdatedAt.Time,
+				StartPosition: trip.StartPosition.String,
+				EndPosition:   trip.EndPosition.String,
+			},
+			Locations: tripLocationsToModel(locationsDB, trip.StartDate),
+		})
+	}
+
+	return result, nil
+}
+
+// CalendarToken kullanıcının takvim feed token'ını döner. Token yoksa ya da
+// rotate true ise yeni bir token üretilir ve eski URL geçersiz olur.
+func (s *TripService) CalendarToken(ctx context.Context, userID string, rotate bool) (string, error) {
+	if !rotate {
+		existing, err := s.Queries.GetCalendarTokenByUserID(ctx, userID)
+		if err == nil {
+			return existing.Token, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	token, err := s.Queries.UpsertCalendarToken(ctx, db.UpsertCalendarTokenParams{
+		UserID: userID,
+		Token:  hex.EncodeToString(buf),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.Token, nil
+}
+
+// UserIDForCalendarToken feed token'ının sahibini bulur; bilinmeyen token
+// için sql.ErrNoRows döner.
+func (s *TripService) UserIDForCalendarToken(ctx context.Context, token string) (string, error) {
+	row, err := s.Queries.GetCalendarTokenByToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return row.UserID, nil
+}
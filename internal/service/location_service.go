@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+
+	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// LocationMatchRadiusKm aynı adı taşıyan iki lokasyonun aynı yer sayılması
+// için aralarındaki en fazla mesafedir.
+const LocationMatchRadiusKm = 0.2
+
+// ErrUnknownLocation birleştirilecek lokasyonlardan biri bulunamadığında döner.
+var ErrUnknownLocation = errors.New("location does not exist")
+
+// CatalogueLocation katalogdaki bir lokasyon ve onu kullanan trip sayısıdır.
+// InCatalogue true ise lokasyon hiçbir trip'te kullanılmasa da silinmez.
+// Katalog herkese açık olduğu için adres ve notlar doldurulmaz.
+type CatalogueLocation struct {
+	models.Location
+	InCatalogue bool  `json:"in_catalogue"`
+	TripCount   int64 `json:"trip_count"`
+}
+
+type LocationPage struct {
+	Total     int64               `json:"total"`
+	Limit     int                 `json:"limit"`
+	Offset    int                 `json:"offset"`
+	Locations []CatalogueLocation `json:"locations"`
+}
+
+// LocationMergeReport birleştirme sonucunu özetler.
+type LocationMergeReport struct {
+	CanonicalID int `json:"canonical_id"`
+	// Merged silinen kopya lokasyon sayısıdır.
+	Merged int64 `json:"merged"`
+	// Repointed kanonik lokasyona taşınan trip bağlantısı sayısıdır.
+	Repointed int64 `json:"repointed"`
+	// Collapsed aynı trip'te kanonik lokasyonla çakıştığı için silinen
+	// bağlantı sayısıdır; bu trip'lerde durak bir kez görünür.
+	Collapsed int64 `json:"collapsed"`
+}
+
+// findExistingLocation aynı normalize adı, adresi ve site adresini taşıyan ve
+// LocationMatchRadiusKm içinde kalan en yakın lokasyonu bulur. Adresi ya da
+// linki farklı olan satır yeniden kullanılmaz; aksi halde bir kullanıcının
+// girdiği bilgi başka bir kullanıcının trip'inde görünürdü. exclude içindeki
+// ID'ler atlanır. Koordinatı olmayan lokasyonlar hiçbir zaman eşleşmez.
+//
+// Seçilen satır FOR KEY SHARE ile kilitlenir; böylece sahipsiz lokasyon
+// temizliği onu trip'e bağlanmadan önce silemez. Bu arada silinmiş adaylar
+// atlanır ve bir sonraki en yakın aday denenir.
+func findExistingLocation(ctx context.Context, qtx *db.Queries, loc models.Location, exclude map[int32]bool) (int32, bool, error) {
+	point := geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude}
+	name := geo.NormalizeName(loc.Name)
+	address, siteURL := nullText(loc.Address), nullText(loc.SiteURL)
+	if point.IsZero() || name == "" {
+		return 0, false, nil
+	}
+
+	// Enlemde 1 derece ~111 km; boylam payı enleme göre genişler
+	latDelta := LocationMatchRadiusKm / 111.0
+	lonDelta := latDelta / math.Max(math.Cos(loc.Latitude*math.Pi/180), 0.01)
+
+	candidates, err := qtx.ListLocationsInBox(ctx, db.ListLocationsInBoxParams{
+		MinLatitude:  formatCoordinate(loc.Latitude - latDelta),
+		MaxLatitude:  formatCoordinate(loc.Latitude + latDelta),
+		MinLongitude: formatCoordinate(loc.Longitude - lonDelta),
+		MaxLongitude: formatCoordinate(loc.Longitude + lonDelta),
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	type match struct {
+		id   int32
+		dist float64
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		if exclude[candidate.ID] || geo.NormalizeName(candidate.Name) != name {
+			continue
+		}
+		if candidate.Address != address || candidate.SiteUrl != siteURL {
+			continue
+		}
+		other := geo.Point{Latitude: nullFloat(candidate.Latitude), Longitude: nullFloat(candidate.Longitude)}
+		if dist := geo.HaversineKm(point, other); dist <= LocationMatchRadiusKm {
+			matches = append(matches, match{id: candidate.ID, dist: dist})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+
+	for _, m := range matches {
+		_, err := qtx.LockLocationByID(ctx, m.id)
+		switch {
+		case err == nil:
+			return m.id, true, nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return 0, false, err
+		}
+	}
+	return 0, false, nil
+}
+
+// ListLocationCatalogue adında query geçen lokasyonları ada göre sıralı ve
+// sayfalı olarak döner. query boşsa tüm lokasyonlar listelenir.
+func (s *TripService) ListLocationCatalogue(ctx context.Context, query string, limit, offset int) (*LocationPage, error) {
+	total, err := s.Queries.CountLocations(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Queries.ListLocations(ctx, db.ListLocationsParams{
+		Query:      query,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LocationPage{
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+		Locations: []CatalogueLocation{},
+	}
+	for _, row := range rows {
+		page.Locations = append(page.Locations, CatalogueLocation{
+			Location: models.Location{
+				ID:          int(row.ID),
+				Name:        row.Name,
+				SiteURL:     nullString(row.SiteUrl),
+				Latitude:    nullFloat(row.Latitude),
+				Longitude:   nullFloat(row.Longitude),
+				CreatedAt:   row.CreatedAt.Time,
+				CountryCode: row.CountryCode.String,
+				AdminRegion: row.AdminRegion.String,
+			},
+			InCatalogue: row.InCatalogue,
+			TripCount:   row.TripCount,
+		})
+	}
+	return page, nil
+}
+
+// MergeLocations kopya lokasyonları kullanan tüm trip bağlantılarını kanonik
+// lokasyona taşır ve kopyaları siler. Bir trip hem kanonik lokasyonu hem bir
+// kopyayı içeriyorsa kopyanın bağlantısı silinir. Kopyalardan biri katalogdaysa
+// kanonik lokasyon da kataloğa alınır. Hepsi tek transaction'dır;
+// lokasyonlardan biri yoksa ErrUnknownLocation döner ve hiçbir şey değişmez.
+func (s *TripService) MergeLocations(ctx context.Context, canonicalID int, duplicateIDs []int) (*LocationMergeReport, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+
+	if _, err := qtx.GetLocationByID(ctx, int32(canonicalID)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownLocation
+		}
+		return nil, err
+	}
+
+	ids := make([]int32, len(duplicateIDs))
+	for i, id := range duplicateIDs {
+		ids[i] = int32(id)
+	}
+
+	report := &LocationMergeReport{CanonicalID: canonicalID}
+	if report.Collapsed, err = qtx.DeleteDuplicateTripLocations(ctx, db.DeleteDuplicateTripLocationsParams{
+		DuplicateIds: ids,
+		CanonicalID:  int32(canonicalID),
+	}); err != nil {
+		return nil, err
+	}
+	if report.Repointed, err = qtx.RepointTripLocations(ctx, db.RepointTripLocationsParams{
+		CanonicalID:  int32(canonicalID),
+		DuplicateIds: ids,
+	}); err != nil {
+		return nil, err
+	}
+	if err := qtx.CarryOverLocationInCatalogue(ctx, db.CarryOverLocationInCatalogueParams{
+		CanonicalID:  int32(canonicalID),
+		DuplicateIds: ids,
+	}); err != nil {
+		return nil, err
+	}
+	if report.Merged, err = qtx.DeleteLocationsByID(ctx, ids); err != nil {
+		return nil, err
+	}
+	if report.Merged != int64(len(ids)) {
+		return nil, ErrUnknownLocation
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// SetLocationInCatalogue lokasyonu kalıcı katalog kaydı yapar ya da bunu
+// geri alır. Katalog kayıtları sahipsiz lokasyon temizliğinde silinmez.
+func (s *TripService) SetLocationInCatalogue(ctx context.Context, locationID int, inCatalogue bool) error {
+	affected, err := s.Queries.SetLocationInCatalogue(ctx, db.SetLocationInCatalogueParams{
+		ID:          int32(locationID),
+		InCatalogue: inCatalogue,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUnknownLocation
+	}
+	return nil
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+func nullFloat(value sql.NullString) float64 {
+	if !value.Valid {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value.String, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func nullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// nullText boş metni NULL olarak yazar.
+func nullText(value *string) sql.NullString {
+	if value == nil || *value == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
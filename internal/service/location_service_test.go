@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/dbtest"
+	"trip-plan-service/internal/models"
+)
+
+// candidate ListLocationsInBox satırıdır; address ve siteURL nil olabilir.
+func candidate(id int64, name string, address, siteURL interface{}, lat, lon string) []driver.Value {
+	return []driver.Value{id, name, address, siteURL, lat, lon, testCreatedAt}
+}
+
+// locationDB verilen adayları dönen ve deleted içindeki ID'leri kilitlerken
+// silinmiş sayan sahte veritabanıdır.
+func locationDB(candidates [][]driver.Value, deleted ...int64) *dbtest.DB {
+	return &dbtest.DB{Rows: map[string]dbtest.RowsFunc{
+		"ListLocationsInBox": func([]driver.Value) [][]driver.Value { return candidates },
+		"LockLocationByID": func(args []driver.Value) [][]driver.Value {
+			for _, id := range deleted {
+				if args[0] == id {
+					return nil
+				}
+			}
+			return [][]driver.Value{{args[0]}}
+		},
+	}}
+}
+
+// Efes 37.9395, 27.3417; enlemde 0.0009 derece ~100 m'dir.
+func TestFindExistingLocation(t *testing.T) {
+	link := "https://muze.gov.tr/efes"
+	efes := models.Location{Name: "Efes", Latitude: 37.9395, Longitude: 27.3417, SiteURL: &link}
+
+	tests := []struct {
+		name       string
+		candidates [][]driver.Value
+		exclude    map[int32]bool
+		deleted    []int64
+		wantID     int32
+		wantFound  bool
+	}{
+		{
+			name:       "yarıçap içindeki aynı adlı lokasyon",
+			candidates: [][]driver.Value{candidate(5, " EFES ", nil, link, "37.940400", "27.341700")},
+			wantID:     5,
+			wantFound:  true,
+		},
+		{
+			name:       "yarıçap dışındaki lokasyon",
+			candidates: [][]driver.Value{candidate(5, "Efes", nil, link, "37.942200", "27.341700")},
+		},
+		{
+			name:       "farklı ad",
+			candidates: [][]driver.Value{candidate(5, "Efes Müzesi", nil, link, "37.939500", "27.341700")},
+		},
+		{
+			name:       "farklı adres",
+			candidates: [][]driver.Value{candidate(5, "Efes", "Selçuk, İzmir", link, "37.939500", "27.341700")},
+		},
+		{
+			name:       "farklı link",
+			candidates: [][]driver.Value{candidate(5, "Efes", nil, "https://efes.example", "37.939500", "27.341700")},
+		},
+		{
+			name:       "linki olmayan lokasyon",
+			candidates: [][]driver.Value{candidate(5, "Efes", nil, nil, "37.939500", "27.341700")},
+		},
+		{
+			name: "en yakın aday seçilir",
+			candidates: [][]driver.Value{
+				candidate(5, "Efes", nil, link, "37.940800", "27.341700"),
+				candidate(6, "Efes", nil, link, "37.939600", "27.341700"),
+			},
+			wantID:    6,
+			wantFound: true,
+		},
+		{
+			name: "hariç tutulan aday atlanır",
+			candidates: [][]driver.Value{
+				candidate(5, "Efes", nil, link, "37.940800", "27.341700"),
+				candidate(6, "Efes", nil, link, "37.939600", "27.341700"),
+			},
+			exclude:   map[int32]bool{6: true},
+			wantID:    5,
+			wantFound: true,
+		},
+		{
+			name: "kilitlenirken silinen aday atlanır",
+			candidates: [][]driver.Value{
+				candidate(5, "Efes", nil, link, "37.940800", "27.341700"),
+				candidate(6, "Efes", nil, link, "37.939600", "27.341700"),
+			},
+			deleted:   []int64{6},
+			wantID:    5,
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := locationDB(tt.candidates, tt.deleted...)
+			id, found, err := findExistingLocation(context.Background(), db.New(fake.Open()), efes, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.wantID || found != tt.wantFound {
+				t.Errorf("findExistingLocation = %d, %t; want %d, %t", id, found, tt.wantID, tt.wantFound)
+			}
+		})
+	}
+}
+
+// Kutu yarıçapı kapsar; koordinatı olmayan lokasyon için sorgu yapılmaz.
+func TestFindExistingLocationQuery(t *testing.T) {
+	fake := locationDB(nil)
+	queries := db.New(fake.Open())
+
+	if _, found, err := findExistingLocation(context.Background(), queries, models.Location{Name: "Efes"}, nil); err != nil || found {
+		t.Fatalf("found = %t, err = %v; want no match", found, err)
+	}
+	if n := fake.Called("ListLocationsInBox"); n != 0 {
+		t.Fatalf("ListLocationsInBox called %d times without coordinates", n)
+	}
+
+	if _, _, err := findExistingLocation(context.Background(), queries, models.Location{Name: "Efes", Latitude: 37.9395, Longitude: 27.3417}, nil); err != nil {
+		t.Fatal(err)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("calls = %+v, want one box query", calls)
+	}
+	want := []driver.Value{"37.937698", "37.941302", "27.339415", "27.343985"}
+	for i, arg := range calls[0].Args {
+		if arg != want[i] {
+			t.Errorf("box = %v, want %v", calls[0].Args, want)
+			break
+		}
+	}
+}
+
+func mergeDB() *dbtest.DB {
+	return &dbtest.DB{
+		Rows: map[string]dbtest.RowsFunc{
+			"GetLocationByID": func(args []driver.Value) [][]driver.Value {
+				return [][]driver.Value{{args[0], "Efes", nil, nil, "37.939500", "27.341700", testCreatedAt}}
+			},
+		},
+		Affected: map[string]func([]driver.Value) int64{
+			"DeleteDuplicateTripLocations": func([]driver.Value) int64 { return 2 },
+			"RepointTripLocations":         func([]driver.Value) int64 { return 3 },
+			"DeleteLocationsByID":          func([]driver.Value) int64 { return 2 },
+		},
+	}
+}
+
+func TestMergeLocations(t *testing.T) {
+	fake := mergeDB()
+	s := NewTripService(nil, fake.Open(), nil)
+
+	report, err := s.MergeLocations(context.Background(), 4, []int{7, 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LocationMergeReport{CanonicalID: 4, Merged: 2, Repointed: 3, Collapsed: 2}
+	if *report != want {
+		t.Errorf("report = %+v, want %+v", *report, want)
+	}
+	if fake.Commits() != 1 {
+		t.Errorf("commits = %d, want 1", fake.Commits())
+	}
+
+	// Çakışan bağlantılar taşımadan, katalog bayrağı kopyalar silinmeden önce
+	// aktarılmalı.
+	var order []string
+	for _, call := range fake.Calls() {
+		order = append(order, call.Name)
+		if call.Name == "CarryOverLocationInCatalogue" && (call.Args[0] != int64(4) || call.Args[1] != "{7,9}") {
+			t.Errorf("CarryOverLocationInCatalogue args = %v, want canonical 4 and duplicates {7,9}", call.Args)
+		}
+	}
+	wantOrder := []string{"GetLocationByID", "DeleteDuplicateTripLocations", "RepointTripLocations", "CarryOverLocationInCatalogue", "DeleteLocationsByID"}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("calls = %v, want %v", order, wantOrder)
+	}
+	for i := range order {
+		if order[i] != wantOrder[i] {
+			t.Fatalf("calls = %v, want %v", order, wantOrder)
+		}
+	}
+}
+
+func TestMergeLocationsUnknownLocation(t *testing.T) {
+	tests := map[string]func(*dbtest.DB){
+		"kanonik lokasyon yok": func(fake *dbtest.DB) {
+			fake.Rows["GetLocationByID"] = dbtest.NoRows
+		},
+		"kopyalardan biri yok": func(fake *dbtest.DB) {
+			fake.Affected["DeleteLocationsByID"] = func([]driver.Value) int64 { return 1 }
+		},
+	}
+
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := mergeDB()
+			setup(fake)
+			s := NewTripService(nil, fake.Open(), nil)
+
+			if _, err := s.MergeLocations(context.Background(), 4, []int{7, 9}); !errors.Is(err, ErrUnknownLocation) {
+				t.Errorf("err = %v, want ErrUnknownLocation", err)
+			}
+			if fake.Commits() != 0 || fake.Rollbacks() != 1 {
+				t.Errorf("commits = %d, rollbacks = %d; want a rollback", fake.Commits(), fake.Rollbacks())
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+
+	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+)
+
+// Yakınlık aramasında kullanılan yöntemler
+const (
+	SpatialBackendPostGIS = "postgis"
+	SpatialBackendBTree   = "btree"
+)
+
+// ErrLocationWithoutCoordinates koordinatı olmayan bir lokasyonun
+// çevresinde arama yapılmak istendiğinde döner.
+var ErrLocationWithoutCoordinates = errors.New("location has no coordinates")
+
+// postgis eklentinin kurulu olup olmadığını ilk başarılı kontrolden sonra
+// saklar; eklenti sonradan kurulursa servisin yeniden başlatılması gerekir.
+var postgis struct {
+	sync.Mutex
+	checked   bool
+	available bool
+}
+
+// NearbyLocation merkeze uzaklığıyla birlikte bir lokasyondur. Katalog gibi
+// herkese açık olduğu için adres ve notlar doldurulmaz.
+type NearbyLocation struct {
+	models.Location
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyResult yakınlık aramasının sonucudur. Backend aramanın PostGIS ile
+// mi yoksa B-tree index'i ve haversine süzmesiyle mi yapıldığını gösterir.
+type NearbyResult struct {
+	Center    geo.Point        `json:"center"`
+	RadiusKm  float64          `json:"radius_km"`
+	Backend   string           `json:"backend"`
+	Locations []NearbyLocation `json:"locations"`
+}
+
+// TripInArea alan içinde en az bir lokasyonu olan bir trip'tir.
+type TripInArea struct {
+	models.Trip
+	MatchingLocations int64 `json:"matching_locations"`
+}
+
+// NearbyLocations center'a radiusKm içinde kalan lokasyonları yakından uzağa
+// en fazla limit tane döner. excludeID verilirse o lokasyon sonuçta yer almaz.
+func (s *TripService) NearbyLocations(ctx context.Context, center geo.Point, radiusKm float64, excludeID, limit int) (*NearbyResult, error) {
+	result := &NearbyResult{Center: center, RadiusKm: radiusKm, Locations: []NearbyLocation{}}
+
+	available, err := s.hasPostGIS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if available {
+		result.Backend = SpatialBackendPostGIS
+		rows, err := s.Queries.ListLocationsNearGeography(ctx, db.ListLocationsNearGeographyParams{
+			Latitude:  center.Latitude,
+			Longitude: center.Longitude,
+			RadiusKm:  radiusKm,
+			ExcludeID: int32(excludeID),
+			Limit:     int32(limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			result.Locations = append(result.Locations, NearbyLocation{
+				Location: models.Location{
+					ID:        int(row.ID),
+					Name:      row.Name,
+					SiteURL:   nullString(row.SiteUrl),
+					Latitude:  nullFloat(row.Latitude),
+					Longitude: nullFloat(row.Longitude),
+					CreatedAt: row.CreatedAt.Time,
+				},
+				DistanceKm: row.DistanceKm,
+			})
+		}
+		return result, nil
+	}
+
+	// PostGIS yoksa kutular (latitude, longitude) index'iyle taranır, kesin
+	// mesafe uygulamada hesaplanır
+	result.Backend = SpatialBackendBTree
+	for _, box := range geo.BoxesAround(center, radiusKm) {
+		rows, err := s.Queries.ListLocationsInBox(ctx, db.ListLocationsInBoxParams{
+			MinLatitude:  formatCoordinate(box.MinLatitude),
+			MaxLatitude:  formatCoordinate(box.MaxLatitude),
+			MinLongitude: formatCoordinate(box.MinLongitude),
+			MaxLongitude: formatCoordinate(box.MaxLongitude),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if int(row.ID) == excludeID {
+				continue
+			}
+			loc := models.Location{
+				ID:        int(row.ID),
+				Name:      row.Name,
+				SiteURL:   nullString(row.SiteUrl),
+				Latitude:  nullFloat(row.Latitude),
+				Longitude: nullFloat(row.Longitude),
+				CreatedAt: row.CreatedAt.Time,
+			}
+			dist := geo.HaversineKm(center, geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude})
+			if dist <= radiusKm {
+				result.Locations = append(result.Locations, NearbyLocation{Location: loc, DistanceKm: dist})
+			}
+		}
+	}
+
+	sort.SliceStable(result.Locations, func(i, j int) bool {
+		a, b := result.Locations[i], result.Locations[j]
+		if a.DistanceKm != b.DistanceKm {
+			return a.DistanceKm < b.DistanceKm
+		}
+		return a.ID < b.ID
+	})
+	if len(result.Locations) > limit {
+		result.Locations = result.Locations[:limit]
+	}
+	return result, nil
+}
+
+// NearbyLocationsOf verilen lokasyonun çevresindeki diğer lokasyonları döner.
+// Lokasyon yoksa ErrUnknownLocation, koordinatı yoksa
+// ErrLocationWithoutCoordinates döner.
+func (s *TripService) NearbyLocationsOf(ctx context.Context, locationID int, radiusKm float64, limit int) (*NearbyResult, error) {
+	loc, err := s.Queries.GetLocationByID(ctx, int32(locationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUnknownLocation
+		}
+		return nil, err
+	}
+
+	center := geo.Point{Latitude: nullFloat(loc.Latitude), Longitude: nullFloat(loc.Longitude)}
+	if center.IsZero() {
+		return nil, ErrLocationWithoutCoordinates
+	}
+	return s.NearbyLocations(ctx, center, radiusKm, locationID, limit)
+}
+
+// TripsInArea kullanıcının en az bir lokasyonu box içinde kalan trip'lerini
+// döner. Dikdörtgen araması için B-tree index'i yeterli olduğundan PostGIS
+// kullanılmaz. box.MinLongitude > box.MaxLongitude ise kutu 180. meridyeni
+// geçer.
+func (s *TripService) TripsInArea(ctx context.Context, userID string, box geo.BBox) ([]TripInArea, error) {
+	rows, err := s.Queries.ListUserTripsInBox(ctx, db.ListUserTripsInBoxParams{
+		UserID:       userID,
+		MinLatitude:  formatCoordinate(box.MinLatitude),
+		MaxLatitude:  formatCoordinate(box.MaxLatitude),
+		MinLongitude: formatCoordinate(box.MinLongitude),
+		MaxLongitude: formatCoordinate(box.MaxLongitude),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trips := []TripInArea{}
+	for _, row := range rows {
+		trips = append(trips, TripInArea{
+			Trip: models.Trip{
+				ID:            int(row.ID),
+				UserID:        row.UserID,
+				Name:          row.Name,
+				Description:   row.Description.String,
+				StartDate:     row.StartDate.Format("2006-01-02"),
+				EndDate:       row.EndDate.Format("2006-01-02"),
+				TotalDays:     totalDays(row.StartDate, row.EndDate),
+				CreatedAt:     row.CreatedAt.Time,
+				UpdatedAt:     row.UpdatedAt.Time,
+				StartPosition: row.StartPosition.String,
+				EndPosition:   row.EndPosition.String,
+			},
+			MatchingLocations: row.MatchingLocations,
+		})
+	}
+	return trips, nil
+}
+
+func (s *TripService) hasPostGIS(ctx context.Context) (bool, error) {
+	postgis.Lock()
+	defer postgis.Unlock()
+
+	if !postgis.checked {
+		available, err := s.Queries.HasPostGIS(ctx)
+		if err != nil {
+			return false, err
+		}
+		postgis.checked, postgis.available = true, available
+	}
+	return postgis.available, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"strconv"
+	"testing"
+
+	"trip-plan-service/internal/dbtest"
+	"trip-plan-service/internal/geo"
+)
+
+// withoutPostGIS önbelleğe alınmış PostGIS kontrolünü test süresince sıfırlar.
+func withoutPostGIS(t *testing.T) {
+	t.Helper()
+	postgis.Lock()
+	postgis.checked, postgis.available = false, false
+	postgis.Unlock()
+	t.Cleanup(func() {
+		postgis.Lock()
+		postgis.checked, postgis.available = false, false
+		postgis.Unlock()
+	})
+}
+
+// boxDB PostGIS'i olmayan ve ListLocationsInBox'ta kutunun içindeki
+// lokasyonları dönen sahte veritabanıdır; satırlar ListLocationsInBox
+// kolonlarıdır.
+func boxDB(locations ...[]driver.Value) *dbtest.DB {
+	coordinate := func(value driver.Value) float64 {
+		f, _ := strconv.ParseFloat(value.(string), 64)
+		return f
+	}
+	return &dbtest.DB{Rows: map[string]dbtest.RowsFunc{
+		"HasPostGIS": func([]driver.Value) [][]driver.Value { return [][]driver.Value{{false}} },
+		"ListLocationsInBox": func(args []driver.Value) [][]driver.Value {
+			minLat, maxLat, minLon, maxLon := coordinate(args[0]), coordinate(args[1]), coordinate(args[2]), coordinate(args[3])
+			var rows [][]driver.Value
+			for _, loc := range locations {
+				lat, lon := coordinate(loc[4]), coordinate(loc[5])
+				if lat >= minLat && lat <= maxLat && lon >= minLon && lon <= maxLon {
+					rows = append(rows, loc)
+				}
+			}
+			return rows
+		},
+	}}
+}
+
+// Fiji çevresinde 50 km'lik arama 180. meridyeni geçer ve iki kutu taranır.
+// Köşedeki lokasyon kutunun içinde kalsa da yarıçapın dışındadır.
+func TestNearbyLocationsBTreeAcrossAntimeridian(t *testing.T) {
+	withoutPostGIS(t)
+	fake := boxDB(
+		candidate(1, "Batı Kıyısı", nil, nil, "-17.000000", "-179.800000"),
+		candidate(2, "Doğu Kıyısı", nil, nil, "-17.000000", "179.950000"),
+		candidate(3, "Köşe", nil, nil, "-17.400000", "-179.700000"),
+		candidate(4, "Merkez", nil, nil, "-17.000000", "179.900000"),
+		candidate(5, "Uzak", nil, nil, "-17.000000", "-179.000000"),
+	)
+	s := NewTripService(nil, fake.Open(), nil)
+
+	result, err := s.NearbyLocations(context.Background(), geo.Point{Latitude: -17, Longitude: 179.9}, 50, 4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Backend != SpatialBackendBTree {
+		t.Errorf("backend = %q, want %q", result.Backend, SpatialBackendBTree)
+	}
+	var boxes [][]driver.Value
+	for _, call := range fake.Calls() {
+		if call.Name == "ListLocationsInBox" {
+			boxes = append(boxes, call.Args)
+		}
+	}
+	if len(boxes) != 2 || boxes[0][3] != "180.000000" || boxes[1][2] != "-180.000000" {
+		t.Errorf("boxes = %v, want one query per side of the meridian", boxes)
+	}
+	var names []string
+	for _, loc := range result.Locations {
+		names = append(names, loc.Name)
+	}
+	if len(names) != 2 || names[0] != "Doğu Kıyısı" || names[1] != "Batı Kıyısı" {
+		t.Fatalf("locations = %v, want [Doğu Kıyısı Batı Kıyısı]", names)
+	}
+	// Boylamda 0.3 derece 17. enlemde ~32 km'dir
+	if km := result.Locations[1].DistanceKm; km < 31 || km > 33 {
+		t.Errorf("distance across the meridian = %.1f km, want about 32", km)
+	}
+}
+
+// Sonuçlar yakından uzağa, eşit mesafede ID'ye göre sıralanıp limit'e kesilir.
+func TestNearbyLocationsBTreeOrderAndLimit(t *testing.T) {
+	withoutPostGIS(t)
+	fake := boxDB(
+		candidate(9, "Uzak", nil, nil, "38.020000", "27.000000"),
+		candidate(8, "Kuzey", nil, nil, "38.010000", "27.000000"),
+		candidate(7, "Güney", nil, nil, "37.990000", "27.000000"),
+	)
+	s := NewTripService(nil, fake.Open(), nil)
+
+	result, err := s.NearbyLocations(context.Background(), geo.Point{Latitude: 38, Longitude: 27}, 5, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Locations) != 2 || result.Locations[0].ID != 7 || result.Locations[1].ID != 8 {
+		t.Errorf("locations = %+v, want ids 7 and 8", result.Locations)
+	}
+	if n := fake.Called("ListLocationsInBox"); n != 1 {
+		t.Errorf("ListLocationsInBox called %d times, want 1", n)
+	}
+}
@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 	db "trip-plan-service/internal/db/postgresql"
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/geocode"
 	"trip-plan-service/internal/models"
 )
 
@@ -36,6 +39,9 @@ func (s *TripService) SaveTripWLocations(ctx context.Context) error {
 
 	qtx := s.Queries.WithTx(tx)
 
+	startLat, startLon := pointColumns(s.TripSer.StartPoint)
+	endLat, endLon := pointColumns(s.TripSer.EndPoint)
+
 	trip, err := qtx.CreateTrip(ctx, db.CreateTripParams{
 		UserID: s.TripSer.UserID,
 		Name:   s.TripSer.Name,
@@ -59,76 +65,173 @@ func (s *TripService) SaveTripWLocations(ctx context.Context) error {
 			t, _ := time.Parse("2006-01-02", s.TripSer.EndDate)
 			return t
 		}(),
+		StartLatitude:  startLat,
+		StartLongitude: startLon,
+		EndLatitude:    endLat,
+		EndLongitude:   endLon,
 	})
 
 	if err != nil {
 		return err // Rollback defer ile yapılacak
 	}
+	s.TripSer.ID = int(trip.ID)
 
-	for i, loc := range s.Locations {
-		location, err := qtx.CreateLocation(ctx, db.CreateLocationParams{
-			Name: loc.Name,
-			Address: sql.NullString{
-				String: func() string {
-					if loc.Address != nil {
-						return *loc.Address
-					}
-					return ""
-				}(),
-				Valid: loc.Address != nil && *loc.Address != "",
-			},
-			Latitude: sql.NullString{
-				String: fmt.Sprintf("%f", loc.Latitude),
-				Valid:  true,
-			},
-			// EKLENDİ: Eksik olan Longitude parametresi eklendi.
-			Longitude: sql.NullString{
-				String: fmt.Sprintf("%f", loc.Longitude),
-				Valid:  true,
-			},
-			SiteUrl: sql.NullString{
-				String: func() string {
-					if loc.SiteURL != nil {
-						return *loc.SiteURL
-					}
-					return ""
-				}(),
-				Valid: loc.SiteURL != nil && *loc.SiteURL != "",
-			},
-			Notes: sql.NullString{
-				String: func() string {
-					if loc.Notes != nil {
-						return *loc.Notes
-					}
-					return ""
-				}(),
-				Valid: loc.Notes != nil && *loc.Notes != "",
-			},
-		})
+	if err := addWaypoints(ctx, qtx, trip.ID, s.TripSer.Waypoints); err != nil {
+		return err // Rollback defer ile yapılacak
+	}
+
+	if err := addLocations(ctx, qtx, trip.ID, s.Locations); err != nil {
+		return err // Rollback defer ile yapılacak
+	}
+
+	// Her şey başarılıysa Commit et
+	return tx.Commit()
+}
+
+// addLocations lokasyonları verilen sırayla trip'e bağlar. Aynı adla aynı
+// noktada zaten bir lokasyon varsa yenisi oluşturulmaz, o satır kullanılır.
+// Notlar trip'e özeldir ve bağlantıyla birlikte saklanır.
+// Transaction içindeki sorgularla çağrılmalıdır.
+func addLocations(ctx context.Context, qtx *db.Queries, tripID int32, locations []models.Location) error {
+	// Aynı trip'e bir lokasyon iki kez bağlanamaz (trip_id, location_id birincil anahtar)
+	used := map[int32]bool{}
+	for i, loc := range locations {
+		locationID, found, err := findExistingLocation(ctx, qtx, loc, used)
 		if err != nil {
-			return err // Rollback defer ile yapılacak
+			return err
+		}
+		if !found {
+			if locationID, err = createLocation(ctx, qtx, loc); err != nil {
+				return err
+			}
 		}
+		used[locationID] = true
 
 		// DÜZELTİLDİ: Fonksiyon adı `CreateTripLocation`'dan `AddLocationToTrip`'e çevrildi.
 		err = qtx.AddLocationToTrip(ctx, db.AddLocationToTripParams{
-			TripID:     trip.ID,
-			LocationID: location.ID,
+			TripID:     tripID,
+			LocationID: locationID,
 			Position:   int32(i + 1),
+			Day: sql.NullInt32{
+				Int32: int32(loc.Day),
+				Valid: loc.Day > 0,
+			},
+			// Gün atanmamış bir lokasyon sabitlenemez
+			Pinned: loc.Pinned && loc.Day > 0,
+			Waypoint: sql.NullInt32{
+				Int32: int32(loc.Waypoint),
+				Valid: loc.Waypoint > 0,
+			},
+			Notes: nullText(loc.Notes),
 		})
 		if err != nil {
-			return err // Rollback defer ile yapılacak
+			return err
 		}
 	}
+	return nil
+}
 
-	// Her şey başarılıysa Commit et
-	return tx.Commit()
+// addWaypoints ara noktaları verilen sırayla, pozisyonları 1'den başlayarak
+// kaydeder. Transaction içindeki sorgularla çağrılmalıdır.
+func addWaypoints(ctx context.Context, qtx *db.Queries, tripID int32, waypoints []models.Waypoint) error {
+	for i, waypoint := range waypoints {
+		latitude, longitude := pointColumns(waypoint.Point)
+		err := qtx.AddTripWaypoint(ctx, db.AddTripWaypointParams{
+			TripID:    tripID,
+			Position:  int32(i + 1),
+			Name:      waypoint.Name,
+			Latitude:  latitude,
+			Longitude: longitude,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *TripService) GetUserTrips(ctx context.Context, userID string) ([]models.TripWithLocations, error) {
-	trips, err := s.Queries.ListTripsByUserID(ctx, userID)
+// tripWaypoints trip'in ara noktalarını sırasıyla döner.
+func tripWaypoints(ctx context.Context, q *db.Queries, tripID int32) ([]models.Waypoint, error) {
+	rows, err := q.GetTripWaypoints(ctx, tripID)
 	if err != nil {
 		return nil, err
 	}
+	var waypoints []models.Waypoint
+	for _, row := range rows {
+		waypoints = append(waypoints, models.Waypoint{
+			Name:  row.Name,
+			Point: rowPoint(row.Latitude, row.Longitude),
+		})
+	}
+	return waypoints, nil
+}
+
+// createLocation yeni lokasyonu koordinatından bulunan ülke ve bölgeyle
+// birlikte kaydeder. Veri setinin dışında kalan noktalar etiketsiz kalır ve
+// backfill tarafından yeniden denenir.
+func createLocation(ctx context.Context, qtx *db.Queries, loc models.Location) (int32, error) {
+	region, tagged := geocode.Lookup(geo.Point{Latitude: loc.Latitude, Longitude: loc.Longitude})
+
+	location, err := qtx.CreateLocation(ctx, db.CreateLocationParams{
+		Name: loc.Name,
+		Address: sql.NullString{
+			String: func() string {
+				if loc.Address != nil {
+					return *loc.Address
+				}
+				return ""
+			}(),
+			Valid: loc.Address != nil && *loc.Address != "",
+		},
+		Latitude: sql.NullString{
+			String: fmt.Sprintf("%f", loc.Latitude),
+			Valid:  true,
+		},
+		// EKLENDİ: Eksik olan Longitude parametresi eklendi.
+		Longitude: sql.NullString{
+			String: fmt.Sprintf("%f", loc.Longitude),
+			Valid:  true,
+		},
+		SiteUrl: sql.NullString{
+			String: func() string {
+				if loc.SiteURL != nil {
+					return *loc.SiteURL
+				}
+				return ""
+			}(),
+			Valid: loc.SiteURL != nil && *loc.SiteURL != "",
+		},
+		CountryCode: sql.NullString{String: region.CountryCode, Valid: tagged},
+		AdminRegion: sql.NullString{String: region.AdminRegion, Valid: region.AdminRegion != ""},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return location.ID, nil
+}
+
+// GetUserTrips kullanıcının trip'lerini döner. countries verilirse yalnızca
+// bu ülkelerden en az birinde lokasyonu olan trip'ler listelenir.
+func (s *TripService) GetUserTrips(ctx context.Context, userID string, countries ...string) ([]models.TripWithLocations, error) {
+	var trips []db.ListTripsByUserIDRow
+	if len(countries) == 0 {
+		rows, err := s.Queries.ListTripsByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		trips = rows
+	} else {
+		rows, err := s.Queries.ListTripsByUserIDAndCountries(ctx, db.ListTripsByUserIDAndCountriesParams{
+			UserID:       userID,
+			CountryCodes: countries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			trips = append(trips, db.ListTripsByUserIDRow(row))
+		}
+	}
 
 	var result []models.TripWithLocations
 	for _, trip := range trips {
@@ -137,52 +240,13 @@ func (s *TripService) GetUserTrips(ctx context.Context, userID string) ([]models
 			return nil, err
 		}
 
-		var tripLocations []models.Location
-		for _, loc := range locationsDB {
-			tripLocation := models.Location{
-				ID:   int(loc.ID),
-				Name: loc.Name,
-				Latitude: func() float64 {
-					if loc.Latitude.Valid {
-						val, err := strconv.ParseFloat(loc.Latitude.String, 64)
-						if err == nil {
-							return val
-						}
-					}
-					return 0
-				}(),
-				Longitude: func() float64 {
-					if loc.Longitude.Valid {
-						val, err := strconv.ParseFloat(loc.Longitude.String, 64)
-						if err == nil {
-							return val
-						}
-					}
-					return 0
-				}(),
-				Address: func() *string {
-					if loc.Address.Valid {
-						return &loc.Address.String
-					}
-					return nil
-				}(),
-				SiteURL: func() *string {
-					if loc.SiteUrl.Valid {
-						return &loc.SiteUrl.String
-					}
-					return nil
-				}(),
-				Notes: func() *string {
-					if loc.Notes.Valid {
-						return &loc.Notes.String
-					}
-					return nil
-				}(),
-				CreatedAt: loc.CreatedAt.Time,
-			}
-			tripLocations = append(tripLocations, tripLocation)
+		waypoints, err := tripWaypoints(ctx, s.Queries, trip.ID)
+		if err != nil {
+			return nil, err
 		}
 
+		tripLocations := tripLocationsToModel(locationsDB, trip.StartDate)
+
 		tripWithLoc := models.TripWithLocations{
 			Trip: models.Trip{
 				ID:            int(trip.ID),
@@ -191,13 +255,18 @@ func (s *TripService) GetUserTrips(ctx context.Context, userID string) ([]models
 				Description:   trip.Description.String,
 				StartDate:     trip.StartDate.Format("2006-01-02"),
 				EndDate:       trip.EndDate.Format("2006-01-02"),
+				TotalDays:     totalDays(trip.StartDate, trip.EndDate),
 				CreatedAt:     trip.CreatedAt.Time,
 				UpdatedAt:     trip.UpdatedAt.Time,
 				StartPosition: trip.StartPosition.String,
 				// DÜZELTİLDİ: SQLC artık EndPosition olarak üretecek
 				EndPosition: trip.EndPosition.String,
+				StartPoint:  rowPoint(trip.StartLatitude, trip.StartLongitude),
+				EndPoint:    rowPoint(trip.EndLatitude, trip.EndLongitude),
+				Waypoints:   waypoints,
 			},
 			Locations: tripLocations,
+			Countries: tripCountries(tripLocations),
 		}
 		result = append(result, tripWithLoc)
 	}
@@ -205,6 +274,159 @@ func (s *TripService) GetUserTrips(ctx context.Context, userID string) ([]models
 	return result, nil
 }
 
+// ReplaceTripLocations trip'in tüm lokasyonlarını verilenlerle tek
+// transaction içinde değiştirir; herhangi bir adım hata verirse trip olduğu
+// gibi kalır. Trip yoksa sql.ErrNoRows döner. Silinen bağlantı sayısını döner.
+func (s *TripService) ReplaceTripLocations(ctx context.Context, tripID int32, locations []models.Location) (int64, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+
+	if _, err := qtx.GetTripByID(ctx, tripID); err != nil {
+		return 0, err
+	}
+
+	removed, err := qtx.RemoveAllLocationsFromTrip(ctx, tripID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := addLocations(ctx, qtx, tripID, locations); err != nil {
+		return 0, err
+	}
+
+	return removed, tx.Commit()
+}
+
+// ErrLocationOrderMismatch yeni sıradaki lokasyonlar trip'in mevcut
+// lokasyonlarıyla birebir aynı olmadığında döner (ör. arada trip değişmiştir).
+var ErrLocationOrderMismatch = errors.New("location order does not match the trip's current locations")
+
+// ReorderTripLocations trip'in lokasyonlarının pozisyonlarını verilen sıraya
+// göre 1'den başlayarak tek transaction içinde yeniden yazar. Gün atamaları
+// değişmez. Trip yoksa sql.ErrNoRows döner.
+func (s *TripService) ReorderTripLocations(ctx context.Context, tripID int32, locationIDs []int) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+
+	if _, err := qtx.GetTripByID(ctx, tripID); err != nil {
+		return err
+	}
+
+	if err := checkLocationOrder(ctx, qtx, tripID, locationIDs); err != nil {
+		return err
+	}
+
+	for i, id := range locationIDs {
+		if _, err := qtx.UpdateTripLocationPosition(ctx, db.UpdateTripLocationPositionParams{
+			TripID:     tripID,
+			LocationID: int32(id),
+			Position:   int32(i + 1),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ScheduleTripLocations lokasyonların günlerini ve pozisyonlarını verilen
+// sıraya göre tek transaction içinde yeniden yazar. Sabitleme bilgisi
+// değişmez. Trip yoksa sql.ErrNoRows döner.
+func (s *TripService) ScheduleTripLocations(ctx context.Context, tripID int32, locations []models.Location) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+
+	if _, err := qtx.GetTripByID(ctx, tripID); err != nil {
+		return err
+	}
+
+	locationIDs := make([]int, len(locations))
+	for i, loc := range locations {
+		locationIDs[i] = loc.ID
+	}
+	if err := checkLocationOrder(ctx, qtx, tripID, locationIDs); err != nil {
+		return err
+	}
+
+	for i, loc := range locations {
+		if _, err := qtx.UpdateTripLocationSchedule(ctx, db.UpdateTripLocationScheduleParams{
+			TripID:     tripID,
+			LocationID: int32(loc.ID),
+			Position:   int32(i + 1),
+			Day: sql.NullInt32{
+				Int32: int32(loc.Day),
+				Valid: loc.Day > 0,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// checkLocationOrder verilen ID'lerin trip'in mevcut lokasyonlarıyla
+// birebir aynı olduğunu kontrol eder.
+func checkLocationOrder(ctx context.Context, qtx *db.Queries, tripID int32, locationIDs []int) error {
+	current, err := qtx.GetTripLocations(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	if len(current) != len(locationIDs) {
+		return ErrLocationOrderMismatch
+	}
+	remaining := make(map[int]bool, len(current))
+	for _, loc := range current {
+		remaining[int(loc.ID)] = true
+	}
+	for _, id := range locationIDs {
+		if !remaining[id] {
+			return ErrLocationOrderMismatch
+		}
+		delete(remaining, id)
+	}
+	return nil
+}
+
+// ErrLocationNotInTrip lokasyon trip'e bağlı değilse döner.
+var ErrLocationNotInTrip = errors.New("location is not part of the trip")
+
+// SetLocationPin lokasyonu verilen güne sabitler. day 0 ise sabitleme
+// kaldırılır ve gün olduğu gibi kalır.
+func (s *TripService) SetLocationPin(ctx context.Context, tripID, locationID int32, day int) error {
+	affected, err := s.Queries.SetTripLocationPin(ctx, db.SetTripLocationPinParams{
+		Day: sql.NullInt32{
+			Int32: int32(day),
+			Valid: day > 0,
+		},
+		Pinned:     day > 0,
+		TripID:     tripID,
+		LocationID: locationID,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrLocationNotInTrip
+	}
+	return nil
+}
+
 func (s *TripService) DeleteTrip(ctx context.Context, tripID int32) error {
 	return s.Queries.DeleteTrip(ctx, tripID)
 }
@@ -220,6 +442,41 @@ func (s *TripService) GetTripByID(ctx context.Context, tripID int32) (*models.Tr
 		return nil, err
 	}
 
+	waypoints, err := tripWaypoints(ctx, s.Queries, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	tripLocations := tripLocationsToModel(locationsDB, trip.StartDate)
+
+	result := &models.TripWithLocations{
+		Trip: models.Trip{
+			ID:            int(trip.ID),
+			UserID:        trip.UserID,
+			Name:          trip.Name,
+			Description:   trip.Description.String,
+			StartDate:     trip.StartDate.Format("2006-01-02"),
+			EndDate:       trip.EndDate.Format("2006-01-02"),
+			TotalDays:     totalDays(trip.StartDate, trip.EndDate),
+			CreatedAt:     trip.CreatedAt.Time,
+			UpdatedAt:     trip.UpdatedAt.Time,
+			StartPosition: trip.StartPosition.String,
+			// DÜZELTİLDİ: SQLC artık EndPosition olarak üretecek
+			EndPosition: trip.EndPosition.String,
+			StartPoint:  rowPoint(trip.StartLatitude, trip.StartLongitude),
+			EndPoint:    rowPoint(trip.EndLatitude, trip.EndLongitude),
+			Waypoints:   waypoints,
+		},
+		Locations: tripLocations,
+		Countries: tripCountries(tripLocations),
+	}
+
+	return result, nil
+}
+
+// tripLocationsToModel DB satırlarını API modeline çevirir; gün bilgisi
+// varsa tarihi trip'in başlangıç tarihinden hesaplar.
+func tripLocationsToModel(locationsDB []db.GetTripLocationsRow, startDate time.Time) []models.Location {
 	var tripLocations []models.Location
 	for _, loc := range locationsDB {
 		tripLocation := models.Location{
@@ -261,27 +518,54 @@ func (s *TripService) GetTripByID(ctx context.Context, tripID int32) (*models.Tr
 				}
 				return nil
 			}(),
-			CreatedAt: loc.CreatedAt.Time,
+			CreatedAt:   loc.CreatedAt.Time,
+			CountryCode: loc.CountryCode.String,
+			AdminRegion: loc.AdminRegion.String,
 		}
+		if loc.Day.Valid {
+			tripLocation.Day = int(loc.Day.Int32)
+			tripLocation.Date = startDate.AddDate(0, 0, tripLocation.Day-1).Format("2006-01-02")
+		}
+		tripLocation.Pinned = loc.Pinned
+		tripLocation.Waypoint = int(loc.Waypoint.Int32)
 		tripLocations = append(tripLocations, tripLocation)
 	}
 
-	result := &models.TripWithLocations{
-		Trip: models.Trip{
-			ID:            int(trip.ID),
-			UserID:        trip.UserID,
-			Name:          trip.Name,
-			Description:   trip.Description.String,
-			StartDate:     trip.StartDate.Format("2006-01-02"),
-			EndDate:       trip.EndDate.Format("2006-01-02"),
-			CreatedAt:     trip.CreatedAt.Time,
-			UpdatedAt:     trip.UpdatedAt.Time,
-			StartPosition: trip.StartPosition.String,
-			// DÜZELTİLDİ: SQLC artık EndPosition olarak üretecek
-			EndPosition: trip.EndPosition.String,
-		},
-		Locations: tripLocations,
+	return tripLocations
+}
+
+// tripCountries lokasyonların ülke kodlarını ilk geçtikleri sırayla,
+// tekrarsız döner.
+func tripCountries(locations []models.Location) []string {
+	var countries []string
+	seen := map[string]bool{}
+	for _, loc := range locations {
+		if loc.CountryCode != "" && !seen[loc.CountryCode] {
+			seen[loc.CountryCode] = true
+			countries = append(countries, loc.CountryCode)
+		}
 	}
+	return countries
+}
 
-	return result, nil
+// pointColumns noktayı enlem/boylam kolon değerlerine çevirir; nokta yoksa
+// ikisi de NULL olur.
+func pointColumns(point *geo.Point) (latitude, longitude sql.NullString) {
+	if point == nil {
+		return sql.NullString{}, sql.NullString{}
+	}
+	return sql.NullString{String: formatCoordinate(point.Latitude), Valid: true},
+		sql.NullString{String: formatCoordinate(point.Longitude), Valid: true}
+}
+
+// rowPoint kolon değerlerinden noktayı kurar; ikisinden biri boşsa nil döner.
+func rowPoint(latitude, longitude sql.NullString) *geo.Point {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+	return &geo.Point{Latitude: nullFloat(latitude), Longitude: nullFloat(longitude)}
+}
+
+func totalDays(startDate, endDate time.Time) int {
+	return int(endDate.Sub(startDate).Hours()/24) + 1
 }
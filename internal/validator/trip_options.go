@@ -0,0 +1,257 @@
+// internal/validator/trip_options.go for trip-plan-service
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"trip-plan-service/internal/models"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+// HopelessResponseError AI yanıtında onarılabilir hiçbir seçenek kalmadığında döner.
+type HopelessResponseError struct {
+	Reasons []string
+}
+
+func (e *HopelessResponseError) Error() string {
+	return "validator: AI response has no usable trip option: " + strings.Join(e.Reasons, "; ")
+}
+
+// OptionReport bir seçenek üzerinde yapılan onarımları ve kalan sorunları listeler.
+type OptionReport struct {
+	Warnings []string `json:"warnings"`
+	// Waypoints onarılmış plandaki durak indeksinden (DailyPlan) o durağın
+	// karşıladığı ara noktanın 1'den başlayan sırasına eşlemedir.
+	Waypoints map[int]int `json:"-"`
+}
+
+func (r *OptionReport) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// ValidateTripOptions AI yanıtındaki her seçeneği orijinal isteğe göre kontrol
+// eder ve yanıtı yerinde onarır: istenen tarih aralığı dışındaki ve tekrarlanan
+// günleri atar, günleri tarihe göre yeniden numaralandırır, geçersiz URL'leri
+// siler ve total_days'i yeniden hesaplar. waypoints verilirse planın ara
+// noktalara istenen sırayla uğraması sağlanır. Kullanılamayan seçenekler
+// yanıttan çıkarılır; hiç seçenek kalmazsa *HopelessResponseError döner.
+//
+// Dönen raporlar resp.TripOptions ile aynı sıradadır.
+func ValidateTripOptions(req *proto.PromptRequest, resp *proto.TripOptionsResponse, waypoints []models.Waypoint) ([]OptionReport, error) {
+	if resp == nil || len(resp.TripOptions) == 0 {
+		return nil, &HopelessResponseError{Reasons: []string{"response contains no trip options"}}
+	}
+
+	startDate, startErr := time.Parse("2006-01-02", req.StartDate)
+	endDate, endErr := time.Parse("2006-01-02", req.EndDate)
+	hasRange := startErr == nil && endErr == nil && !endDate.Before(startDate)
+
+	var (
+		options []*proto.TripOption
+		reports []OptionReport
+		reasons []string
+	)
+
+	for i, option := range resp.TripOptions {
+		if option == nil {
+			reasons = append(reasons, fmt.Sprintf("option %d is empty", i+1))
+			continue
+		}
+
+		var report OptionReport
+		validateTrip(req, option, &report)
+
+		if hasRange {
+			option.DailyPlan = validateDays(option.DailyPlan, startDate, endDate, &report)
+			option.Trip.TotalDays = int32(endDate.Sub(startDate).Hours()/24) + 1
+		} else {
+			report.warn("request dates are invalid, days were not checked against the trip range")
+			option.DailyPlan = renumberSequentially(option.DailyPlan)
+			option.Trip.TotalDays = int32(countDays(option.DailyPlan))
+		}
+
+		located := 0
+		for _, dailyPlan := range option.DailyPlan {
+			if dailyPlan.Location != nil {
+				validateLocation(dailyPlan, &report)
+				located++
+			}
+		}
+
+		if located == 0 {
+			reasons = append(reasons, fmt.Sprintf("option %d (%q) has no usable stops", i+1, option.Theme))
+			continue
+		}
+
+		if len(waypoints) > 0 {
+			report.Waypoints = validateWaypoints(option, waypoints, &report)
+		}
+
+		options = append(options, option)
+		reports = append(reports, report)
+	}
+
+	if len(options) == 0 {
+		return nil, &HopelessResponseError{Reasons: reasons}
+	}
+
+	resp.TripOptions = options
+	return reports, nil
+}
+
+// validateTrip seçeneğin trip bilgisini istekle uyumlu hale getirir.
+func validateTrip(req *proto.PromptRequest, option *proto.TripOption, report *OptionReport) {
+	if option.Trip == nil {
+		report.warn("trip details were missing and were copied from the request")
+		option.Trip = &proto.Trip{}
+	}
+
+	trip := option.Trip
+	fill := func(field string, value *string, want string) {
+		if *value == want || want == "" {
+			return
+		}
+		if *value != "" {
+			report.warn("%s %q did not match the request and was replaced with %q", field, *value, want)
+		}
+		*value = want
+	}
+
+	fill("user_id", &trip.UserId, req.UserId)
+	fill("start_date", &trip.StartDate, req.StartDate)
+	fill("end_date", &trip.EndDate, req.EndDate)
+	fill("start_position", &trip.StartPosition, req.StartPosition)
+	fill("end_position", &trip.EndPosition, req.EndPosition)
+	if trip.Name == "" {
+		trip.Name = req.Name
+	}
+}
+
+// validateDays aralık dışı ve tekrarlanan günleri atar, günü tarihten
+// yeniden hesaplar ve planı gün sırasına dizer.
+func validateDays(plan []*proto.DailyPlan, startDate, endDate time.Time, report *OptionReport) []*proto.DailyPlan {
+	var kept []*proto.DailyPlan
+	seen := map[string]bool{}
+
+	for _, dailyPlan := range plan {
+		if dailyPlan == nil {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", dailyPlan.Date)
+		if err != nil {
+			// Tarih yoksa gün numarasından türet
+			if dailyPlan.Day < 1 {
+				report.warn("a stop without date or day number was dropped")
+				continue
+			}
+			date = startDate.AddDate(0, 0, int(dailyPlan.Day)-1)
+		}
+
+		if date.Before(startDate) || date.After(endDate) {
+			report.warn("day %d (%s) is outside the trip dates and was dropped", dailyPlan.Day, date.Format("2006-01-02"))
+			continue
+		}
+
+		day := int32(date.Sub(startDate).Hours()/24) + 1
+		if dailyPlan.Day != day {
+			report.warn("day %d was renumbered to %d to match its date %s", dailyPlan.Day, day, date.Format("2006-01-02"))
+		}
+		dailyPlan.Day = day
+		dailyPlan.Date = date.Format("2006-01-02")
+
+		key := fmt.Sprintf("%d|%s", day, locationKey(dailyPlan.Location))
+		if seen[key] {
+			report.warn("duplicate stop on day %d was dropped", day)
+			continue
+		}
+		seen[key] = true
+
+		kept = append(kept, dailyPlan)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Day < kept[j].Day })
+
+	for day := int32(1); day <= int32(endDate.Sub(startDate).Hours()/24)+1; day++ {
+		if !hasDay(kept, day) {
+			report.warn("day %d has no stops", day)
+		}
+	}
+
+	return kept
+}
+
+func renumberSequentially(plan []*proto.DailyPlan) []*proto.DailyPlan {
+	var kept []*proto.DailyPlan
+	for _, dailyPlan := range plan {
+		if dailyPlan != nil {
+			kept = append(kept, dailyPlan)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Day < kept[j].Day })
+
+	day, previous := int32(0), int32(-1)
+	for _, dailyPlan := range kept {
+		if dailyPlan.Day != previous {
+			day++
+			previous = dailyPlan.Day
+		}
+		dailyPlan.Day = day
+	}
+	return kept
+}
+
+func validateLocation(dailyPlan *proto.DailyPlan, report *OptionReport) {
+	location := dailyPlan.Location
+
+	if strings.TrimSpace(location.Name) == "" {
+		report.warn("a stop on day %d has no name", dailyPlan.Day)
+	}
+
+	switch {
+	case location.Latitude == 0 && location.Longitude == 0:
+		report.warn("%q on day %d has no coordinates", location.Name, dailyPlan.Day)
+	case location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180:
+		report.warn("%q on day %d has out of range coordinates and they were cleared", location.Name, dailyPlan.Day)
+		location.Latitude, location.Longitude = 0, 0
+	}
+
+	if location.SiteUrl != "" && !validURL(location.SiteUrl) {
+		report.warn("invalid site_url %q of %q was removed", location.SiteUrl, location.Name)
+		location.SiteUrl = ""
+	}
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func locationKey(location *proto.Location) string {
+	if location == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s|%.5f|%.5f", strings.ToLower(strings.TrimSpace(location.Name)), location.Latitude, location.Longitude)
+}
+
+func hasDay(plan []*proto.DailyPlan, day int32) bool {
+	for _, dailyPlan := range plan {
+		if dailyPlan.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+func countDays(plan []*proto.DailyPlan) int {
+	days := map[int32]bool{}
+	for _, dailyPlan := range plan {
+		days[dailyPlan.Day] = true
+	}
+	return len(days)
+}
@@ -0,0 +1,299 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+func stop(day int32, date, name string, lat, lon float64) *proto.DailyPlan {
+	return &proto.DailyPlan{Day: day, Date: date, Location: &proto.Location{Name: name, Latitude: lat, Longitude: lon}}
+}
+
+func testRequest() *proto.PromptRequest {
+	return &proto.PromptRequest{
+		UserId:        "user-1",
+		Name:          "Ege turu",
+		StartPosition: "İzmir",
+		EndPosition:   "Bodrum",
+		StartDate:     "2026-06-01",
+		EndDate:       "2026-06-03",
+	}
+}
+
+// planSummary durakları "gün|tarih|ad" olarak sıralı yazar.
+func planSummary(plan []*proto.DailyPlan) []string {
+	var summary []string
+	for _, dailyPlan := range plan {
+		summary = append(summary, fmt.Sprintf("%d|%s|%s", dailyPlan.Day, dailyPlan.Date, dailyPlan.Location.Name))
+	}
+	return summary
+}
+
+func hasWarning(report OptionReport, text string) bool {
+	for _, warning := range report.Warnings {
+		if strings.Contains(warning, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateTripOptionsRepairsDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     []*proto.DailyPlan
+		want     []string
+		warnings []string
+	}{
+		{
+			name: "günler tarihe göre yeniden numaralandırılır ve sıralanır",
+			plan: []*proto.DailyPlan{
+				stop(5, "2026-06-02", "Efes", 37.9395, 27.3417),
+				stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+				stop(3, "2026-06-03", "Bodrum", 37.0344, 27.4305),
+			},
+			want:     []string{"1|2026-06-01|İzmir", "2|2026-06-02|Efes", "3|2026-06-03|Bodrum"},
+			warnings: []string{"day 5 was renumbered to 2"},
+		},
+		{
+			name: "tarihi olmayan durağın tarihi gün numarasından türetilir",
+			plan: []*proto.DailyPlan{
+				stop(1, "", "İzmir", 38.4237, 27.1428),
+				stop(2, "", "Efes", 37.9395, 27.3417),
+				stop(3, "not a date", "Bodrum", 37.0344, 27.4305),
+			},
+			want: []string{"1|2026-06-01|İzmir", "2|2026-06-02|Efes", "3|2026-06-03|Bodrum"},
+		},
+		{
+			name: "aralık dışındaki ve günü olmayan duraklar atılır",
+			plan: []*proto.DailyPlan{
+				stop(1, "2026-05-31", "Çeşme", 38.3236, 26.3029),
+				stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+				stop(0, "", "Selçuk", 37.9508, 27.3686),
+				stop(2, "2026-06-02", "Efes", 37.9395, 27.3417),
+				stop(4, "", "Marmaris", 36.8550, 28.2742),
+				stop(3, "2026-06-03", "Bodrum", 37.0344, 27.4305),
+			},
+			want: []string{"1|2026-06-01|İzmir", "2|2026-06-02|Efes", "3|2026-06-03|Bodrum"},
+			warnings: []string{
+				"(2026-05-31) is outside the trip dates",
+				"(2026-06-04) is outside the trip dates",
+				"a stop without date or day number was dropped",
+			},
+		},
+		{
+			name: "aynı gündeki tekrar atılır, başka gündeki kalır",
+			plan: []*proto.DailyPlan{
+				stop(1, "2026-06-01", "Efes", 37.9395, 27.3417),
+				stop(1, "2026-06-01", " efes ", 37.939501, 27.3417),
+				stop(2, "2026-06-02", "Efes", 37.9395, 27.3417),
+				stop(3, "2026-06-03", "Bodrum", 37.0344, 27.4305),
+			},
+			want:     []string{"1|2026-06-01|Efes", "2|2026-06-02|Efes", "3|2026-06-03|Bodrum"},
+			warnings: []string{"duplicate stop on day 1 was dropped"},
+		},
+		{
+			name: "durağı olmayan gün raporlanır",
+			plan: []*proto.DailyPlan{
+				stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+				stop(3, "2026-06-03", "Bodrum", 37.0344, 27.4305),
+			},
+			want:     []string{"1|2026-06-01|İzmir", "3|2026-06-03|Bodrum"},
+			warnings: []string{"day 2 has no stops"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{Theme: "Ege", DailyPlan: tt.plan}}}
+			reports, err := ValidateTripOptions(testRequest(), resp, nil)
+			if err != nil {
+				t.Fatalf("ValidateTripOptions: %v", err)
+			}
+
+			option := resp.TripOptions[0]
+			if got := planSummary(option.DailyPlan); strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("plan = %q, want %q", got, tt.want)
+			}
+			if option.Trip.TotalDays != 3 {
+				t.Errorf("total_days = %d, want 3", option.Trip.TotalDays)
+			}
+			for _, warning := range tt.warnings {
+				if !hasWarning(reports[0], warning) {
+					t.Errorf("warnings = %q, want one containing %q", reports[0].Warnings, warning)
+				}
+			}
+		})
+	}
+}
+
+// Tarihler geçersizse günler sadece sıralanıp 1'den ardışık numaralanır.
+func TestValidateTripOptionsInvalidRequestDates(t *testing.T) {
+	req := testRequest()
+	req.EndDate = "2026-05-01"
+	resp := &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{DailyPlan: []*proto.DailyPlan{
+		stop(7, "", "Bodrum", 37.0344, 27.4305),
+		stop(3, "", "İzmir", 38.4237, 27.1428),
+		nil,
+		stop(3, "", "Efes", 37.9395, 27.3417),
+	}}}}
+
+	reports, err := ValidateTripOptions(req, resp, nil)
+	if err != nil {
+		t.Fatalf("ValidateTripOptions: %v", err)
+	}
+
+	option := resp.TripOptions[0]
+	want := []string{"1||İzmir", "1||Efes", "2||Bodrum"}
+	if got := planSummary(option.DailyPlan); strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("plan = %q, want %q", got, want)
+	}
+	if option.Trip.TotalDays != 2 {
+		t.Errorf("total_days = %d, want 2", option.Trip.TotalDays)
+	}
+	if !hasWarning(reports[0], "request dates are invalid") {
+		t.Errorf("warnings = %q, want the invalid dates warning", reports[0].Warnings)
+	}
+}
+
+func TestValidateTripOptionsCopiesTripFromRequest(t *testing.T) {
+	resp := &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{
+		Trip: &proto.Trip{UserId: "someone-else", StartPosition: "Ankara", StartDate: "2026-06-01"},
+		DailyPlan: []*proto.DailyPlan{
+			stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+		},
+	}}}
+
+	reports, err := ValidateTripOptions(testRequest(), resp, nil)
+	if err != nil {
+		t.Fatalf("ValidateTripOptions: %v", err)
+	}
+
+	trip := resp.TripOptions[0].Trip
+	if trip.UserId != "user-1" || trip.Name != "Ege turu" || trip.StartPosition != "İzmir" || trip.EndPosition != "Bodrum" ||
+		trip.StartDate != "2026-06-01" || trip.EndDate != "2026-06-03" {
+		t.Errorf("trip = %+v, want the request's values", trip)
+	}
+	for _, warning := range []string{`user_id "someone-else" did not match`, `start_position "Ankara" did not match`} {
+		if !hasWarning(reports[0], warning) {
+			t.Errorf("warnings = %q, want one containing %q", reports[0].Warnings, warning)
+		}
+	}
+	// Boş alanların doldurulması uyarı değildir
+	if hasWarning(reports[0], "end_position") {
+		t.Errorf("warnings = %q, want no warning for the empty end_position", reports[0].Warnings)
+	}
+}
+
+func TestValidateTripOptionsRepairsLocations(t *testing.T) {
+	tests := []struct {
+		name     string
+		location *proto.Location
+		wantURL  string
+		wantLat  float64
+		warning  string
+	}{
+		{name: "geçerli https", location: &proto.Location{Name: "Efes", SiteUrl: "https://muze.gov.tr/efes", Latitude: 37.9395, Longitude: 27.3417},
+			wantURL: "https://muze.gov.tr/efes", wantLat: 37.9395},
+		{name: "şemasız", location: &proto.Location{Name: "Efes", SiteUrl: "muze.gov.tr/efes", Latitude: 37.9395, Longitude: 27.3417},
+			wantLat: 37.9395, warning: `invalid site_url "muze.gov.tr/efes"`},
+		{name: "http dışı şema", location: &proto.Location{Name: "Efes", SiteUrl: "javascript:alert(1)", Latitude: 37.9395, Longitude: 27.3417},
+			wantLat: 37.9395, warning: `invalid site_url "javascript:alert(1)"`},
+		{name: "hostsuz", location: &proto.Location{Name: "Efes", SiteUrl: "https://", Latitude: 37.9395, Longitude: 27.3417},
+			wantLat: 37.9395, warning: "invalid site_url"},
+		{name: "aralık dışı koordinat", location: &proto.Location{Name: "Efes", Latitude: 137.9, Longitude: 27.3417},
+			warning: "out of range coordinates and they were cleared"},
+		{name: "koordinatsız", location: &proto.Location{Name: "Efes"},
+			warning: `"Efes" on day 1 has no coordinates`},
+		{name: "adsız", location: &proto.Location{Latitude: 37.9395, Longitude: 27.3417},
+			wantLat: 37.9395, warning: "a stop on day 1 has no name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := tt.location
+			resp := &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{{DailyPlan: []*proto.DailyPlan{
+				{Day: 1, Date: "2026-06-01", Location: location},
+			}}}}
+			reports, err := ValidateTripOptions(testRequest(), resp, nil)
+			if err != nil {
+				t.Fatalf("ValidateTripOptions: %v", err)
+			}
+
+			if location.SiteUrl != tt.wantURL {
+				t.Errorf("site_url = %q, want %q", location.SiteUrl, tt.wantURL)
+			}
+			if location.Latitude != tt.wantLat {
+				t.Errorf("latitude = %v, want %v", location.Latitude, tt.wantLat)
+			}
+			if tt.warning != "" && !hasWarning(reports[0], tt.warning) {
+				t.Errorf("warnings = %q, want one containing %q", reports[0].Warnings, tt.warning)
+			}
+		})
+	}
+}
+
+func TestValidateTripOptionsHopeless(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *proto.TripOptionsResponse
+		reasons []string
+	}{
+		{name: "yanıt yok", resp: nil, reasons: []string{"response contains no trip options"}},
+		{name: "seçenek yok", resp: &proto.TripOptionsResponse{}, reasons: []string{"response contains no trip options"}},
+		{
+			name: "hiçbir seçenek kullanılamaz",
+			resp: &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{
+				nil,
+				{Theme: "Boş"},
+				{Theme: "Aralık dışı", DailyPlan: []*proto.DailyPlan{stop(9, "2026-07-01", "Efes", 37.9395, 27.3417)}},
+				{Theme: "Lokasyonsuz", DailyPlan: []*proto.DailyPlan{{Day: 1, Date: "2026-06-01"}}},
+			}},
+			reasons: []string{
+				"option 1 is empty",
+				`option 2 ("Boş") has no usable stops`,
+				`option 3 ("Aralık dışı") has no usable stops`,
+				`option 4 ("Lokasyonsuz") has no usable stops`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateTripOptions(testRequest(), tt.resp, nil)
+
+			var hopeless *HopelessResponseError
+			if !errors.As(err, &hopeless) {
+				t.Fatalf("err = %v, want *HopelessResponseError", err)
+			}
+			if strings.Join(hopeless.Reasons, "; ") != strings.Join(tt.reasons, "; ") {
+				t.Errorf("reasons = %q, want %q", hopeless.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+// Kullanılamayan seçenekler yanıttan çıkarılır, raporlar kalanlarla aynı
+// sırada döner.
+func TestValidateTripOptionsDropsUnusableOptions(t *testing.T) {
+	resp := &proto.TripOptionsResponse{TripOptions: []*proto.TripOption{
+		{Theme: "Boş"},
+		{Theme: "Tarih", DailyPlan: []*proto.DailyPlan{stop(1, "2026-06-01", "Efes", 37.9395, 27.3417)}},
+		{Theme: "Doğa", DailyPlan: []*proto.DailyPlan{stop(1, "2026-06-01", "Dilek Yarımadası", 37.6833, 27.1667)}},
+	}}
+
+	reports, err := ValidateTripOptions(testRequest(), resp, nil)
+	if err != nil {
+		t.Fatalf("ValidateTripOptions: %v", err)
+	}
+	if len(resp.TripOptions) != 2 || resp.TripOptions[0].Theme != "Tarih" || resp.TripOptions[1].Theme != "Doğa" {
+		t.Fatalf("options = %+v, want Tarih and Doğa", resp.TripOptions)
+	}
+	if len(reports) != 2 {
+		t.Errorf("reports = %d, want 2", len(reports))
+	}
+}
@@ -0,0 +1,182 @@
+// internal/validator/waypoints.go for trip-plan-service
+package validator
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+// waypointRadiusKm adı eşleşmeyen bir durağın yine de ara noktayı karşılamış
+// sayılması için ara noktaya olabileceği en uzak mesafedir.
+const waypointRadiusKm = 2
+
+// validateWaypoints her ara noktayı karşılayan durağı bulur ve planın ara
+// noktalara istenen sırayla uğramasını sağlar: sırası bozuk ara nokta
+// durakları kendi aralarında yer değiştirir (günler slotta kalır), planda
+// olmayan ama koordinatı bilinen ara noktalar komşu ara noktaların arasına en
+// az sapma yaratacak yere eklenir. Dönen eşleme DailyPlan indeksinden ara
+// noktanın 1'den başlayan sırasınadır.
+func validateWaypoints(option *proto.TripOption, waypoints []models.Waypoint, report *OptionReport) map[int]int {
+	plan := option.DailyPlan
+
+	// matched[k] k. ara noktayı karşılayan durağın indeksidir, yoksa -1
+	matched := make([]int, len(waypoints))
+	used := map[int]bool{}
+	for k, waypoint := range waypoints {
+		matched[k] = -1
+		for i, dailyPlan := range plan {
+			if dailyPlan.Location != nil && !used[i] && matchesWaypoint(dailyPlan.Location, waypoint) {
+				matched[k] = i
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	var slots []int
+	var locations []*proto.Location
+	for _, i := range matched {
+		if i >= 0 {
+			slots = append(slots, i)
+			locations = append(locations, plan[i].Location)
+		}
+	}
+	if !sort.IntsAreSorted(slots) {
+		sort.Ints(slots)
+		for j, i := range slots {
+			plan[i].Location = locations[j]
+		}
+		j := 0
+		for k := range matched {
+			if matched[k] >= 0 {
+				matched[k] = slots[j]
+				j++
+			}
+		}
+		report.warn("waypoints were visited out of order and were rearranged to follow the requested order")
+	}
+
+	for k, waypoint := range waypoints {
+		if matched[k] >= 0 {
+			continue
+		}
+		if waypoint.Point == nil {
+			report.warn("waypoint %d (%q) is missing from the plan", k+1, waypoint.Name)
+			continue
+		}
+
+		// Eklenecek yer, önceki ve sonraki eşleşmiş ara noktaların arasıdır
+		lo, hi := 0, len(plan)
+		for j := k - 1; j >= 0; j-- {
+			if matched[j] >= 0 {
+				lo = matched[j] + 1
+				break
+			}
+		}
+		for j := k + 1; j < len(waypoints); j++ {
+			if matched[j] >= 0 {
+				hi = matched[j]
+				break
+			}
+		}
+		at := cheapestInsertion(plan, lo, hi, *waypoint.Point)
+
+		neighbour := plan[len(plan)-1]
+		if at < len(plan) {
+			neighbour = plan[at]
+		}
+		if at > 0 {
+			neighbour = plan[at-1]
+		}
+		stop := &proto.DailyPlan{
+			Day:  neighbour.Day,
+			Date: neighbour.Date,
+			Location: &proto.Location{
+				Name:      waypoint.Name,
+				Latitude:  waypoint.Point.Latitude,
+				Longitude: waypoint.Point.Longitude,
+			},
+		}
+		plan = append(plan[:at], append([]*proto.DailyPlan{stop}, plan[at:]...)...)
+
+		for j := range matched {
+			if matched[j] >= at {
+				matched[j]++
+			}
+		}
+		matched[k] = at
+		report.warn("waypoint %d (%q) was missing and was added on day %d", k+1, waypoint.Name, stop.Day)
+	}
+	option.DailyPlan = plan
+
+	result := map[int]int{}
+	for k, i := range matched {
+		if i >= 0 {
+			result[i] = k + 1
+		}
+	}
+	return result
+}
+
+// matchesWaypoint durağın ara noktayı karşılayıp karşılamadığını söyler: ara
+// noktanın adı (virgülden önceki kısmı) durağın adında bütün kelimeler olarak
+// geçiyorsa ya da ikisi de koordinatlıysa ve waypointRadiusKm içindeyse.
+func matchesWaypoint(location *proto.Location, waypoint models.Waypoint) bool {
+	name := geo.NormalizeName(strings.Split(waypoint.Name, ",")[0])
+	stop := geo.NormalizeName(location.Name)
+	if name != "" && strings.Contains(" "+stop+" ", " "+name+" ") {
+		return true
+	}
+
+	point, ok := stopPoint(location)
+	return ok && waypoint.Point != nil && geo.HaversineKm(point, *waypoint.Point) <= waypointRadiusKm
+}
+
+// cheapestInsertion p noktasının plan[lo:hi] aralığında rotayı en az uzatacak
+// ekleme indeksini döner. Koordinatı olmayan duraklar atlanarak en yakın
+// koordinatlı komşular kullanılır.
+func cheapestInsertion(plan []*proto.DailyPlan, lo, hi int, p geo.Point) int {
+	best, bestCost := lo, math.Inf(1)
+	for at := lo; at <= hi; at++ {
+		previous, hasPrevious := neighbourPoint(plan, at-1, -1)
+		next, hasNext := neighbourPoint(plan, at, 1)
+
+		cost := 0.0
+		switch {
+		case hasPrevious && hasNext:
+			cost = geo.HaversineKm(previous, p) + geo.HaversineKm(p, next) - geo.HaversineKm(previous, next)
+		case hasPrevious:
+			cost = geo.HaversineKm(previous, p)
+		case hasNext:
+			cost = geo.HaversineKm(p, next)
+		}
+		if cost < bestCost-1e-9 {
+			best, bestCost = at, cost
+		}
+	}
+	return best
+}
+
+// neighbourPoint from indeksinden step yönünde ilk koordinatlı durağı bulur.
+func neighbourPoint(plan []*proto.DailyPlan, from, step int) (geo.Point, bool) {
+	for i := from; i >= 0 && i < len(plan); i += step {
+		if point, ok := stopPoint(plan[i].Location); ok {
+			return point, true
+		}
+	}
+	return geo.Point{}, false
+}
+
+func stopPoint(location *proto.Location) (geo.Point, bool) {
+	if location == nil {
+		return geo.Point{}, false
+	}
+	point := geo.Point{Latitude: location.Latitude, Longitude: location.Longitude}
+	return point, !point.IsZero()
+}
@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"trip-plan-service/internal/geo"
+	"trip-plan-service/internal/models"
+
+	"github.com/Semhumc/grpc-proto/proto"
+)
+
+func TestMatchesWaypoint(t *testing.T) {
+	kusadasi := &geo.Point{Latitude: 37.8579, Longitude: 27.2610}
+
+	tests := []struct {
+		name     string
+		stop     *proto.Location
+		waypoint models.Waypoint
+		want     bool
+	}{
+		{name: "ad virgülden önce eşleşir", stop: &proto.Location{Name: "Kuşadası Limanı"},
+			waypoint: models.Waypoint{Name: "Kusadasi, Aydın"}, want: true},
+		{name: "kelimenin parçası eşleşmez", stop: &proto.Location{Name: "Kuşadasıspor Stadı"},
+			waypoint: models.Waypoint{Name: "Kuşadası"}},
+		{name: "yarıçap içindeki durak eşleşir", stop: &proto.Location{Name: "Güvercinada", Latitude: 37.8625, Longitude: 27.2556},
+			waypoint: models.Waypoint{Name: "Liman", Point: kusadasi}, want: true},
+		{name: "yarıçap dışındaki durak eşleşmez", stop: &proto.Location{Name: "Efes", Latitude: 37.9395, Longitude: 27.3417},
+			waypoint: models.Waypoint{Name: "Liman", Point: kusadasi}},
+		{name: "koordinatsız durak mesafeyle eşleşmez", stop: &proto.Location{Name: "Merkez"},
+			waypoint: models.Waypoint{Name: "Liman", Point: &geo.Point{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesWaypoint(tt.stop, tt.waypoint); got != tt.want {
+				t.Errorf("matchesWaypoint = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// Sırası bozuk ara nokta durakları yer değiştirir; günler slotlarında kalır.
+func TestValidateWaypointsReordersMatchedStops(t *testing.T) {
+	option := &proto.TripOption{DailyPlan: []*proto.DailyPlan{
+		stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+		stop(1, "2026-06-01", "Bodrum Kalesi", 37.0317, 27.4286),
+		stop(2, "2026-06-02", "Kuşadası Limanı", 37.8579, 27.2610),
+	}}
+	waypoints := []models.Waypoint{{Name: "Kuşadası"}, {Name: "Bodrum"}}
+
+	var report OptionReport
+	got := validateWaypoints(option, waypoints, &report)
+
+	want := []string{"1|2026-06-01|İzmir", "1|2026-06-01|Kuşadası Limanı", "2|2026-06-02|Bodrum Kalesi"}
+	if summary := planSummary(option.DailyPlan); strings.Join(summary, ", ") != strings.Join(want, ", ") {
+		t.Errorf("plan = %q, want %q", summary, want)
+	}
+	if want := map[int]int{1: 1, 2: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("waypoints = %v, want %v", got, want)
+	}
+	if !hasWarning(report, "out of order") {
+		t.Errorf("warnings = %q, want the reorder warning", report.Warnings)
+	}
+}
+
+// Planda olmayan ara nokta rotayı en az uzatan yere, komşusunun gününe eklenir.
+func TestValidateWaypointsInsertsMissingWaypoint(t *testing.T) {
+	option := &proto.TripOption{DailyPlan: []*proto.DailyPlan{
+		stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+		stop(1, "2026-06-01", "Efes", 37.9395, 27.3417),
+		stop(2, "2026-06-02", "Bodrum", 37.0344, 27.4305),
+	}}
+	waypoints := []models.Waypoint{{Name: "Didim", Point: &geo.Point{Latitude: 37.3756, Longitude: 27.2683}}}
+
+	var report OptionReport
+	got := validateWaypoints(option, waypoints, &report)
+
+	want := []string{"1|2026-06-01|İzmir", "1|2026-06-01|Efes", "1|2026-06-01|Didim", "2|2026-06-02|Bodrum"}
+	if summary := planSummary(option.DailyPlan); strings.Join(summary, ", ") != strings.Join(want, ", ") {
+		t.Errorf("plan = %q, want %q", summary, want)
+	}
+	if want := map[int]int{2: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("waypoints = %v, want %v", got, want)
+	}
+	if !hasWarning(report, `waypoint 1 ("Didim") was missing and was added on day 1`) {
+		t.Errorf("warnings = %q, want the insertion warning", report.Warnings)
+	}
+}
+
+// Eklenecek yer komşu ara noktaların arasıyla sınırlıdır; daha kısa olsa da
+// önceki ara noktanın önüne eklenmez.
+func TestValidateWaypointsInsertsBetweenNeighbours(t *testing.T) {
+	option := &proto.TripOption{DailyPlan: []*proto.DailyPlan{
+		stop(1, "2026-06-01", "Selçuk", 37.9508, 27.3686),
+		stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+		stop(2, "2026-06-02", "Bodrum", 37.0344, 27.4305),
+	}}
+	waypoints := []models.Waypoint{
+		{Name: "İzmir"},
+		{Name: "Efes", Point: &geo.Point{Latitude: 37.9395, Longitude: 27.3417}},
+	}
+
+	var report OptionReport
+	got := validateWaypoints(option, waypoints, &report)
+
+	want := []string{"1|2026-06-01|Selçuk", "1|2026-06-01|İzmir", "1|2026-06-01|Efes", "2|2026-06-02|Bodrum"}
+	if summary := planSummary(option.DailyPlan); strings.Join(summary, ", ") != strings.Join(want, ", ") {
+		t.Errorf("plan = %q, want %q", summary, want)
+	}
+	if want := map[int]int{1: 1, 2: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("waypoints = %v, want %v", got, want)
+	}
+}
+
+func TestValidateWaypointsReportsMissingWithoutPoint(t *testing.T) {
+	option := &proto.TripOption{DailyPlan: []*proto.DailyPlan{
+		stop(1, "2026-06-01", "İzmir", 38.4237, 27.1428),
+	}}
+
+	var report OptionReport
+	got := validateWaypoints(option, []models.Waypoint{{Name: "Didim"}}, &report)
+
+	if len(option.DailyPlan) != 1 || len(got) != 0 {
+		t.Errorf("plan = %q, waypoints = %v; want the plan unchanged", planSummary(option.DailyPlan), got)
+	}
+	if !hasWarning(report, `waypoint 1 ("Didim") is missing from the plan`) {
+		t.Errorf("warnings = %q, want the missing waypoint warning", report.Warnings)
+	}
+}
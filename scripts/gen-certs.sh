@@ -0,0 +1,27 @@
+#!/bin/sh
+# Yerel geliştirme ve sahte AI sunucusu ile denemek için test sertifikaları üretir.
+# Kullanım: ./scripts/gen-certs.sh [hedef_klasör]
+set -e
+
+OUT=${1:-certs}
+DAYS=365
+mkdir -p "$OUT"
+
+# Test CA
+openssl req -x509 -newkey rsa:2048 -nodes -days "$DAYS" \
+	-keyout "$OUT/ca.key" -out "$OUT/ca.crt" -subj "/CN=trip-plan-test-ca"
+
+# AI sunucu sertifikası (localhost ve docker host adı için)
+openssl req -newkey rsa:2048 -nodes -keyout "$OUT/server.key" -out "$OUT/server.csr" -subj "/CN=localhost"
+printf "subjectAltName=DNS:localhost,DNS:host.docker.internal,IP:127.0.0.1\nextendedKeyUsage=serverAuth\n" > "$OUT/server.ext"
+openssl x509 -req -in "$OUT/server.csr" -CA "$OUT/ca.crt" -CAkey "$OUT/ca.key" -CAcreateserial \
+	-days "$DAYS" -extfile "$OUT/server.ext" -out "$OUT/server.crt"
+
+# trip-plan-service istemci sertifikası (mTLS)
+openssl req -newkey rsa:2048 -nodes -keyout "$OUT/client.key" -out "$OUT/client.csr" -subj "/CN=trip-plan-service"
+printf "extendedKeyUsage=clientAuth\n" > "$OUT/client.ext"
+openssl x509 -req -in "$OUT/client.csr" -CA "$OUT/ca.crt" -CAkey "$OUT/ca.key" -CAcreateserial \
+	-days "$DAYS" -extfile "$OUT/client.ext" -out "$OUT/client.crt"
+
+rm -f "$OUT"/*.csr "$OUT"/*.ext "$OUT"/*.srl
+echo "Sertifikalar $OUT klasörüne yazıldı."